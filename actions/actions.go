@@ -45,6 +45,10 @@ var ExtraTuningSheets = "/etc/saptune/extra/"
 // SolutionSheets is the working directory of available sap solutions
 var SolutionSheets = "/var/lib/saptune/working/sols/"
 
+// BaselineArea is the directory of the baselines saved by
+// 'saptune baseline save'
+var BaselineArea = "/var/lib/saptune/baseline/"
+
 // RPMVersion is the package version from package build process
 var RPMVersion = "undef"
 
@@ -94,12 +98,25 @@ func SelectAction(writer io.Writer, stApp *app.App, saptuneVers string) {
 		NoteAction(writer, system.CliArg(2), system.CliArg(3), system.CliArg(4), stApp)
 	case "solution":
 		SolutionAction(writer, system.CliArg(2), system.CliArg(3), system.CliArg(4), stApp)
+	case "parameter":
+		ParameterAction(writer, system.CliArg(2), system.CliArg(3), stApp)
 	case "revert":
 		RevertAction(writer, system.CliArg(2), stApp)
+	case "configure":
+		ConfigureAction(writer, stApp)
+	case "config":
+		ConfigAction(os.Stdin, writer, system.CliArg(2), system.CliArg(3), saptuneVers, stApp)
 	case "staging":
 		StagingAction(system.CliArg(2), system.CliArgs(3), stApp)
 	case "status":
 		ServiceAction(writer, "status", saptuneVers, stApp)
+	case "verify":
+		if system.CliArg(2) != "" {
+			PrintHelpAndExit(writer, 1)
+		}
+		VerifyAllParameters(writer, stApp)
+	case "baseline":
+		BaselineAction(writer, system.CliArg(2), system.CliArg(3), stApp)
 	default:
 		PrintHelpAndExit(writer, 1)
 	}
@@ -148,13 +165,13 @@ func VerifyAllParameters(writer io.Writer, tuneApp *app.App) {
 		if err != nil {
 			system.ErrorExit("Failed to inspect the current system: %v", err)
 		}
-		PrintNoteFields(writer, "NONE", comparisons, true, &result)
+		comparisons, sysComp := filterVerifyResult(writer, comparisons, len(unsatisfiedNotes) == 0, tuneApp)
+		printVerifyResult(writer, "NONE", comparisons, tuneApp, &result)
 		tuneApp.PrintNoteApplyOrder(writer)
 		result.NotesOrder = tuneApp.NoteApplyOrder
-		sysComp := len(unsatisfiedNotes) == 0
 		result.SysCompliance = &sysComp
 		system.Jcollect(result)
-		if len(unsatisfiedNotes) == 0 {
+		if sysComp {
 			fmt.Fprintf(writer, "%s%sThe running system is currently well-tuned according to all of the enabled notes.%s%s\n", setGreenText, setBoldText, resetBoldText, resetTextColor)
 		} else {
 			system.ErrorExit("The parameters listed above have deviated from SAP/SUSE recommendations.", "colorPrint", setRedText, setBoldText, resetBoldText, resetTextColor)
@@ -267,41 +284,34 @@ func switchOffColor() {
 	}
 }
 
-// PrintHelpAndExit prints the usage and exit
+// PrintCmdHelpAndExit prints the usage of the realm or command given on the
+// command line and exit. Without a known realm the complete usage is printed
+func PrintCmdHelpAndExit(writer io.Writer, exitStatus int) {
+	usage := system.CmdUsage(system.CliArgs(1))
+	if usage == "" || system.CliArg(1) == "help" {
+		PrintHelpAndExit(writer, exitStatus)
+		return
+	}
+	if system.GetFlagVal("format") == "json" {
+		system.JInvalid(exitStatus)
+	}
+	if system.IsReportFormat() {
+		// stdout is reserved for the report
+		writer = os.Stderr
+	}
+	fmt.Fprint(writer, usage)
+	system.ErrorExit("", exitStatus)
+}
+
+// PrintHelpAndExit prints the usage generated from the command tree and exit
 func PrintHelpAndExit(writer io.Writer, exitStatus int) {
 	if system.GetFlagVal("format") == "json" {
 		system.JInvalid(exitStatus)
 	}
-	fmt.Fprintln(writer, `saptune: Comprehensive system optimisation management for SAP solutions.
-Daemon control:
-  saptune daemon [ start | stop ]                 ATTENTION: deprecated
-  saptune daemon status [--non-compliance-check]  ATTENTION: deprecated
-  saptune service [ start | stop | restart | takeover | enable | disable | enablestart | disablestop ]
-  saptune service status [--non-compliance-check]
-Tune system according to SAP and SUSE notes:
-  saptune note [ list | revertall | enabled | applied ]
-  saptune note [ apply | simulate | customise | create | edit | revert | show | delete ] NoteID
-  saptune note verify [--colorscheme=<color scheme>] [--show-non-compliant] [NoteID]
-  saptune note rename NoteID newNoteID
-Tune system for all notes applicable to your SAP solution:
-  saptune solution [ list | verify | enabled | applied ]
-  saptune solution [ apply | simulate | verify | customise | create | edit | revert | show | delete ] SolutionName
-  saptune solution rename SolutionName newSolutionName
-Staging control:
-   saptune staging [ status | enable | disable | is-enabled | list | diff | analysis | release ]
-   saptune staging [ analysis | diff ] [ NoteID... | SolutionID... | all ]
-   saptune staging release [--force|--dry-run] [ NoteID... | SolutionID... | all ]
-Revert all parameters tuned by the SAP notes or solutions:
-  saptune revert all
-Remove the pending lock file from a former saptune call
-  saptune lock remove
-Call external script '/usr/sbin/saptune_check'
-  saptune check
-Print current saptune status:
-  saptune status [--non-compliance-check]
-Print current saptune version:
-  saptune version
-Print this message:
-  saptune help`)
+	if system.IsReportFormat() {
+		// stdout is reserved for the report
+		writer = os.Stderr
+	}
+	fmt.Fprint(writer, system.MainUsage())
 	system.ErrorExit("", exitStatus)
 }
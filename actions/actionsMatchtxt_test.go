@@ -48,6 +48,7 @@ staged Solutions:
 
 sapconf.service:          not available
 tuned.service:            disabled/active (profile: '%s')
+conflicts:                none
 systemd system state:     running
 virtualization:           %s
 tuning:                   not tuned
@@ -72,6 +73,7 @@ staged Solutions:
 
 sapconf.service:          not available
 tuned.service:            disabled/active (profile: '%s')
+conflicts:                none
 systemd system state:     running
 virtualization:           %s
 tuning:                   not tuned
@@ -82,35 +84,6 @@ Your system has not yet been tuned. Please visit `+"`"+`saptune note`+"`"+` and
 
 `, system.GetTunedAdmProfile(), system.GetVirtStatus())
 
-var PrintHelpAndExitMatchText = `saptune: Comprehensive system optimisation management for SAP solutions.
-Daemon control:
-  saptune daemon [ start | stop ]                 ATTENTION: deprecated
-  saptune daemon status [--non-compliance-check]  ATTENTION: deprecated
-  saptune service [ start | stop | restart | takeover | enable | disable | enablestart | disablestop ]
-  saptune service status [--non-compliance-check]
-Tune system according to SAP and SUSE notes:
-  saptune note [ list | revertall | enabled | applied ]
-  saptune note [ apply | simulate | customise | create | edit | revert | show | delete ] NoteID
-  saptune note verify [--colorscheme=<color scheme>] [--show-non-compliant] [NoteID]
-  saptune note rename NoteID newNoteID
-Tune system for all notes applicable to your SAP solution:
-  saptune solution [ list | verify | enabled | applied ]
-  saptune solution [ apply | simulate | verify | customise | create | edit | revert | show | delete ] SolutionName
-  saptune solution rename SolutionName newSolutionName
-Staging control:
-   saptune staging [ status | enable | disable | is-enabled | list | diff | analysis | release ]
-   saptune staging [ analysis | diff ] [ NoteID... | SolutionID... | all ]
-   saptune staging release [--force|--dry-run] [ NoteID... | SolutionID... | all ]
-Revert all parameters tuned by the SAP notes or solutions:
-  saptune revert all
-Remove the pending lock file from a former saptune call
-  saptune lock remove
-Call external script '/usr/sbin/saptune_check'
-  saptune check
-Print current saptune status:
-  saptune status [--non-compliance-check]
-Print current saptune version:
-  saptune version
-Print this message:
-  saptune help
-`
+// the usage is generated from the command tree, its content is checked
+// by TestMainUsage
+var PrintHelpAndExitMatchText = system.MainUsage()
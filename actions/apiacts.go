@@ -0,0 +1,265 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/sap/solution"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// API setting in /etc/sysconfig/saptune
+const apiSocketKey = "API_SOCKET"
+
+// apiLockWait is the default time an API request waits for the saptune
+// lock, can be changed per request by the query parameter 'wait'
+const apiLockWait = "5m"
+
+// isAPIObject matches the supported Note IDs, Solution names and staging
+// objects in the request path. They must not look like an option
+var isAPIObject = regexp.MustCompile(`^[\w.+:][\w.+:-]*$`)
+
+// apiRoute maps a request to the saptune command line. '{id}' in the path
+// is a Note ID, Solution name or staging object
+type apiRoute struct {
+	method string
+	path   string
+	args   []string
+}
+
+// apiRoutes are the operations of the saptune API. The result is the json
+// output of the corresponding saptune command
+var apiRoutes = []apiRoute{
+	{http.MethodGet, "/v1/status", []string{"status"}},
+	{http.MethodGet, "/v1/verify", []string{"verify"}},
+	{http.MethodGet, "/v1/notes", []string{"note", "list"}},
+	{http.MethodGet, "/v1/notes/enabled", []string{"note", "enabled"}},
+	{http.MethodGet, "/v1/notes/applied", []string{"note", "applied"}},
+	{http.MethodGet, "/v1/notes/{id}/verify", []string{"note", "verify", "{id}"}},
+	{http.MethodGet, "/v1/notes/{id}/simulate", []string{"note", "simulate", "{id}"}},
+	{http.MethodPost, "/v1/notes/{id}/apply", []string{"note", "apply", "{id}"}},
+	{http.MethodPost, "/v1/notes/{id}/revert", []string{"note", "revert", "{id}"}},
+	{http.MethodGet, "/v1/solutions", []string{"solution", "list"}},
+	{http.MethodGet, "/v1/solutions/enabled", []string{"solution", "enabled"}},
+	{http.MethodGet, "/v1/solutions/applied", []string{"solution", "applied"}},
+	{http.MethodGet, "/v1/solutions/{id}/verify", []string{"solution", "verify", "{id}"}},
+	{http.MethodGet, "/v1/solutions/{id}/simulate", []string{"solution", "simulate", "{id}"}},
+	{http.MethodPost, "/v1/solutions/{id}/apply", []string{"solution", "apply", "{id}"}},
+	{http.MethodPost, "/v1/solutions/{id}/revert", []string{"solution", "revert", "{id}"}},
+	{http.MethodPost, "/v1/revert", []string{"revert", "all"}},
+	{http.MethodGet, "/v1/staging", []string{"staging", "status"}},
+	{http.MethodGet, "/v1/staging/list", []string{"staging", "list"}},
+	{http.MethodPost, "/v1/staging/{id}/release", []string{"staging", "release", "--force", "{id}"}},
+}
+
+// apiRun runs the saptune command of a request within the saptune service
+// and returns the collected json entry. The commands run one after the
+// other and take the saptune lock exactly like the command line, an error
+// exit of a command does not terminate the service
+func apiRun(args []string, saptuneVers string) system.JEntry {
+	return system.JRun(args, func() { apiAction(saptuneVers) })
+}
+
+// apiAction prepares and runs a saptune command like main does for the
+// command line. The application is initialised for each command, as the
+// configuration may be changed by saptune calls outside of the service
+func apiAction(saptuneVers string) {
+	if !system.ChkCliSyntax() {
+		PrintHelpAndExit(ioutil.Discard, 1)
+	}
+	system.SaptuneLock(system.CmdLockMode(system.CliArgs(1)), system.LockWait())
+	if system.HoldsExclusiveLock() {
+		// cleanup runtime files
+		system.CleanUpRun()
+	}
+	solution.Refresh()
+	archSolutions, exist := solution.AllSolutions[solutionSelector]
+	if !exist {
+		system.ErrorExit("The system architecture (%s) is not supported.", solutionSelector)
+	}
+	tuneApp := app.InitialiseApp("", "", note.GetTuningOptions(NoteTuningSheets, ExtraTuningSheets), archSolutions)
+	if system.HoldsExclusiveLock() {
+		if err := tuneApp.NoteSanityCheck(); err != nil {
+			system.ErrorExit("Error during NoteSanityCheck - '%v'\n", err)
+		}
+	}
+	SelectAction(ioutil.Discard, tuneApp, saptuneVers)
+}
+
+// ServiceActionAPI is only used by the saptune service after tuning the
+// system, hence it is not advertised to the end user.
+// It tells systemd, that the service is started, and serves the local REST
+// API on the root-only Unix socket configured in the saptune configuration
+// file for the whole lifetime of the service.
+// Problems of the API are only logged, as an error exit of the service
+// would revert the tuning
+func ServiceActionAPI(saptuneVers string) {
+	if !system.IsNotifyService() {
+		// 'saptune service apply' called from the command line
+		return
+	}
+	if err := system.SdNotify("READY=1"); err != nil {
+		system.WarningLog("%v", err)
+	}
+	socket := getAPISocket(saptuneSysconfig)
+	if socket == "" {
+		system.InfoLog("saptune API disabled (%s is not set in '%s')", apiSocketKey, saptuneSysconfig)
+		return
+	}
+	// the saptune lock is taken by each single request
+	system.ReleaseSaptuneLock()
+	listener, err := apiListen(socket)
+	if err != nil {
+		system.ErrorLog("saptune API not available - %v", err)
+		return
+	}
+	server := &http.Server{Handler: apiHandler(func(args []string) system.JEntry { return apiRun(args, saptuneVers) })}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+	system.NoticeLog("saptune API listening on '%s'", socket)
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		system.ErrorLog("saptune API failed - %v", err)
+	}
+	os.Remove(socket)
+	system.NoticeLog("saptune API stopped")
+}
+
+// getAPISocket reads the path of the API socket from the saptune
+// configuration file. An empty path disables the API
+func getAPISocket(sysconfig string) string {
+	sconf, err := txtparser.ParseSysconfigFile(sysconfig, false)
+	if err != nil {
+		return ""
+	}
+	return sconf.GetString(apiSocketKey, "")
+}
+
+// apiListen creates the Unix socket of the API, which is only accessible
+// by root. A socket left over by a former run is removed
+func apiListen(socket string) (net.Listener, error) {
+	if err := os.MkdirAll(path.Dir(socket), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create the directory of the API socket '%s' - %v", socket, err)
+	}
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove the old API socket '%s' - %v", socket, err)
+	}
+	oldMask := syscall.Umask(0177)
+	listener, err := net.Listen("unix", socket)
+	syscall.Umask(oldMask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the API socket '%s' - %v", socket, err)
+	}
+	if err := os.Chmod(socket, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict the access to the API socket '%s' - %v", socket, err)
+	}
+	return listener, nil
+}
+
+// apiCommand returns the saptune arguments of a request and the http status,
+// if the request does not match an API route
+func apiCommand(method, reqPath string) ([]string, int) {
+	status := http.StatusNotFound
+	reqFields := strings.Split(strings.Trim(reqPath, "/"), "/")
+	for _, route := range apiRoutes {
+		routeFields := strings.Split(strings.Trim(route.path, "/"), "/")
+		if len(routeFields) != len(reqFields) {
+			continue
+		}
+		object := ""
+		hasObject := false
+		match := true
+		for cnt, field := range routeFields {
+			if field == "{id}" {
+				object = reqFields[cnt]
+				hasObject = true
+			} else if field != reqFields[cnt] {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		if hasObject && !isAPIObject.MatchString(object) {
+			return nil, http.StatusBadRequest
+		}
+		if route.method != method {
+			status = http.StatusMethodNotAllowed
+			continue
+		}
+		args := []string{}
+		for _, arg := range route.args {
+			args = append(args, strings.Replace(arg, "{id}", object, 1))
+		}
+		return args, http.StatusOK
+	}
+	return nil, status
+}
+
+// apiHandler runs the saptune command of a request by 'run' and returns
+// its json output. The command is not bound to the request, so a client
+// closing the connection does not abort e.g. a running apply
+func apiHandler(run func([]string) system.JEntry) http.HandlerFunc {
+	return func(writer http.ResponseWriter, req *http.Request) {
+		args, status := apiCommand(req.Method, req.URL.Path)
+		if status != http.StatusOK {
+			apiError(writer, status, fmt.Sprintf("unsupported request '%s %s'", req.Method, req.URL.Path))
+			return
+		}
+		wait := req.URL.Query().Get("wait")
+		if wait == "" {
+			wait = apiLockWait
+		}
+		cmdArgs := append([]string{"--wait=" + wait}, args...)
+		system.InfoLog("saptune API request '%s %s' runs 'saptune %s'", req.Method, req.URL.Path, strings.Join(cmdArgs, " "))
+		entry := run(cmdArgs)
+		data, err := json.Marshal(entry)
+		if err != nil {
+			apiError(writer, http.StatusInternalServerError, fmt.Sprintf("problems writing the result of 'saptune %s' - %v", strings.Join(cmdArgs, " "), err))
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(apiStatus(entry.CmdRet))
+		_, _ = writer.Write(data)
+	}
+}
+
+// apiStatus returns the http status for the exit code of a command.
+// Exit code 11 means, saptune is in use by another saptune call
+func apiStatus(exit int) int {
+	switch exit {
+	case 0:
+		return http.StatusOK
+	case 11:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// apiError returns an error message as json
+func apiError(writer http.ResponseWriter, status int, msg string) {
+	system.WarningLog("saptune API: %s", msg)
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(system.JMsg{Prio: "ERROR", Txt: msg})
+}
@@ -0,0 +1,199 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/SUSE/saptune/system"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPICommand(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		args   []string
+		status int
+	}{
+		{http.MethodGet, "/v1/status", []string{"status"}, http.StatusOK},
+		{http.MethodGet, "/v1/notes/", []string{"note", "list"}, http.StatusOK},
+		{http.MethodGet, "/v1/notes/enabled", []string{"note", "enabled"}, http.StatusOK},
+		{http.MethodGet, "/v1/notes/1680803/verify", []string{"note", "verify", "1680803"}, http.StatusOK},
+		{http.MethodPost, "/v1/notes/SAP_BOBJ/apply", []string{"note", "apply", "SAP_BOBJ"}, http.StatusOK},
+		{http.MethodPost, "/v1/solutions/S4HANA-APP+DB/apply", []string{"solution", "apply", "S4HANA-APP+DB"}, http.StatusOK},
+		{http.MethodPost, "/v1/staging/all/release", []string{"staging", "release", "--force", "all"}, http.StatusOK},
+		{http.MethodPost, "/v1/revert", []string{"revert", "all"}, http.StatusOK},
+		{http.MethodGet, "/v1/notes/1680803/apply", nil, http.StatusMethodNotAllowed},
+		{http.MethodPost, "/v1/notes", nil, http.StatusMethodNotAllowed},
+		{http.MethodPost, "/v1/notes/--force/apply", nil, http.StatusBadRequest},
+		{http.MethodPost, "/v1/notes//apply", nil, http.StatusBadRequest},
+		{http.MethodGet, "/v1/notes/1680803/delete", nil, http.StatusNotFound},
+		{http.MethodGet, "/v2/status", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		args, status := apiCommand(tt.method, tt.path)
+		if status != tt.status || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("'%s %s': got '%v' - '%d', expected '%v' - '%d'", tt.method, tt.path, args, status, tt.args, tt.status)
+		}
+	}
+}
+
+func TestAPIHandler(t *testing.T) {
+	var gotArgs []string
+	handler := apiHandler(func(args []string) system.JEntry {
+		gotArgs = args
+		exit := 0
+		switch args[len(args)-1] {
+		case "busy":
+			exit = 11
+		case "deviated":
+			exit = 1
+		}
+		return system.JEntry{Cmd: args[1] + " " + args[2], CmdRet: exit, CmdMsg: []system.JMsg{}}
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/v1/notes/1680803/verify", nil))
+	entry := system.JEntry{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &entry); rec.Code != http.StatusOK || err != nil || entry.Cmd != "note verify" || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("wrong response '%d' - '%s'", rec.Code, rec.Body.String())
+	}
+	if exp := []string{"--wait=" + apiLockWait, "note", "verify", "1680803"}; !reflect.DeepEqual(gotArgs, exp) {
+		t.Errorf("got '%v', expected '%v'", gotArgs, exp)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/v1/notes/1680803/apply?wait=30s", nil))
+	if exp := []string{"--wait=30s", "note", "apply", "1680803"}; !reflect.DeepEqual(gotArgs, exp) {
+		t.Errorf("got '%v', expected '%v'", gotArgs, exp)
+	}
+
+	// a failing command returns its json output with a non 2xx status
+	for req, status := range map[string]int{"/v1/notes/deviated/verify": http.StatusUnprocessableEntity, "/v1/notes/busy/verify": http.StatusServiceUnavailable} {
+		rec = httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, req, nil))
+		entry := system.JEntry{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &entry); rec.Code != status || err != nil || entry.Cmd != "note verify" || entry.CmdRet == 0 {
+			t.Errorf("'%s': wrong response '%d' - '%s'", req, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/v1/unknown", nil))
+	msg := system.JMsg{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &msg); rec.Code != http.StatusNotFound || err != nil || msg.Prio != "ERROR" {
+		t.Errorf("wrong error response '%d' - '%s'", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAPIStatus(t *testing.T) {
+	for exit, status := range map[int]int{0: http.StatusOK, 1: http.StatusUnprocessableEntity, 4: http.StatusUnprocessableEntity, 11: http.StatusServiceUnavailable} {
+		if got := apiStatus(exit); got != status {
+			t.Errorf("exit code '%d': got '%d', expected '%d'", exit, got, status)
+		}
+	}
+}
+
+func TestAPIRun(t *testing.T) {
+	oldOSExit := system.OSExit
+	defer func() { system.OSExit = oldOSExit }()
+	system.OSExit = tstosExit
+	oldErrorExitOut := system.ErrorExitOut
+	defer func() { system.ErrorExitOut = oldErrorExitOut }()
+	system.ErrorExitOut = tstErrorExitOut
+	tstwriter = &bytes.Buffer{}
+
+	// the command runs within the process, a syntax error ends the
+	// command, but not the process
+	entry := apiRun([]string{"--wait=1s", "note", "verify", "--unknown"}, "3")
+	if entry.Cmd != "invalid" || entry.CmdRet != 1 {
+		t.Errorf("wrong json entry '%+v'", entry)
+	}
+	if apiStatus(entry.CmdRet) == http.StatusOK {
+		t.Errorf("failed command answered with status OK")
+	}
+}
+
+func TestAPIListen(t *testing.T) {
+	tstDir := t.TempDir()
+	socket := path.Join(tstDir, "run", "api.sock")
+	// left over socket of a former run
+	_ = os.MkdirAll(path.Dir(socket), 0700)
+	_ = ioutil.WriteFile(socket, []byte{}, 0644)
+
+	listener, err := apiListen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: apiHandler(func(args []string) system.JEntry {
+		return system.JEntry{Cmd: "status", CmdMsg: []system.JMsg{}}
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+	if info, err := os.Stat(socket); err != nil || info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0600 {
+		t.Errorf("wrong API socket '%v' - '%v'", info, err)
+	}
+
+	client := &http.Client{Transport: &http.Transport{DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+	}}}
+	resp, err := client.Get("http://saptune/v1/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"command":"status"`) {
+		t.Errorf("wrong response '%d' - '%s'", resp.StatusCode, string(body))
+	}
+}
+
+func TestGetAPISocket(t *testing.T) {
+	sysconf := path.Join(t.TempDir(), "saptune")
+	if socket := getAPISocket(sysconf); socket != "" {
+		t.Errorf("got '%s' for a missing file", socket)
+	}
+	_ = ioutil.WriteFile(sysconf, []byte("STAGING=\"false\"\nAPI_SOCKET=\"/run/saptune/api.sock\"\n"), 0644)
+	if socket := getAPISocket(sysconf); socket != "/run/saptune/api.sock" {
+		t.Errorf("got '%s', expected '/run/saptune/api.sock'", socket)
+	}
+}
+
+func TestServiceActionAPI(t *testing.T) {
+	oldSysconfig := saptuneSysconfig
+	defer func() { saptuneSysconfig = oldSysconfig }()
+	saptuneSysconfig = path.Join(t.TempDir(), "saptune")
+	_ = ioutil.WriteFile(saptuneSysconfig, []byte("STAGING=\"false\"\nAPI_SOCKET=\"\"\n"), 0644)
+	notify := path.Join(t.TempDir(), "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: notify, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	buf := make([]byte, 64)
+
+	// called from the command line, no notification
+	t.Setenv("NOTIFY_SOCKET", "")
+	ServiceActionAPI("3")
+	_ = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if n, err := conn.Read(buf); err == nil {
+		t.Errorf("unexpected notification '%s'", string(buf[:n]))
+	}
+
+	// started by the saptune service with disabled API
+	t.Setenv("NOTIFY_SOCKET", notify)
+	ServiceActionAPI("3")
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if n, err := conn.Read(buf); err != nil || string(buf[:n]) != "READY=1" {
+		t.Errorf("got '%s' - '%v', expected 'READY=1'", string(buf[:n]), err)
+	}
+}
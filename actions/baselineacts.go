@@ -0,0 +1,202 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dfltBaseline is the name of the baseline, if no name is given
+const dfltBaseline = "default"
+
+// isBaselineName matches the supported baseline names, which are used as
+// file names in BaselineArea
+var isBaselineName = regexp.MustCompile(`^[\w.-]+$`)
+
+// change of a parameter since the baseline was saved
+const (
+	baselineChanged = "changed"
+	baselineAdded   = "added"
+	baselineRemoved = "removed"
+)
+
+// BaselineAction handles baseline actions like save and check
+func BaselineAction(writer io.Writer, actionName, name string, tuneApp *app.App) {
+	if name == "" {
+		name = dfltBaseline
+	}
+	if actionName == "save" || actionName == "check" {
+		if !isBaselineName.MatchString(name) || strings.HasPrefix(name, ".") {
+			system.ErrorExit("wrong baseline name '%s', only letters, digits, '_', '-' and '.' are supported", name)
+		}
+	}
+	switch actionName {
+	case "save":
+		BaselineActionSave(writer, name, tuneApp)
+	case "check":
+		BaselineActionCheck(writer, name, tuneApp)
+	default:
+		PrintHelpAndExit(writer, 1)
+	}
+}
+
+// BaselineActionSave records the current values of all parameters managed
+// by the enabled Notes as baseline
+func BaselineActionSave(writer io.Writer, name string, tuneApp *app.App) {
+	baseline := system.JBaseline{Name: name, Created: time.Now().Format("2006-01-02 15:04:05.000"), Host: hostFacts(), NotesOrder: tuneApp.NoteApplyOrder}
+	baseline.Params = baselineParams(currentComparisons(tuneApp))
+	if baseline.NotesOrder == nil {
+		baseline.NotesOrder = []string{}
+	}
+	content, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		system.ErrorExit("failed to create the baseline '%s' - %v", name, err)
+	}
+	if err := os.MkdirAll(BaselineArea, 0755); err != nil {
+		system.ErrorExit("failed to create the baseline directory '%s' - %v", BaselineArea, err)
+	}
+	fileName := baselineFile(name)
+	if err := ioutil.WriteFile(fileName, content, 0644); err != nil {
+		system.ErrorExit("failed to write the baseline '%s' - %v", fileName, err)
+	}
+	system.InfoLog("baseline '%s' with %d parameters saved to '%s'", name, len(baseline.Params), fileName)
+	fmt.Fprintf(writer, "Baseline '%s' with the current values of %d parameters of the enabled Notes saved to '%s'.\n", name, len(baseline.Params), fileName)
+	system.Jcollect(baseline)
+}
+
+// BaselineActionCheck reports all parameters, whose current value differs
+// from the value recorded in the baseline. The compliance of the parameter
+// does not matter, a changed value is reported even if the parameter is
+// compliant before and after the change (e.g. a changed kernel default)
+func BaselineActionCheck(writer io.Writer, name string, tuneApp *app.App) {
+	baseline, err := readBaseline(name)
+	if err != nil {
+		system.ErrorExit("%v", err)
+	}
+	result := checkBaseline(baseline, baselineParams(currentComparisons(tuneApp)))
+	printBaselineCheck(writer, result)
+	system.Jcollect(result)
+	if len(result.Changes) != 0 {
+		system.ErrorExit("The parameters listed above have changed since the baseline '%s' was saved.", name)
+	}
+}
+
+// baselineFile returns the file name of a baseline
+func baselineFile(name string) string {
+	return path.Join(BaselineArea, name+".json")
+}
+
+// readBaseline reads a baseline saved by 'saptune baseline save'
+func readBaseline(name string) (system.JBaseline, error) {
+	baseline := system.JBaseline{}
+	fileName := baselineFile(name)
+	content, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return baseline, fmt.Errorf("baseline '%s' not found, please create it with 'saptune baseline save %s'", name, name)
+	}
+	if err != nil {
+		return baseline, fmt.Errorf("failed to read the baseline '%s' - %v", fileName, err)
+	}
+	if err := json.Unmarshal(content, &baseline); err != nil {
+		return baseline, fmt.Errorf("baseline '%s' is broken - %v", fileName, err)
+	}
+	return baseline, nil
+}
+
+// currentComparisons inspects the current values of the parameters of all
+// enabled Notes by using the Initialise path of the Notes
+func currentComparisons(tuneApp *app.App) map[string]map[string]note.FieldComparison {
+	if len(tuneApp.NoteApplyOrder) == 0 {
+		return map[string]map[string]note.FieldComparison{}
+	}
+	oldStdout, oldSdterr := system.SwitchOffOut()
+	_, comparisons, err := tuneApp.VerifyAll()
+	system.SwitchOnOut(oldStdout, oldSdterr)
+	if err != nil {
+		system.ErrorExit("Failed to inspect the current system: %v", err)
+	}
+	return comparisons
+}
+
+// baselineParams returns the current values of the parameters, sorted by
+// parameter name. A parameter set by more than one Note is only compliant,
+// if it is compliant for all of these Notes
+func baselineParams(comparisons map[string]map[string]note.FieldComparison) []system.JBaselineParam {
+	params := make(map[string]*system.JBaselineParam)
+	for noteID, noteComparisons := range comparisons {
+		for _, comparison := range noteComparisons {
+			if comparison.ReflectFieldName != "SysctlParams" || comparison.ReflectMapKey == "reminder" {
+				continue
+			}
+			key := comparison.ReflectMapKey
+			if _, ok := params[key]; !ok {
+				params[key] = &system.JBaselineParam{Parameter: key, Value: strings.Replace(comparison.ActualValueJS, "\t", " ", -1), Notes: []string{}, Compliant: true}
+			}
+			params[key].Notes = append(params[key].Notes, noteID)
+			params[key].Compliant = params[key].Compliant && comparison.MatchExpectation
+		}
+	}
+	result := make([]system.JBaselineParam, 0, len(params))
+	for _, param := range params {
+		sort.Strings(param.Notes)
+		result = append(result, *param)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Parameter < result[j].Parameter })
+	return result
+}
+
+// checkBaseline compares the recorded with the current parameter values
+func checkBaseline(baseline system.JBaseline, current []system.JBaselineParam) system.JBaselineCheck {
+	result := system.JBaselineCheck{Name: baseline.Name, Created: baseline.Created, Changes: []system.JBaselineChange{}}
+	recorded := make(map[string]system.JBaselineParam)
+	for _, param := range baseline.Params {
+		recorded[param.Parameter] = param
+	}
+	for _, param := range current {
+		param := param
+		base, ok := recorded[param.Parameter]
+		delete(recorded, param.Parameter)
+		switch {
+		case !ok:
+			result.Changes = append(result.Changes, system.JBaselineChange{Parameter: param.Parameter, Notes: param.Notes, Change: baselineAdded, ActValue: &param.Value, Compliant: &param.Compliant})
+		case base.Value != param.Value:
+			result.Changes = append(result.Changes, system.JBaselineChange{Parameter: param.Parameter, Notes: param.Notes, Change: baselineChanged, BaseValue: &base.Value, ActValue: &param.Value, BaseCompliant: &base.Compliant, Compliant: &param.Compliant})
+		}
+	}
+	result.ParamsCount = len(current) + len(recorded)
+	for _, base := range baseline.Params {
+		if _, ok := recorded[base.Parameter]; !ok {
+			continue
+		}
+		base := base
+		result.Changes = append(result.Changes, system.JBaselineChange{Parameter: base.Parameter, Notes: base.Notes, Change: baselineRemoved, BaseValue: &base.Value, BaseCompliant: &base.Compliant})
+	}
+	sort.SliceStable(result.Changes, func(i, j int) bool { return result.Changes[i].Parameter < result.Changes[j].Parameter })
+	return result
+}
+
+// printBaselineCheck prints the parameters changed since the baseline was
+// saved
+func printBaselineCheck(writer io.Writer, result system.JBaselineCheck) {
+	if len(result.Changes) == 0 {
+		fmt.Fprintf(writer, "\nNo parameter changed since the baseline '%s' was saved at %s (%d parameters checked).\n\n", result.Name, result.Created, result.ParamsCount)
+		return
+	}
+	rows := [][]string{}
+	for _, change := range result.Changes {
+		rows = append(rows, []string{change.Parameter, strings.Join(change.Notes, ", "), strPtrValue(change.BaseValue), strPtrValue(change.ActValue), complianceText(change.BaseCompliant) + " -> " + complianceText(change.Compliant), change.Change})
+	}
+	fmt.Fprintf(writer, "\nParameters changed since the baseline '%s' was saved at %s:\n\n", result.Name, result.Created)
+	printCompareTable(writer, []string{"Parameter", "Notes", "Baseline value", "Current value", "Compliant", "Change"}, rows)
+	fmt.Fprintf(writer, "\n%d of %d parameters changed.\n\n", len(result.Changes), result.ParamsCount)
+}
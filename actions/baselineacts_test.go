@@ -0,0 +1,117 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBaselineParams(t *testing.T) {
+	comparisons := map[string]map[string]note.FieldComparison{
+		"941735": {
+			"SysctlParams[vm.swappiness]": {ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.swappiness", ActualValueJS: "60", ExpectedValueJS: "60", MatchExpectation: true},
+			"SysctlParams[reminder]":      {ReflectFieldName: "SysctlParams", ReflectMapKey: "reminder", ActualValueJS: "remind me"},
+			"ConfFilePath":                {ReflectFieldName: "ConfFilePath", ActualValueJS: "/nonexisting/941735"},
+		},
+		"1680803": {
+			"SysctlParams[vm.swappiness]":   {ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.swappiness", ActualValueJS: "60", ExpectedValueJS: "10", MatchExpectation: false},
+			"SysctlParams[NRREQ_sda]":       {ReflectFieldName: "SysctlParams", ReflectMapKey: "NRREQ_sda", ActualValueJS: "1024", ExpectedValueJS: "1024", MatchExpectation: true},
+			"SysctlParams[rpm:libstdc++6]":  {ReflectFieldName: "SysctlParams", ReflectMapKey: "rpm:libstdc++6", ActualValueJS: "12.2.1", ExpectedValueJS: "", MatchExpectation: true},
+			"SysctlParams[MAX_MAP_COUNT_x]": {ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.max_map_count", ActualValueJS: "65530\t1", ExpectedValueJS: "65530", MatchExpectation: true},
+		},
+	}
+	exp := []system.JBaselineParam{
+		{Parameter: "NRREQ_sda", Value: "1024", Notes: []string{"1680803"}, Compliant: true},
+		{Parameter: "rpm:libstdc++6", Value: "12.2.1", Notes: []string{"1680803"}, Compliant: true},
+		{Parameter: "vm.max_map_count", Value: "65530 1", Notes: []string{"1680803"}, Compliant: true},
+		{Parameter: "vm.swappiness", Value: "60", Notes: []string{"1680803", "941735"}, Compliant: false},
+	}
+	if params := baselineParams(comparisons); !reflect.DeepEqual(params, exp) {
+		t.Errorf("got '%+v', expected '%+v'", params, exp)
+	}
+}
+
+func TestCheckBaseline(t *testing.T) {
+	baseline := system.JBaseline{Name: "default", Created: "2026-10-12 10:00:00.000", Params: []system.JBaselineParam{
+		{Parameter: "kernel.shmmni", Value: "32768", Notes: []string{"941735"}, Compliant: true},
+		{Parameter: "vm.dirty_ratio", Value: "20", Notes: []string{"941735"}, Compliant: true},
+		{Parameter: "vm.swappiness", Value: "10", Notes: []string{"941735"}, Compliant: true},
+	}}
+	current := []system.JBaselineParam{
+		{Parameter: "NRREQ_sda", Value: "1024", Notes: []string{"1680803"}, Compliant: true},
+		{Parameter: "kernel.shmmni", Value: "32768", Notes: []string{"941735"}, Compliant: true},
+		{Parameter: "vm.swappiness", Value: "60", Notes: []string{"941735"}, Compliant: true},
+	}
+	result := checkBaseline(baseline, current)
+	if result.ParamsCount != 4 || len(result.Changes) != 3 {
+		t.Fatalf("wrong check result '%+v'", result)
+	}
+	for cnt, exp := range [][]string{{"NRREQ_sda", baselineAdded}, {"vm.dirty_ratio", baselineRemoved}, {"vm.swappiness", baselineChanged}} {
+		if result.Changes[cnt].Parameter != exp[0] || result.Changes[cnt].Change != exp[1] {
+			t.Errorf("got '%+v', expected '%v'", result.Changes[cnt], exp)
+		}
+	}
+	// compliant before and after, but changed
+	if change := result.Changes[2]; *change.BaseValue != "10" || *change.ActValue != "60" || !*change.BaseCompliant || !*change.Compliant {
+		t.Errorf("wrong change '%+v'", change)
+	}
+
+	buffer := bytes.Buffer{}
+	printBaselineCheck(&buffer, result)
+	txt := buffer.String()
+	for _, line := range []string{
+		"Parameters changed since the baseline 'default' was saved at 2026-10-12 10:00:00.000:\n",
+		"   NRREQ_sda      | 1680803 | -              | 1024          | - -> yes   | added\n",
+		"   vm.dirty_ratio | 941735  | 20             | -             | yes -> -   | removed\n",
+		"   vm.swappiness  | 941735  | 10             | 60            | yes -> yes | changed\n",
+		"3 of 4 parameters changed.\n",
+	} {
+		if !strings.Contains(txt, line) {
+			t.Errorf("missing '%s' in '%s'", line, txt)
+		}
+	}
+
+	buffer.Reset()
+	printBaselineCheck(&buffer, checkBaseline(baseline, baseline.Params))
+	if !strings.Contains(buffer.String(), "No parameter changed since the baseline 'default' was saved at 2026-10-12 10:00:00.000 (3 parameters checked).\n") {
+		t.Errorf("wrong output '%s'", buffer.String())
+	}
+}
+
+func TestBaselineSaveAndRead(t *testing.T) {
+	oldBaselineArea := BaselineArea
+	defer func() { BaselineArea = oldBaselineArea }()
+	BaselineArea = t.TempDir()
+
+	if _, err := readBaseline("nightly"); err == nil || !strings.Contains(err.Error(), "please create it with 'saptune baseline save nightly'") {
+		t.Errorf("expected missing baseline error, got '%v'", err)
+	}
+	buffer := bytes.Buffer{}
+	BaselineActionSave(&buffer, "nightly", &app.App{})
+	if !strings.Contains(buffer.String(), "Baseline 'nightly' with the current values of 0 parameters of the enabled Notes saved to ") {
+		t.Errorf("wrong output '%s'", buffer.String())
+	}
+	baseline, err := readBaseline("nightly")
+	if err != nil || baseline.Name != "nightly" || len(baseline.Params) != 0 || baseline.NotesOrder == nil {
+		t.Errorf("wrong baseline '%+v' - '%v'", baseline, err)
+	}
+
+	oldOSExit := system.OSExit
+	defer func() { system.OSExit = oldOSExit }()
+	system.OSExit = tstosExit
+	oldErrorExitOut := system.ErrorExitOut
+	defer func() { system.ErrorExitOut = oldErrorExitOut }()
+	system.ErrorExitOut = tstErrorExitOut
+	for _, name := range []string{"../etc", ".hidden", "a b"} {
+		tstRetErrorExit = -1
+		tstwriter = &bytes.Buffer{}
+		BaselineAction(&buffer, "check", name, &app.App{})
+		if tstRetErrorExit != 1 || !strings.Contains(tstwriter.(*bytes.Buffer).String(), "wrong baseline name") {
+			t.Errorf("'%s': expected error exit, got '%d'", name, tstRetErrorExit)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// verifySnapshot is the machine readable output of 'saptune --format=json
+// note|solution verify' used by 'saptune compare'
+type verifySnapshot struct {
+	Created string         `json:"publish time"`
+	Cmd     string         `json:"command"`
+	Result  system.JPNotes `json:"result"`
+}
+
+// snapshotCommands are the commands, which create a verify snapshot
+var snapshotCommands = []string{"note verify", "solution verify", "verify"}
+
+// hostFacts returns the facts of the running system, which are added to the
+// verify result to compare the tuning of different hosts
+func hostFacts() *system.JHostFacts {
+	hostname, _ := os.Hostname()
+	return &system.JHostFacts{Hostname: hostname, VirtEnv: system.GetVirtStatus(), CSP: system.GetCSP(), OsVersion: system.GetOsVers(), Arch: system.GetArch()}
+}
+
+// CompareAction compares the verify snapshots of two hosts. Only the
+// snapshot files are used, so it runs on every host (e.g. an admin
+// workstation)
+func CompareAction(writer io.Writer, fileA, fileB string) {
+	if fileA == "" || fileB == "" {
+		PrintHelpAndExit(writer, 1)
+	}
+	snapshots := []verifySnapshot{}
+	for _, fileName := range []string{fileA, fileB} {
+		snapshot, err := readVerifySnapshot(fileName)
+		if err != nil {
+			// logging is not initialised for 'saptune compare'
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			system.ErrorExit("", 1)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	snapA, snapB := snapshots[0], snapshots[1]
+	result := compareSnapshots(fileA, fileB, snapA, snapB)
+	printCompareResult(writer, result)
+	system.Jcollect(result)
+}
+
+// readVerifySnapshot reads and checks a verify snapshot file
+func readVerifySnapshot(fileName string) (verifySnapshot, error) {
+	snapshot := verifySnapshot{}
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return snapshot, fmt.Errorf("failed to read the snapshot '%s' - %v", fileName, err)
+	}
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("snapshot '%s' is not a valid json file - %v", fileName, err)
+	}
+	if !system.IsInList(snapshot.Cmd, snapshotCommands) {
+		return snapshot, fmt.Errorf("file '%s' is not a verify snapshot, please create it with 'saptune --format=json solution verify'", fileName)
+	}
+	return snapshot, nil
+}
+
+// snapshotLines returns the verify lines of a snapshot by Note and parameter
+func snapshotLines(snapshot verifySnapshot) map[string]system.JPNotesLine {
+	lines := make(map[string]system.JPNotesLine)
+	for _, line := range snapshot.Result.Verifications {
+		lines[line.NoteID+"§"+line.Parameter] = line
+	}
+	return lines
+}
+
+// compareValue returns the values of a parameter of one of the compared
+// hosts
+func compareValue(line system.JPNotesLine, available bool) system.JCompareValue {
+	if !available {
+		return system.JCompareValue{}
+	}
+	return system.JCompareValue{Available: true, ExpValue: line.ExpValue, ActValue: line.ActValue, Compliant: line.Compliant}
+}
+
+// compareDiffs returns the differences between the values of a parameter
+// of the two hosts ('missing', 'actual value', 'expected value',
+// 'compliance')
+func compareDiffs(valA, valB system.JCompareValue) []string {
+	diffs := []string{}
+	if valA.Available != valB.Available {
+		return append(diffs, "missing")
+	}
+	if strPtrValue(valA.ActValue) != strPtrValue(valB.ActValue) {
+		diffs = append(diffs, "actual value")
+	}
+	if valA.ExpValue != valB.ExpValue {
+		diffs = append(diffs, "expected value")
+	}
+	if complianceText(valA.Compliant) != complianceText(valB.Compliant) {
+		diffs = append(diffs, "compliance")
+	}
+	return diffs
+}
+
+// compareSnapshots aligns the verify lines of both snapshots by Note and
+// parameter and collects the differences
+func compareSnapshots(fileA, fileB string, snapA, snapB verifySnapshot) system.JCompare {
+	result := system.JCompare{Hosts: []system.JCompareHost{compareHost(fileA, snapA), compareHost(fileB, snapB)}, Differences: []system.JCompareLine{}}
+	linesA := snapshotLines(snapA)
+	linesB := snapshotLines(snapB)
+	keys := make([]string, 0, len(linesA)+len(linesB))
+	for key := range linesA {
+		keys = append(keys, key)
+	}
+	for key := range linesB {
+		if _, ok := linesA[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	result.ParamsCount = len(keys)
+	for _, key := range keys {
+		lineA, okA := linesA[key]
+		lineB, okB := linesB[key]
+		valA := compareValue(lineA, okA)
+		valB := compareValue(lineB, okB)
+		diffs := compareDiffs(valA, valB)
+		if len(diffs) == 0 {
+			continue
+		}
+		keyFields := strings.Split(key, "§")
+		result.Differences = append(result.Differences, system.JCompareLine{NoteID: keyFields[0], Parameter: keyFields[1], Diffs: diffs, Values: []system.JCompareValue{valA, valB}})
+	}
+	return result
+}
+
+// compareHost returns the information about a compared host
+func compareHost(fileName string, snapshot verifySnapshot) system.JCompareHost {
+	notes := snapshot.Result.NotesOrder
+	if notes == nil {
+		notes = []string{}
+	}
+	return system.JCompareHost{File: fileName, Created: snapshot.Created, Facts: snapshot.Result.Host, NotesOrder: notes, Compliance: snapshot.Result.SysCompliance}
+}
+
+// strPtrValue returns the value of a string pointer, '-' for nil
+func strPtrValue(str *string) string {
+	if str == nil {
+		return "-"
+	}
+	return *str
+}
+
+// complianceText returns the compliance of a parameter as shown in the
+// verify table
+func complianceText(compliant *bool) string {
+	switch {
+	case compliant == nil:
+		return "-"
+	case *compliant:
+		return "yes"
+	}
+	return "no"
+}
+
+// hostFactRows returns the host facts of both hosts as table rows
+func hostFactRows(hosts []system.JCompareHost) [][]string {
+	facts := func(host system.JCompareHost) []string {
+		if host.Facts == nil {
+			return []string{"-", "-", "-", "-", "-"}
+		}
+		return []string{host.Facts.Hostname, host.Facts.VirtEnv, host.Facts.CSP, host.Facts.OsVersion, host.Facts.Arch}
+	}
+	names := []string{"hostname", "virtualization", "cloud service provider", "os version", "architecture"}
+	factsA := facts(hosts[0])
+	factsB := facts(hosts[1])
+	rows := [][]string{{"snapshot", hosts[0].File, hosts[1].File}, {"publish time", hosts[0].Created, hosts[1].Created}}
+	for cnt, name := range names {
+		rows = append(rows, []string{name, factsA[cnt], factsB[cnt]})
+	}
+	rows = append(rows, []string{"Notes enabled", strings.Join(hosts[0].NotesOrder, " "), strings.Join(hosts[1].NotesOrder, " ")})
+	rows = append(rows, []string{"system compliance", complianceText(hosts[0].Compliance), complianceText(hosts[1].Compliance)})
+	return rows
+}
+
+// printCompareTable prints a table with a header line
+func printCompareTable(writer io.Writer, header []string, rows [][]string) {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for cnt, col := range row {
+			if len(col) > widths[cnt] {
+				widths[cnt] = len(col)
+			}
+		}
+	}
+	format := "  "
+	for cnt := range widths[:len(widths)-1] {
+		format = format + " %-" + strconv.Itoa(widths[cnt]) + "s |"
+	}
+	format = format + " %s\n"
+	printRow := func(row []string) {
+		cols := make([]interface{}, len(row))
+		for cnt, col := range row {
+			cols[cnt] = col
+		}
+		fmt.Fprintf(writer, format, cols...)
+	}
+	printRow(header)
+	fmt.Fprintf(writer, "%s\n", tableSeparator(widths[:len(widths)-1], widths[len(widths)-1]+1))
+	for _, row := range rows {
+		printRow(row)
+	}
+}
+
+// printCompareResult prints the host facts and the differing parameters of
+// the compared hosts
+func printCompareResult(writer io.Writer, result system.JCompare) {
+	fmt.Fprintf(writer, "\nHost facts:\n\n")
+	printCompareTable(writer, []string{"Fact", "Host A", "Host B"}, hostFactRows(result.Hosts))
+	if len(result.Differences) == 0 {
+		fmt.Fprintf(writer, "\nNo differences found in the %d compared parameters.\n\n", result.ParamsCount)
+		return
+	}
+	rows := [][]string{}
+	for _, diff := range result.Differences {
+		row := []string{diff.NoteID, diff.Parameter}
+		for _, val := range diff.Values {
+			if !val.Available {
+				row = append(row, "(not verified)", "", "")
+				continue
+			}
+			row = append(row, strPtrValue(val.ActValue), val.ExpValue, complianceText(val.Compliant))
+		}
+		rows = append(rows, append(row, strings.Join(diff.Diffs, ", ")))
+	}
+	fmt.Fprintf(writer, "\nDiffering parameters:\n\n")
+	printCompareTable(writer, []string{"SAPNote", "Parameter", "Actual A", "Expected A", "Compliant A", "Actual B", "Expected B", "Compliant B", "Differences"}, rows)
+	fmt.Fprintf(writer, "\n%d of %d compared parameters differ.\n\n", len(result.Differences), result.ParamsCount)
+}
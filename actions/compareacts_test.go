@@ -0,0 +1,103 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/system"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func compareTestSnapshots() (verifySnapshot, verifySnapshot) {
+	sixty := "60"
+	ten := "10"
+	none := "none"
+	yes := true
+	no := false
+	snapA := verifySnapshot{Created: "2026-10-19 10:00:00.000", Cmd: "solution verify", Result: system.JPNotes{
+		Verifications: []system.JPNotesLine{
+			{NoteID: "941735", Parameter: "vm.swappiness", ExpValue: "10", ActValue: &sixty, Compliant: &no},
+			{NoteID: "941735", Parameter: "kernel.shmmni", ExpValue: "32768", ActValue: &ten, Compliant: &no},
+			{NoteID: "1680803", Parameter: "IO_SCHEDULER_sda", ExpValue: "none", ActValue: &none, Compliant: &yes},
+		},
+		NotesOrder:    []string{"941735", "1680803"},
+		SysCompliance: &no,
+		Host:          &system.JHostFacts{Hostname: "hana01", VirtEnv: "kvm", OsVersion: "15-SP5", Arch: "x86_64"},
+	}}
+	snapB := verifySnapshot{Created: "2026-10-19 11:00:00.000", Cmd: "note verify", Result: system.JPNotes{
+		Verifications: []system.JPNotesLine{
+			{NoteID: "941735", Parameter: "vm.swappiness", ExpValue: "10", ActValue: &ten, Compliant: &yes},
+			{NoteID: "941735", Parameter: "kernel.shmmni", ExpValue: "32768", ActValue: &ten, Compliant: &no},
+		},
+		NotesOrder:    []string{"941735"},
+		SysCompliance: &yes,
+	}}
+	return snapA, snapB
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	snapA, snapB := compareTestSnapshots()
+	result := compareSnapshots("a.json", "b.json", snapA, snapB)
+	if result.ParamsCount != 3 || len(result.Differences) != 2 {
+		t.Fatalf("wrong compare result '%+v'", result)
+	}
+	if result.Hosts[0].Facts.Hostname != "hana01" || result.Hosts[1].Facts != nil || result.Hosts[1].File != "b.json" {
+		t.Errorf("wrong hosts '%+v'", result.Hosts)
+	}
+	diff := result.Differences[0]
+	if diff.NoteID != "1680803" || diff.Parameter != "IO_SCHEDULER_sda" || !reflect.DeepEqual(diff.Diffs, []string{"missing"}) || result.Differences[0].Values[1].Available {
+		t.Errorf("wrong difference '%+v'", diff)
+	}
+	diff = result.Differences[1]
+	if diff.Parameter != "vm.swappiness" || !reflect.DeepEqual(diff.Diffs, []string{"actual value", "compliance"}) {
+		t.Errorf("wrong difference '%+v'", diff)
+	}
+
+	buffer := bytes.Buffer{}
+	printCompareResult(&buffer, result)
+	txt := buffer.String()
+	for _, line := range []string{
+		"   hostname               | hana01                  | -\n",
+		"   Notes enabled          | 941735 1680803          | 941735\n",
+		"   system compliance      | no                      | yes\n",
+		"   1680803 | IO_SCHEDULER_sda | none     | none       | yes         | (not verified) |            |             | missing\n",
+		"   941735  | vm.swappiness    | 60       | 10         | no          | 10             | 10         | yes         | actual value, compliance\n",
+		"2 of 3 compared parameters differ.\n",
+	} {
+		if !strings.Contains(txt, line) {
+			t.Errorf("missing '%s' in '%s'", line, txt)
+		}
+	}
+
+	buffer.Reset()
+	printCompareResult(&buffer, compareSnapshots("a.json", "a.json", snapA, snapA))
+	if !strings.Contains(buffer.String(), "No differences found in the 3 compared parameters.\n") {
+		t.Errorf("wrong output '%s'", buffer.String())
+	}
+}
+
+func TestReadVerifySnapshot(t *testing.T) {
+	tstDir := t.TempDir()
+	for name, tt := range map[string]struct{ content, errTxt string }{
+		"missing.json": {"", "failed to read the snapshot"},
+		"nojson.json":  {"no json", "is not a valid json file"},
+		"list.json":    {`{"command":"note list","result":{}}`, "is not a verify snapshot"},
+	} {
+		fileName := path.Join(tstDir, name)
+		content, errTxt := tt.content, tt.errTxt
+		if content != "" {
+			_ = ioutil.WriteFile(fileName, []byte(content), 0644)
+		}
+		if _, err := readVerifySnapshot(fileName); err == nil || !strings.Contains(err.Error(), errTxt) {
+			t.Errorf("expected error '%s', got '%v'", errTxt, err)
+		}
+	}
+	fileName := path.Join(tstDir, "verify.json")
+	_ = ioutil.WriteFile(fileName, []byte(`{"command":"solution verify","result":{"verifications":[{"Note ID":"941735","parameter":"vm.swappiness","expected value":"10"}],"Notes enabled":["941735"]}}`), 0644)
+	snapshot, err := readVerifySnapshot(fileName)
+	if err != nil || len(snapshot.Result.Verifications) != 1 || snapshot.Result.Verifications[0].Parameter != "vm.swappiness" {
+		t.Errorf("wrong snapshot '%+v' - '%v'", snapshot, err)
+	}
+}
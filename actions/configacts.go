@@ -0,0 +1,435 @@
+package actions
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// names of the entries in the configuration bundle
+const (
+	bundleManifest  = "manifest.json"
+	bundleSysconfig = "sysconfig/saptune"
+	bundleOverride  = "override/"
+	bundleExtra     = "extra/"
+)
+
+// configManifest describes the content of a configuration bundle
+type configManifest struct {
+	SaptuneVersion string            `json:"saptune version"`
+	PackageVersion string            `json:"package version"`
+	Created        string            `json:"created"`
+	Notes          map[string]string `json:"note versions"`
+}
+
+// ConfigAction handles the export and import of the complete saptune
+// configuration as a bundle
+func ConfigAction(reader io.Reader, writer io.Writer, actionName, fileName, saptuneVers string, tuneApp *app.App) {
+	if fileName == "" {
+		PrintHelpAndExit(writer, 1)
+	}
+	switch actionName {
+	case "export":
+		ConfigActionExport(writer, fileName, saptuneVers, tuneApp)
+	case "import":
+		ConfigActionImport(reader, writer, fileName, saptuneVers, tuneApp)
+	default:
+		PrintHelpAndExit(writer, 1)
+	}
+}
+
+// ConfigActionExport writes the saptune configuration file, the override
+// files and the custom Note and solution definition files together with a
+// manifest into a gzip compressed tar file
+func ConfigActionExport(writer io.Writer, fileName, saptuneVers string, tuneApp *app.App) {
+	bundle, err := collectConfigBundle()
+	if err != nil {
+		system.ErrorExit("Failed to collect the configuration files - %v", err)
+	}
+	if _, ok := bundle[bundleSysconfig]; !ok {
+		system.ErrorExit("Missing saptune configuration file '%s'", saptuneSysconfig)
+	}
+	manifest := configManifest{
+		SaptuneVersion: saptuneVers,
+		PackageVersion: RPMVersion,
+		Created:        time.Now().Format(time.RFC3339),
+		Notes:          noteVersions(tuneApp),
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		system.ErrorExit("Failed to create the manifest - %v", err)
+	}
+	bundle[bundleManifest] = append(content, '\n')
+	if err := writeConfigBundle(fileName, bundle); err != nil {
+		system.ErrorExit("Failed to write configuration bundle '%s' - %v", fileName, err)
+	}
+	fmt.Fprintf(writer, "Configuration exported to '%s' (%d files).\n", fileName, len(bundle)-1)
+}
+
+// ConfigActionImport validates the configuration bundle, shows the
+// differences to the current configuration and installs the bundle after
+// confirmation
+func ConfigActionImport(reader io.Reader, writer io.Writer, fileName, saptuneVers string, tuneApp *app.App) {
+	if len(tuneApp.NoteApplyOrder) != 0 {
+		system.ErrorExit("There are still Notes or solutions enabled. Please revert them with 'saptune revert all' before importing a configuration.")
+	}
+	bundle, err := readConfigBundle(fileName)
+	if err != nil {
+		system.ErrorExit("Failed to read configuration bundle '%s' - %v", fileName, err)
+	}
+	if err := checkConfigBundle(bundle, saptuneVers, tuneApp); err != nil {
+		system.ErrorExit("Invalid configuration bundle '%s' - %v", fileName, err)
+	}
+	if !printConfigDiff(writer, bundle) {
+		system.ErrorExit("The configuration bundle matches the current configuration, nothing to do.", 0)
+	}
+	if system.IsFlagSet("dryrun") {
+		system.ErrorExit("Flag 'dryrun' set, so config action 'import' finished now without installing anything", 0)
+	}
+	if !system.IsFlagSet("force") {
+		if !readYesNo("The current configuration will be replaced. Are you sure", reader, writer) {
+			system.ErrorExit("Config action 'import' aborted by user interaction", 0)
+		}
+	}
+	if err := installConfigBundle(bundle); err != nil {
+		system.ErrorExit("Failed to install configuration bundle '%s' - %v", fileName, err)
+	}
+	system.NoticeLog("Configuration bundle '%s' imported. Use 'saptune service apply' to apply the enabled Notes and solutions.", fileName)
+}
+
+// configBundleDirs returns the directories of the bundle and the related
+// directories on the host
+func configBundleDirs() map[string]string {
+	return map[string]string{bundleOverride: OverrideTuningSheets, bundleExtra: ExtraTuningSheets}
+}
+
+// collectConfigBundle reads all configuration files of the host.
+// The map key is the name of the file in the bundle
+func collectConfigBundle() (map[string][]byte, error) {
+	bundle := make(map[string][]byte)
+	content, err := ioutil.ReadFile(saptuneSysconfig)
+	if err == nil {
+		bundle[bundleSysconfig] = content
+	} else if !os.IsNotExist(err) {
+		return bundle, err
+	}
+	for prefix, dir := range configBundleDirs() {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return bundle, err
+		}
+		for _, entry := range entries {
+			if !entry.Mode().IsRegular() {
+				continue
+			}
+			content, err := ioutil.ReadFile(path.Join(dir, entry.Name()))
+			if err != nil {
+				return bundle, err
+			}
+			bundle[prefix+entry.Name()] = content
+		}
+	}
+	return bundle, nil
+}
+
+// noteVersions returns the versions of all available Notes
+func noteVersions(tuneApp *app.App) map[string]string {
+	versions := make(map[string]string)
+	for noteID, aNote := range tuneApp.AllNotes {
+		if iniNote, ok := aNote.(note.INISettings); ok {
+			versions[noteID] = txtparser.GetINIFileVersionSectionEntry(iniNote.ConfFilePath, "version")
+		}
+	}
+	return versions
+}
+
+// writeConfigBundle writes the bundle as gzip compressed tar file
+func writeConfigBundle(fileName string, bundle map[string][]byte) error {
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gzw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gzw)
+	now := time.Now()
+	for _, name := range sortedBundleNames(bundle) {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(bundle[name])), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(bundle[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
+
+// readConfigBundle reads the gzip compressed tar file. Only the manifest,
+// the saptune configuration file and files directly located in the
+// override and extra directory are accepted
+func readConfigBundle(fileName string) (map[string][]byte, error) {
+	bundle := make(map[string][]byte)
+	file, err := os.Open(fileName)
+	if err != nil {
+		return bundle, err
+	}
+	defer file.Close()
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return bundle, err
+	}
+	defer gzr.Close()
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return bundle, err
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg || !validBundleName(hdr.Name) {
+			return bundle, fmt.Errorf("unexpected entry '%s'", hdr.Name)
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return bundle, err
+		}
+		bundle[hdr.Name] = content
+	}
+	return bundle, nil
+}
+
+// validBundleName checks, if the name is a supported entry of the bundle
+func validBundleName(name string) bool {
+	if name == bundleManifest || name == bundleSysconfig {
+		return true
+	}
+	for prefix := range configBundleDirs() {
+		base := strings.TrimPrefix(name, prefix)
+		if base != name && base != "" && base != "." && base != ".." && !strings.Contains(base, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConfigBundle checks the manifest and validates the configuration
+// file and all Note and solution definition files of the bundle
+func checkConfigBundle(bundle map[string][]byte, saptuneVers string, tuneApp *app.App) error {
+	manifest := configManifest{}
+	content, ok := bundle[bundleManifest]
+	if !ok {
+		return fmt.Errorf("missing '%s'", bundleManifest)
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return fmt.Errorf("wrong manifest - %v", err)
+	}
+	if manifest.SaptuneVersion != saptuneVers {
+		system.WarningLog("the bundle was created with saptune version '%s', but saptune version '%s' is active on this host", manifest.SaptuneVersion, saptuneVers)
+	}
+	sysconf, ok := bundle[bundleSysconfig]
+	if !ok {
+		return fmt.Errorf("missing '%s'", bundleSysconfig)
+	}
+	if _, err := txtparser.ParseSysconfig(string(sysconf)); err != nil {
+		return fmt.Errorf("wrong saptune configuration file - %v", err)
+	}
+
+	// validate the definition files in a temporary directory
+	tmpDir, err := ioutil.TempDir("", "saptune_import")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	for _, name := range sortedBundleNames(bundle) {
+		if name == bundleManifest || name == bundleSysconfig {
+			continue
+		}
+		tmpFile := path.Join(tmpDir, strings.Replace(name, "/", "_", -1))
+		if err := ioutil.WriteFile(tmpFile, bundle[name], 0644); err != nil {
+			return err
+		}
+		if _, err := txtparser.ParseINIFile(tmpFile, false); err != nil {
+			return fmt.Errorf("wrong definition file '%s' - %v", name, err)
+		}
+		if strings.HasPrefix(name, bundleExtra) && strings.HasSuffix(name, ".conf") {
+			if err := txtparser.ChkVersionSection(tmpFile); err != nil {
+				return fmt.Errorf("wrong Note definition file '%s' - %v", name, err)
+			}
+		}
+	}
+
+	// compare the Note versions of the bundle with the Notes of this host
+	noteIDs := make([]string, 0, len(manifest.Notes))
+	for noteID := range manifest.Notes {
+		noteIDs = append(noteIDs, noteID)
+	}
+	sort.Strings(noteIDs)
+	hostVersions := noteVersions(tuneApp)
+	for _, noteID := range noteIDs {
+		if _, ok := bundle[bundleExtra+noteID+".conf"]; ok {
+			// custom Note is part of the bundle
+			continue
+		}
+		hostVers, ok := hostVersions[noteID]
+		switch {
+		case !ok:
+			system.WarningLog("Note '%s' (version %s) is not available on this host", noteID, manifest.Notes[noteID])
+		case hostVers != manifest.Notes[noteID]:
+			system.WarningLog("Note '%s' has version %s in the bundle, but version %s on this host", noteID, manifest.Notes[noteID], hostVers)
+		}
+	}
+	return nil
+}
+
+// printConfigDiff prints the differences between the bundle and the
+// configuration of the host. Returns false, if there are no differences
+func printConfigDiff(writer io.Writer, bundle map[string][]byte) bool {
+	current, err := collectConfigBundle()
+	if err != nil {
+		system.ErrorExit("Failed to collect the configuration files - %v", err)
+	}
+	names := sortedBundleNames(bundle)
+	for name := range current {
+		if _, ok := bundle[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	differs := false
+	for _, name := range names {
+		if name == bundleManifest {
+			continue
+		}
+		hostFile := bundleHostFile(name)
+		oldContent, inHost := current[name]
+		newContent, inBundle := bundle[name]
+		switch {
+		case !inHost:
+			fmt.Fprintf(writer, "new      %s\n", hostFile)
+		case !inBundle:
+			fmt.Fprintf(writer, "removed  %s\n", hostFile)
+		case string(oldContent) != string(newContent):
+			fmt.Fprintf(writer, "changed  %s\n", hostFile)
+			for _, line := range diffLines(strings.Split(string(oldContent), "\n"), strings.Split(string(newContent), "\n")) {
+				fmt.Fprintf(writer, "    %s\n", line)
+			}
+		default:
+			continue
+		}
+		differs = true
+	}
+	return differs
+}
+
+// installConfigBundle writes the files of the bundle to the host and
+// removes the override and custom definition files, which are not part of
+// the bundle
+func installConfigBundle(bundle map[string][]byte) error {
+	for prefix, dir := range configBundleDirs() {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if _, ok := bundle[prefix+entry.Name()]; ok || !entry.Mode().IsRegular() {
+				continue
+			}
+			if err := os.Remove(path.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range sortedBundleNames(bundle) {
+		if name == bundleManifest {
+			continue
+		}
+		if err := ioutil.WriteFile(bundleHostFile(name), bundle[name], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bundleHostFile returns the file name on the host for a bundle entry
+func bundleHostFile(name string) string {
+	if name == bundleSysconfig {
+		return saptuneSysconfig
+	}
+	for prefix, dir := range configBundleDirs() {
+		if strings.HasPrefix(name, prefix) {
+			return path.Join(dir, strings.TrimPrefix(name, prefix))
+		}
+	}
+	return name
+}
+
+// sortedBundleNames returns the entry names of the bundle sorted
+func sortedBundleNames(bundle map[string][]byte) []string {
+	names := make([]string, 0, len(bundle))
+	for name := range bundle {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diffLines returns the differences between the old and new lines.
+// Removed lines are prefixed by '-', added lines by '+'
+func diffLines(oldLines, newLines []string) []string {
+	// length of the longest common subsequence for the line suffixes
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	diff := []string{}
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+oldLines[i])
+			i++
+		default:
+			diff = append(diff, "+ "+newLines[j])
+			j++
+		}
+	}
+	return diff
+}
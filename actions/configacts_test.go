@@ -0,0 +1,106 @@
+package actions
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigBundleExportImport(t *testing.T) {
+	oldSysconfig, oldOverride, oldExtra := saptuneSysconfig, OverrideTuningSheets, ExtraTuningSheets
+	defer func() {
+		saptuneSysconfig, OverrideTuningSheets, ExtraTuningSheets = oldSysconfig, oldOverride, oldExtra
+	}()
+	tstDir := "/tmp/saptune_tst_bundle/"
+	defer os.RemoveAll(tstDir)
+	saptuneSysconfig = tstDir + "sysconfig"
+	OverrideTuningSheets = tstDir + "override/"
+	ExtraTuningSheets = tstDir + "extra/"
+	bundleFile := tstDir + "bundle.tgz"
+	_ = os.MkdirAll(OverrideTuningSheets, 0755)
+	_ = os.MkdirAll(ExtraTuningSheets, 0755)
+	_ = ioutil.WriteFile(saptuneSysconfig, []byte("TUNE_FOR_NOTES=\"simpleNote\"\nCOLOR_SCHEME=\"\"\n"), 0644)
+	_ = ioutil.WriteFile(OverrideTuningSheets+"simpleNote", []byte("[sysctl]\nvm.swappiness = 10\n"), 0644)
+	content, _ := ioutil.ReadFile(ExtraFilesInGOPATH + "simpleNote.conf")
+	_ = ioutil.WriteFile(ExtraTuningSheets+"simpleNote.conf", content, 0644)
+
+	buffer := bytes.Buffer{}
+	ConfigActionExport(&buffer, bundleFile, "3", tApp)
+	checkOut(t, buffer.String(), "Configuration exported to '"+bundleFile+"' (3 files).\n")
+
+	bundle, err := readConfigBundle(bundleFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNames := []string{"extra/simpleNote.conf", "manifest.json", "override/simpleNote", "sysconfig/saptune"}
+	if !reflect.DeepEqual(sortedBundleNames(bundle), wantNames) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", sortedBundleNames(bundle), wantNames)
+	}
+	if !strings.Contains(string(bundle[bundleManifest]), `"saptune version": "3"`) {
+		t.Errorf("wrong manifest '%s'\n", string(bundle[bundleManifest]))
+	}
+	if err := checkConfigBundle(bundle, "3", tApp); err != nil {
+		t.Error(err)
+	}
+	buffer.Reset()
+	if printConfigDiff(&buffer, bundle) {
+		t.Errorf("expected no differences, got '%s'\n", buffer.String())
+	}
+
+	// change the host configuration
+	_ = ioutil.WriteFile(saptuneSysconfig, []byte("TUNE_FOR_NOTES=\"\"\nCOLOR_SCHEME=\"\"\n"), 0644)
+	_ = os.Remove(OverrideTuningSheets + "simpleNote")
+	_ = ioutil.WriteFile(ExtraTuningSheets+"other.sol", []byte("[ArchX86]\nsimpleNote\n"), 0644)
+	buffer.Reset()
+	if !printConfigDiff(&buffer, bundle) {
+		t.Errorf("expected differences\n")
+	}
+	diffTxt := `removed  ` + ExtraTuningSheets + `other.sol
+new      ` + OverrideTuningSheets + `simpleNote
+changed  ` + saptuneSysconfig + `
+    - TUNE_FOR_NOTES=""
+    + TUNE_FOR_NOTES="simpleNote"
+`
+	checkOut(t, buffer.String(), diffTxt)
+
+	if err := installConfigBundle(bundle); err != nil {
+		t.Error(err)
+	}
+	buffer.Reset()
+	if printConfigDiff(&buffer, bundle) {
+		t.Errorf("expected no differences after install, got '%s'\n", buffer.String())
+	}
+
+	// wrong Note definition file
+	bundle["extra/wrongNote.conf"] = []byte("[sysctl]\nvm.swappiness = 10\n")
+	if err := checkConfigBundle(bundle, "3", tApp); err == nil {
+		t.Errorf("expected an error for a Note definition file without version section\n")
+	}
+	delete(bundle, bundleManifest)
+	if err := checkConfigBundle(bundle, "3", tApp); err == nil {
+		t.Errorf("expected an error for a missing manifest\n")
+	}
+}
+
+func TestValidBundleName(t *testing.T) {
+	for name, want := range map[string]bool{"manifest.json": true, "sysconfig/saptune": true, "override/2382421": true, "extra/NEWSOL.sol": true, "extra/": false, "extra/../../etc/passwd": false, "override/sub/file": false, "etc/passwd": false, "override/..": false} {
+		if got := validBundleName(name); got != want {
+			t.Errorf("'%s': got: '%v', expected: '%v'\n", name, got, want)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	oldLines := []string{"a", "b", "c", "d"}
+	newLines := []string{"a", "c", "x", "d", "e"}
+	want := []string{"- b", "+ x", "+ e"}
+	if got := diffLines(oldLines, newLines); !reflect.DeepEqual(got, want) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", got, want)
+	}
+	if got := diffLines(oldLines, oldLines); len(got) != 0 {
+		t.Errorf("got: '%+v', expected no differences\n", got)
+	}
+}
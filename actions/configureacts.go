@@ -0,0 +1,411 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// keys of the host profile file
+const (
+	profSolutions   = "solutions"
+	profNotes       = "notes"
+	profOrder       = "order"
+	profOverrides   = "overrides"
+	profStaging     = "staging"
+	profColorScheme = "color_scheme"
+)
+
+// hostProfile is the declarative description of the saptune configuration
+// of a host. Only the keys available in the profile file are managed, all
+// other settings stay untouched.
+type hostProfile struct {
+	Solutions   []string          // enabled solutions
+	Notes       []string          // additional enabled notes
+	Order       []string          // note apply order
+	Overrides   map[string]string // content of the note override files
+	Staging     string            // 'true' or 'false'
+	ColorScheme string            // color scheme of the verify output
+	keys        map[string]bool   // keys available in the profile file
+}
+
+// ConfigureAction converges the host to the given profile file or exports
+// the current configuration in the profile format
+func ConfigureAction(writer io.Writer, tuneApp *app.App) {
+	switch {
+	case system.GetFlagVal("from") != "":
+		ConfigureActionFrom(writer, system.GetFlagVal("from"), tuneApp)
+	case system.IsFlagSet("export"):
+		ConfigureActionExport(writer, tuneApp)
+	default:
+		PrintHelpAndExit(writer, 1)
+	}
+}
+
+// ConfigureActionFrom reads the profile file and converges the host to the
+// described state
+func ConfigureActionFrom(writer io.Writer, fileName string, tuneApp *app.App) {
+	prof, err := readHostProfile(fileName)
+	if err != nil {
+		system.ErrorExit("Failed to read profile '%s' - %v", fileName, err)
+	}
+	if err := checkHostProfile(prof, tuneApp); err != nil {
+		system.ErrorExit("Invalid profile '%s' - %v", fileName, err)
+	}
+	changed, err := writeProfileOverrides(prof)
+	if err != nil {
+		system.ErrorExit("Failed to write the override files - %v", err)
+	}
+	if err := convergeHostProfile(writer, prof, changed, tuneApp); err != nil {
+		system.ErrorExit("Failed to apply profile '%s' - %v", fileName, err)
+	}
+	if err := writeProfileSysconfig(prof); err != nil {
+		system.ErrorExit("Failed to write '%s' - %v", saptuneSysconfig, err)
+	}
+	system.NoticeLog("The host configuration now matches the profile '%s'.", fileName)
+}
+
+// ConfigureActionExport prints the current configuration in the profile
+// format
+func ConfigureActionExport(writer io.Writer, tuneApp *app.App) {
+	prof := hostProfile{
+		Solutions: tuneApp.TuneForSolutions,
+		Notes:     tuneApp.TuneForNotes,
+		Order:     tuneApp.NoteApplyOrder,
+		Overrides: make(map[string]string),
+		Staging:   "false",
+	}
+	entries, err := ioutil.ReadDir(OverrideTuningSheets)
+	if err != nil && !os.IsNotExist(err) {
+		system.ErrorExit("Failed to read override directory '%s' - %v", OverrideTuningSheets, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".sol") {
+			// solution overrides are not part of the profile
+			continue
+		}
+		content, err := ioutil.ReadFile(path.Join(OverrideTuningSheets, entry.Name()))
+		if err != nil {
+			system.ErrorExit("Failed to read override file - %v", err)
+		}
+		prof.Overrides[entry.Name()] = string(content)
+	}
+	if sconf, err := txtparser.ParseSysconfigFile(saptuneSysconfig, false); err == nil {
+		prof.Staging = sconf.GetString("STAGING", "false")
+		prof.ColorScheme = sconf.GetString("COLOR_SCHEME", "")
+	}
+	fmt.Fprint(writer, hostProfileToYAML(prof))
+}
+
+// readHostProfile reads and parses the profile file
+func readHostProfile(fileName string) (hostProfile, error) {
+	prof := hostProfile{Overrides: make(map[string]string), keys: make(map[string]bool)}
+	content, err := txtparser.ParseYAMLFile(fileName)
+	if err != nil {
+		return prof, err
+	}
+	for key, value := range content {
+		var ok bool
+		switch key {
+		case profSolutions:
+			prof.Solutions, ok = profileList(value)
+		case profNotes:
+			prof.Notes, ok = profileList(value)
+		case profOrder:
+			prof.Order, ok = profileList(value)
+		case profOverrides:
+			var overrides map[string]interface{}
+			if overrides, ok = value.(map[string]interface{}); !ok {
+				ok = value == ""
+			}
+			for noteID, ovContent := range overrides {
+				if prof.Overrides[noteID], ok = ovContent.(string); !ok {
+					return prof, fmt.Errorf("override of note '%s' needs to be a text block", noteID)
+				}
+			}
+		case profStaging:
+			prof.Staging, ok = value.(string)
+		case profColorScheme:
+			prof.ColorScheme, ok = value.(string)
+		default:
+			return prof, fmt.Errorf("unknown key '%s'", key)
+		}
+		if !ok {
+			return prof, fmt.Errorf("wrong value type for key '%s'", key)
+		}
+		prof.keys[key] = true
+	}
+	return prof, nil
+}
+
+// profileList returns the value of a profile key as list. An empty value
+// is an empty list
+func profileList(value interface{}) ([]string, bool) {
+	switch val := value.(type) {
+	case []string:
+		return val, true
+	case string:
+		if val == "" {
+			return []string{}, true
+		}
+	}
+	return nil, false
+}
+
+// checkHostProfile checks, if the profile is applicable to the system
+func checkHostProfile(prof hostProfile, tuneApp *app.App) error {
+	for _, solName := range prof.Solutions {
+		if _, ok := tuneApp.AllSolutions[solName]; !ok {
+			return fmt.Errorf("solution '%s' is not available", solName)
+		}
+	}
+	for _, noteID := range append(append([]string{}, prof.Notes...), prof.Order...) {
+		if _, err := tuneApp.GetNoteByID(noteID); err != nil {
+			return err
+		}
+	}
+	for noteID := range prof.Overrides {
+		if _, err := tuneApp.GetNoteByID(noteID); err != nil {
+			return fmt.Errorf("override for unknown note - %v", err)
+		}
+	}
+	if prof.keys[profOrder] {
+		if dup := firstDuplicate(prof.Order); dup != "" {
+			return fmt.Errorf("note '%s' is listed twice in '%s'", dup, profOrder)
+		}
+		wanted := profileNotes(prof, tuneApp)
+		sort.Strings(wanted)
+		order := append([]string{}, prof.Order...)
+		sort.Strings(order)
+		if strings.Join(wanted, " ") != strings.Join(order, " ") {
+			return fmt.Errorf("'%s' needs to contain exactly the notes of the solutions and the additional notes (%s)", profOrder, strings.Join(wanted, " "))
+		}
+	}
+	if prof.keys[profStaging] && prof.Staging != "true" && prof.Staging != "false" {
+		return fmt.Errorf("wrong value '%s' for '%s'. Needs to be 'true' or 'false'", prof.Staging, profStaging)
+	}
+	if prof.keys[profColorScheme] && prof.ColorScheme != "" && !isColorScheme(prof.ColorScheme) {
+		return fmt.Errorf("unknown color scheme '%s'", prof.ColorScheme)
+	}
+	return nil
+}
+
+// profileSolutions returns the solutions, which should be enabled
+func profileSolutions(prof hostProfile, tuneApp *app.App) []string {
+	if prof.keys[profSolutions] {
+		return prof.Solutions
+	}
+	return tuneApp.TuneForSolutions
+}
+
+// profileExtraNotes returns the additional notes, which should be enabled
+func profileExtraNotes(prof hostProfile, tuneApp *app.App) []string {
+	if prof.keys[profNotes] {
+		return prof.Notes
+	}
+	return tuneApp.TuneForNotes
+}
+
+// profileNotes returns all notes, which should be enabled - the notes of
+// the solutions and the additional notes - in the order, in which new notes
+// will be applied
+func profileNotes(prof hostProfile, tuneApp *app.App) []string {
+	notes := []string{}
+	for _, solName := range profileSolutions(prof, tuneApp) {
+		for _, noteID := range tuneApp.AllSolutions[solName] {
+			if !system.IsInList(noteID, notes) {
+				notes = append(notes, noteID)
+			}
+		}
+	}
+	for _, noteID := range profileExtraNotes(prof, tuneApp) {
+		if !system.IsInList(noteID, notes) {
+			notes = append(notes, noteID)
+		}
+	}
+	return notes
+}
+
+// profileOrder returns the wanted note apply order. Without order in the
+// profile the enabled notes keep their position and the new notes follow
+// in the order of their solutions
+func profileOrder(prof hostProfile, tuneApp *app.App) ([]string, error) {
+	if prof.keys[profOrder] {
+		return prof.Order, nil
+	}
+	wanted := profileNotes(prof, tuneApp)
+	order := []string{}
+	for _, noteID := range tuneApp.NoteApplyOrder {
+		if system.IsInList(noteID, wanted) {
+			order = append(order, noteID)
+		}
+	}
+	newNotes := []string{}
+	for _, noteID := range wanted {
+		if !system.IsInList(noteID, order) {
+			newNotes = append(newNotes, noteID)
+		}
+	}
+	newNotes, err := tuneApp.OrderByDependencies(newNotes)
+	return append(order, newNotes...), err
+}
+
+// writeProfileOverrides writes the note override files of the profile and
+// removes the override files of notes not listed in the profile.
+// Returns the notes with changed override files
+func writeProfileOverrides(prof hostProfile) ([]string, error) {
+	changed := []string{}
+	if !prof.keys[profOverrides] {
+		return changed, nil
+	}
+	if err := os.MkdirAll(OverrideTuningSheets, 0755); err != nil {
+		return changed, err
+	}
+	entries, err := ioutil.ReadDir(OverrideTuningSheets)
+	if err != nil {
+		return changed, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".sol") {
+			continue
+		}
+		if _, ok := prof.Overrides[entry.Name()]; ok {
+			continue
+		}
+		system.NoticeLog("removing override file of note '%s'", entry.Name())
+		if err := os.Remove(path.Join(OverrideTuningSheets, entry.Name())); err != nil {
+			return changed, err
+		}
+		changed = append(changed, entry.Name())
+	}
+	for noteID, content := range prof.Overrides {
+		ovFile := path.Join(OverrideTuningSheets, noteID)
+		if old, err := ioutil.ReadFile(ovFile); err == nil && strings.TrimRight(string(old), "\n") == strings.TrimRight(content, "\n") {
+			continue
+		}
+		system.NoticeLog("writing override file of note '%s'", noteID)
+		if err := ioutil.WriteFile(ovFile, []byte(content), 0644); err != nil {
+			return changed, err
+		}
+		changed = append(changed, noteID)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// convergeHostProfile enables and applies the solutions and notes of the
+// profile in the wanted order and reverts all other notes.
+// Enabled notes are kept as long as the order of the enabled notes matches
+// the beginning of the wanted order. All following notes and notes with
+// changed override files are reverted and applied again
+func convergeHostProfile(writer io.Writer, prof hostProfile, changed []string, tuneApp *app.App) error {
+	order, err := profileOrder(prof, tuneApp)
+	if err != nil {
+		return err
+	}
+	keep := 0
+	for keep < len(order) && keep < len(tuneApp.NoteApplyOrder) && order[keep] == tuneApp.NoteApplyOrder[keep] && !system.IsInList(order[keep], changed) {
+		keep++
+	}
+	revert := append([]string{}, tuneApp.NoteApplyOrder[keep:]...)
+	for i := len(revert) - 1; i >= 0; i-- {
+		fmt.Fprintf(writer, "Reverting note %s\n", revert[i])
+		if err := tuneApp.RevertNote(revert[i], true); err != nil {
+			return err
+		}
+	}
+	solutions := append([]string{}, profileSolutions(prof, tuneApp)...)
+	sort.Strings(solutions)
+	tuneApp.TuneForSolutions = solutions
+	solNotes := tuneApp.GetSortedSolutionEnabledNotes()
+	tuneApp.TuneForNotes = []string{}
+	for _, noteID := range profileExtraNotes(prof, tuneApp) {
+		if !system.IsInList(noteID, solNotes) && !system.IsInList(noteID, tuneApp.TuneForNotes) {
+			tuneApp.TuneForNotes = append(tuneApp.TuneForNotes, noteID)
+		}
+	}
+	sort.Strings(tuneApp.TuneForNotes)
+	if err := tuneApp.SaveConfig(); err != nil {
+		return err
+	}
+	for _, noteID := range order[keep:] {
+		fmt.Fprintf(writer, "Applying note %s\n", noteID)
+		if err := tuneApp.TuneNote(noteID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeProfileSysconfig writes the staging and color scheme setting of the
+// profile to the saptune configuration file
+func writeProfileSysconfig(prof hostProfile) error {
+	if !prof.keys[profStaging] && !prof.keys[profColorScheme] {
+		return nil
+	}
+	sconf, err := txtparser.ParseSysconfigFile(saptuneSysconfig, true)
+	if err != nil {
+		return err
+	}
+	if prof.keys[profStaging] {
+		sconf.Set("STAGING", prof.Staging)
+	}
+	if prof.keys[profColorScheme] {
+		sconf.Set("COLOR_SCHEME", prof.ColorScheme)
+	}
+	return ioutil.WriteFile(saptuneSysconfig, []byte(sconf.ToText()), 0644)
+}
+
+// hostProfileToYAML returns the profile in YAML format
+func hostProfileToYAML(prof hostProfile) string {
+	var out strings.Builder
+	out.WriteString("# saptune host profile\n# apply with 'saptune configure --from <file>'\n")
+	for _, entry := range []struct {
+		key  string
+		list []string
+	}{{profSolutions, prof.Solutions}, {profNotes, prof.Notes}, {profOrder, prof.Order}} {
+		if len(entry.list) == 0 {
+			fmt.Fprintf(&out, "%s: []\n", entry.key)
+			continue
+		}
+		fmt.Fprintf(&out, "%s:\n", entry.key)
+		for _, item := range entry.list {
+			fmt.Fprintf(&out, "  - %s\n", txtparser.YAMLScalar(item))
+		}
+	}
+	if len(prof.Overrides) == 0 {
+		fmt.Fprintf(&out, "%s: {}\n", profOverrides)
+	} else {
+		fmt.Fprintf(&out, "%s:\n", profOverrides)
+		noteIDs := make([]string, 0, len(prof.Overrides))
+		for noteID := range prof.Overrides {
+			noteIDs = append(noteIDs, noteID)
+		}
+		sort.Strings(noteIDs)
+		for _, noteID := range noteIDs {
+			fmt.Fprintf(&out, "  %s: %s", txtparser.YAMLScalar(noteID), txtparser.YAMLBlock(prof.Overrides[noteID], 4))
+		}
+	}
+	fmt.Fprintf(&out, "%s: %s\n", profStaging, prof.Staging)
+	fmt.Fprintf(&out, "%s: %s\n", profColorScheme, txtparser.YAMLScalar(prof.ColorScheme))
+	return out.String()
+}
+
+// firstDuplicate returns the first entry, which is listed twice
+func firstDuplicate(list []string) string {
+	seen := make(map[string]bool)
+	for _, entry := range list {
+		if seen[entry] {
+			return entry
+		}
+		seen[entry] = true
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAndCheckHostProfile(t *testing.T) {
+	profFile := "/tmp/saptune_tst_profile.yaml"
+	defer os.Remove(profFile)
+
+	content := "solutions: [sol1]\nnotes:\n  - extraNote\norder: [simpleNote, extraNote]\noverrides:\n  extraNote: |\n    [sysctl]\n    vm.swappiness = 10\nstaging: false\n"
+	_ = ioutil.WriteFile(profFile, []byte(content), 0644)
+	prof, err := readHostProfile(profFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(prof.Solutions, []string{"sol1"}) || !reflect.DeepEqual(prof.Notes, []string{"extraNote"}) || prof.Overrides["extraNote"] != "[sysctl]\nvm.swappiness = 10\n" || prof.Staging != "false" {
+		t.Errorf("got: '%+v'\n", prof)
+	}
+	if prof.keys[profColorScheme] {
+		t.Errorf("expected key '%s' to be unset\n", profColorScheme)
+	}
+	if err := checkHostProfile(prof, tApp); err != nil {
+		t.Error(err)
+	}
+	order, err := profileOrder(prof, tApp)
+	if err != nil || !reflect.DeepEqual(order, []string{"simpleNote", "extraNote"}) {
+		t.Errorf("got: '%+v', '%v'\n", order, err)
+	}
+
+	// order without the solution note
+	prof.Order = []string{"extraNote"}
+	if err := checkHostProfile(prof, tApp); err == nil || !strings.Contains(err.Error(), "needs to contain exactly") {
+		t.Errorf("expected an order error, got '%v'\n", err)
+	}
+	// without order the new notes follow the solution order
+	delete(prof.keys, profOrder)
+	order, err = profileOrder(prof, tApp)
+	if err != nil || !reflect.DeepEqual(order, []string{"simpleNote", "extraNote"}) {
+		t.Errorf("got: '%+v', '%v'\n", order, err)
+	}
+
+	for _, wrong := range []string{"solutions: [unknownSol]\n", "notes: [unknownNote]\n", "staging: maybe\n", "color_scheme: rainbow\n"} {
+		_ = ioutil.WriteFile(profFile, []byte(wrong), 0644)
+		prof, err = readHostProfile(profFile)
+		if err != nil {
+			t.Error(err)
+		}
+		if err := checkHostProfile(prof, tApp); err == nil {
+			t.Errorf("expected an error for '%s'\n", wrong)
+		}
+	}
+	for _, wrong := range []string{"unknown: 1\n", "solutions:\n  sol1: 1\n", "overrides: [extraNote]\n"} {
+		_ = ioutil.WriteFile(profFile, []byte(wrong), 0644)
+		if _, err = readHostProfile(profFile); err == nil {
+			t.Errorf("expected an error for '%s'\n", wrong)
+		}
+	}
+}
+
+func TestHostProfileToYAML(t *testing.T) {
+	prof := hostProfile{
+		Solutions:   []string{"sol1"},
+		Notes:       []string{},
+		Order:       []string{"simpleNote"},
+		Overrides:   map[string]string{"simpleNote": "[sysctl]\nvm.swappiness = 10\n"},
+		Staging:     "true",
+		ColorScheme: "",
+	}
+	want := `# saptune host profile
+# apply with 'saptune configure --from <file>'
+solutions:
+  - sol1
+notes: []
+order:
+  - simpleNote
+overrides:
+  simpleNote: |
+    [sysctl]
+    vm.swappiness = 10
+staging: true
+color_scheme: ""
+`
+	txt := hostProfileToYAML(prof)
+	checkOut(t, txt, want)
+
+	// read back the exported profile
+	profFile := "/tmp/saptune_tst_export.yaml"
+	defer os.Remove(profFile)
+	_ = ioutil.WriteFile(profFile, []byte(txt), 0644)
+	got, err := readHostProfile(profFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.keys = nil
+	if !reflect.DeepEqual(got, prof) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", got, prof)
+	}
+}
+
+func TestWriteProfileOverrides(t *testing.T) {
+	oldOverrideDir := OverrideTuningSheets
+	defer func() { OverrideTuningSheets = oldOverrideDir }()
+	OverrideTuningSheets = "/tmp/saptune_tst_override/"
+	defer os.RemoveAll(OverrideTuningSheets)
+	_ = os.MkdirAll(OverrideTuningSheets, 0755)
+	_ = ioutil.WriteFile(OverrideTuningSheets+"oldNote", []byte("[sysctl]\n"), 0644)
+	_ = ioutil.WriteFile(OverrideTuningSheets+"keepNote", []byte("[sysctl]\nvm.swappiness = 10\n"), 0644)
+	_ = ioutil.WriteFile(OverrideTuningSheets+"sol1.sol", []byte("[ArchX86]\n"), 0644)
+
+	// overrides not part of the profile - nothing to do
+	prof := hostProfile{keys: map[string]bool{}}
+	changed, err := writeProfileOverrides(prof)
+	if err != nil || len(changed) != 0 {
+		t.Errorf("got: '%+v', '%v'\n", changed, err)
+	}
+
+	prof = hostProfile{Overrides: map[string]string{"keepNote": "[sysctl]\nvm.swappiness = 10", "newNote": "[sysctl]\nvm.swappiness = 20\n"}, keys: map[string]bool{profOverrides: true}}
+	changed, err = writeProfileOverrides(prof)
+	if err != nil || !reflect.DeepEqual(changed, []string{"newNote", "oldNote"}) {
+		t.Errorf("got: '%+v', '%v'\n", changed, err)
+	}
+	if _, err := os.Stat(OverrideTuningSheets + "oldNote"); !os.IsNotExist(err) {
+		t.Errorf("override file of 'oldNote' still exists\n")
+	}
+	if _, err := os.Stat(OverrideTuningSheets + "sol1.sol"); err != nil {
+		t.Errorf("solution override file removed\n")
+	}
+	if content, _ := ioutil.ReadFile(OverrideTuningSheets + "newNote"); string(content) != "[sysctl]\nvm.swappiness = 20\n" {
+		t.Errorf("got: '%s'\n", string(content))
+	}
+}
@@ -0,0 +1,187 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// drift check settings in /etc/sysconfig/saptune
+const (
+	driftIntervalKey = "DRIFT_CHECK_INTERVAL"
+	driftActionKey   = "DRIFT_ACTION"
+	driftReport      = "report"
+	driftReapply     = "reapply"
+)
+
+// driftWatchFiles are the files and directories, which are watched for
+// changes to trigger an additional drift check between the regular checks.
+// /proc/sys can not be watched by inotify, so these values are only
+// checked periodically
+var driftWatchFiles = []string{"/etc/sysctl.conf", "/etc/sysctl.d", "/run/sysctl.d", "/usr/lib/sysctl.d", "/etc/tuned/active_profile", "/etc/security/limits.d", "/etc/systemd/logind.conf.d", "/etc/saptune/override", "/etc/saptune/extra", "/etc/sysconfig/saptune"}
+
+// driftSettleTime is the time to wait after a watched file was changed
+// before the drift check starts, to catch a bunch of changes at once
+var driftSettleTime = 5 * time.Second
+
+// ServiceActionDriftCheck is only used by the saptune drift check service,
+// hence it is not advertised to the end user.
+// It periodically verifies all enabled notes and reports or re-applies
+// parameters, which have drifted away from the tuned values
+func ServiceActionDriftCheck(tuneApp *app.App) {
+	interval, action := getDriftSettings(saptuneSysconfig)
+	if interval <= 0 {
+		system.NoticeLog("drift check disabled (%s is not set or '0' in '%s'), nothing to do.", driftIntervalKey, saptuneSysconfig)
+		return
+	}
+	// the drift check runs for the whole lifetime of the service, so
+	// release the lock and only lock saptune during a single check
+	system.ReleaseSaptuneLock()
+	system.NoticeLog("starting drift check every %v seconds with action '%s'", interval, action)
+
+	var events <-chan string
+	watcher, err := system.NewFileWatcher(driftWatchFiles)
+	if err != nil {
+		system.WarningLog("watching configuration files for changes not possible, only checking periodically - %v", err)
+	} else {
+		defer watcher.Close()
+		events = watcher.Events
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			system.NoticeLog("drift check stopped")
+			return
+		case <-ticker.C:
+		case file, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			system.InfoLog("'%s' changed, triggering drift check", file)
+			time.Sleep(driftSettleTime)
+		}
+		if !system.TrySaptuneLock() {
+			system.InfoLog("saptune currently in use, skipping drift check")
+			continue
+		}
+		_, action = getDriftSettings(saptuneSysconfig)
+		driftCheck(tuneApp, action)
+		system.ReleaseSaptuneLock()
+	}
+}
+
+// getDriftSettings reads the drift check interval (seconds) and the
+// action from the saptune configuration file
+func getDriftSettings(sysconfig string) (int, string) {
+	interval := 0
+	action := driftReport
+	sconf, err := txtparser.ParseSysconfigFile(sysconfig, false)
+	if err != nil {
+		return interval, action
+	}
+	interval = sconf.GetInt(driftIntervalKey, 0)
+	switch act := sconf.GetString(driftActionKey, driftReport); act {
+	case driftReport, driftReapply:
+		action = act
+	default:
+		system.WarningLog("wrong value '%s' for '%s' in '%s', using '%s'", act, driftActionKey, sysconfig, driftReport)
+	}
+	return interval, action
+}
+
+// driftCheck verifies all enabled notes and handles the drifted parameters
+// according to the configured action
+func driftCheck(tuneApp *app.App, action string) map[string][]string {
+	// re-read the configuration as notes may be applied or reverted
+	// since the last check
+	system.CleanUpRun()
+	tApp := app.InitialiseApp(tuneApp.SysconfigPrefix, tuneApp.State.StateDirPrefix, tuneApp.AllNotes, tuneApp.AllSolutions)
+	if tApp.AppliedNotes() == "" {
+		return map[string][]string{}
+	}
+	oldStdout, oldSdterr := system.SwitchOffOut()
+	unsatisfiedNotes, comparisons, err := tApp.VerifyAll()
+	system.SwitchOnOut(oldStdout, oldSdterr)
+	if err != nil {
+		system.WarningLog("drift check failed to verify the current system: %v", err)
+		return map[string][]string{}
+	}
+	drifted := collectDrift(unsatisfiedNotes, comparisons)
+	for _, noteID := range tApp.NoteApplyOrder {
+		params, ok := drifted[noteID]
+		if !ok {
+			continue
+		}
+		reportDrift(noteID, params, comparisons[noteID], action)
+		if action != driftReapply {
+			continue
+		}
+		if _, applied := tApp.IsNoteApplied(noteID); !applied {
+			continue
+		}
+		if err := tApp.TuneNote(noteID); err != nil {
+			system.ErrorLog("re-applying note '%s' after drift failed - %v", noteID, err)
+			_ = system.JournalMsg(system.JournalPrioErr, fmt.Sprintf("saptune: re-applying note %s after drift failed - %v", noteID, err), map[string]string{"SAPTUNE_NOTE": noteID})
+			continue
+		}
+		system.NoticeLog("note '%s' re-applied after drift of %s", noteID, strings.Join(params, ", "))
+	}
+	return drifted
+}
+
+// collectDrift returns per note the parameters, which do not match the
+// expected values and can be applied by saptune.
+// Parameters only checked but never set by saptune (rpm, grub, filesystem
+// options) or not supported by the system are skipped.
+func collectDrift(unsatisfiedNotes []string, comparisons map[string]map[string]note.FieldComparison) map[string][]string {
+	drifted := make(map[string][]string)
+	for _, noteID := range unsatisfiedNotes {
+		params := []string{}
+		for _, comparison := range comparisons[noteID] {
+			if comparison.ReflectFieldName != "SysctlParams" || comparison.MatchExpectation {
+				continue
+			}
+			key := comparison.ReflectMapKey
+			actVal, _ := comparison.ActualValue.(string)
+			if actVal == "all:none" || actVal == "PNA" || actVal == "NA" || key == "VSZ_TMPFS_PERCENT" || key == "reminder" || strings.HasPrefix(key, "rpm:") || strings.HasPrefix(key, "grub:") || system.IsXFSOption.MatchString(key) {
+				continue
+			}
+			params = append(params, key)
+		}
+		if len(params) != 0 {
+			sort.Strings(params)
+			drifted[noteID] = params
+		}
+	}
+	return drifted
+}
+
+// reportDrift logs the drifted parameters of a note and sends a message
+// to the systemd journal
+func reportDrift(noteID string, params []string, comparisons map[string]note.FieldComparison, action string) {
+	details := []string{}
+	for _, param := range params {
+		comparison := comparisons[fmt.Sprintf("SysctlParams[%s]", param)]
+		details = append(details, fmt.Sprintf("%s (expected '%s', actual '%s')", param, comparison.ExpectedValueJS, comparison.ActualValueJS))
+	}
+	system.WarningLog("drift detected for note '%s': %s", noteID, strings.Join(details, ", "))
+	fields := map[string]string{
+		"SAPTUNE_NOTE":         noteID,
+		"SAPTUNE_PARAMETERS":   strings.Join(params, " "),
+		"SAPTUNE_DRIFT_ACTION": action,
+	}
+	_ = system.JournalMsg(system.JournalPrioWarning, fmt.Sprintf("saptune: drift detected for note %s: %s", noteID, strings.Join(details, ", ")), fields)
+}
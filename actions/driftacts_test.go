@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"github.com/SUSE/saptune/sap/note"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestGetDriftSettings(t *testing.T) {
+	tstFile := "/tmp/saptune_drift_sysconfig"
+	defer os.Remove(tstFile)
+
+	// missing file - drift check disabled
+	interval, action := getDriftSettings("/file_does_not_exist")
+	if interval != 0 || action != "report" {
+		t.Errorf("got: '%d', '%s', expected: '0', 'report'\n", interval, action)
+	}
+
+	_ = ioutil.WriteFile(tstFile, []byte("DRIFT_CHECK_INTERVAL=\"300\"\nDRIFT_ACTION=\"reapply\"\n"), 0644)
+	interval, action = getDriftSettings(tstFile)
+	if interval != 300 || action != "reapply" {
+		t.Errorf("got: '%d', '%s', expected: '300', 'reapply'\n", interval, action)
+	}
+
+	// wrong action falls back to 'report'
+	_ = ioutil.WriteFile(tstFile, []byte("DRIFT_CHECK_INTERVAL=\"60\"\nDRIFT_ACTION=\"ignore\"\n"), 0644)
+	interval, action = getDriftSettings(tstFile)
+	if interval != 60 || action != "report" {
+		t.Errorf("got: '%d', '%s', expected: '60', 'report'\n", interval, action)
+	}
+}
+
+func TestCollectDrift(t *testing.T) {
+	comparisons := map[string]map[string]note.FieldComparison{
+		"1680803": {
+			"SysctlParams[vm.swappiness]":       {ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.swappiness", ActualValue: "60", ExpectedValue: "10", MatchExpectation: false},
+			"SysctlParams[vm.dirty_bytes]":      {ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.dirty_bytes", ActualValue: "0", ExpectedValue: "0", MatchExpectation: true},
+			"SysctlParams[rpm:glibc]":           {ReflectFieldName: "SysctlParams", ReflectMapKey: "rpm:glibc", ActualValue: "2.22", ExpectedValue: "2.26", MatchExpectation: false},
+			"SysctlParams[grub:numa_balancing]": {ReflectFieldName: "SysctlParams", ReflectMapKey: "grub:numa_balancing", ActualValue: "NA", ExpectedValue: "disable", MatchExpectation: false},
+			"SysctlParams[governor]":            {ReflectFieldName: "SysctlParams", ReflectMapKey: "governor", ActualValue: "all:none", ExpectedValue: "performance", MatchExpectation: false},
+			"SysctlParams[IO_SCHEDULER_sda]":    {ReflectFieldName: "SysctlParams", ReflectMapKey: "IO_SCHEDULER_sda", ActualValue: "mq-deadline", ExpectedValue: "none", MatchExpectation: false},
+			"OverrideParams[vm.swappiness]":     {ReflectFieldName: "OverrideParams", ReflectMapKey: "vm.swappiness", ActualValue: "10", ExpectedValue: "10", MatchExpectation: false},
+		},
+		"2382421": {
+			"SysctlParams[net.core.somaxconn]": {ReflectFieldName: "SysctlParams", ReflectMapKey: "net.core.somaxconn", ActualValue: "4096", ExpectedValue: "4096", MatchExpectation: true},
+		},
+	}
+	drifted := collectDrift([]string{"1680803", "2382421"}, comparisons)
+	want := map[string][]string{"1680803": {"IO_SCHEDULER_sda", "vm.swappiness"}}
+	if !reflect.DeepEqual(drifted, want) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", drifted, want)
+	}
+	drifted = collectDrift([]string{}, comparisons)
+	if len(drifted) != 0 {
+		t.Errorf("got: '%+v', expected an empty map\n", drifted)
+	}
+}
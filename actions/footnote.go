@@ -1,12 +1,14 @@
 package actions
 
 import (
+	"fmt"
 	"github.com/SUSE/saptune/sap/note"
 	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"regexp"
-	"runtime"
 	"strconv"
 	"strings"
+	"time"
 )
 
 //define footnote texts
@@ -30,6 +32,9 @@ const (
 	footnote14   = "[14] the parameter value exceeds the maximum possible number of open files. Check and increase fs.nr_open if really needed."
 	footnote15   = "[15] the parameter is only used to calculate the size of tmpfs (/dev/shm)"
 	footnote16   = "[16] parameter not available on the system, setting not possible"
+	footnote17   = "[17] override has expired (see '@expires' in the override file), review the override value"
+	footnote18   = "[18] override of OVERMETA"
+	footnote19   = "[19] value calculated from TEMPLATE"
 )
 
 // set 'unsupported' footnote regarding the architecture
@@ -69,7 +74,7 @@ func prepareFootnote(comparison note.FieldComparison, compliant, comment, inform
 		return compliant, comment, footnote
 	}
 	// set 'unsupported' footnote regarding the architecture
-	if runtime.GOARCH == "ppc64le" {
+	if arch := system.GetArch(); arch == system.ArchPPC64LE || arch == system.ArchS390X {
 		footnote1 = footnote1IBM
 	}
 	if system.GetCSP() == "azure" {
@@ -292,6 +297,42 @@ func setMem(mapKey, compliant, comment string, footnote []string) (string, strin
 	return compliant, comment, footnote
 }
 
+// setOverMeta sets footnotes for the metadata of an overridden parameter
+// [17],[18] and returns the metadata for the machine readable output.
+// An expired override is not compliant
+func setOverMeta(mapKey, override, compliant, comment string, metas map[string]txtparser.OverrideMeta, footnote []string, now time.Time) (string, string, []string, *system.JOverMeta) {
+	meta, ok := txtparser.LookupOverrideMeta(metas, mapKey)
+	if override == "" || !ok {
+		return compliant, comment, footnote, nil
+	}
+	jMeta := &system.JOverMeta{Reason: meta.Reason, Expires: meta.Expires, ApprovedBy: meta.ApprovedBy, Expired: meta.IsExpired(now)}
+	if jMeta.Expired {
+		compliant = strings.Replace(compliant, "yes", "no ", 1)
+		compliant = compliant + " [17]"
+		comment = comment + " [17]"
+		footnote[16] = footnote17
+	}
+	if system.IsFlagSet("show-non-compliant") && !strings.Contains(compliant, "no") {
+		return compliant, comment, footnote, jMeta
+	}
+	compliant = compliant + " [18]"
+	comment = comment + " [18]"
+	footnote[17] = writeFN(footnote[17], footnote18, fmt.Sprintf("%s: %s", mapKey, meta.String()), "OVERMETA")
+	return compliant, comment, footnote, jMeta
+}
+
+// setTemplate sets footnote for parameter values calculated from a template
+// expression
+func setTemplate(mapKey, tmpl, compliant, comment string, footnote []string) (string, string, []string) {
+	if tmpl == "" || (system.IsFlagSet("show-non-compliant") && !strings.Contains(compliant, "no")) {
+		return compliant, comment, footnote
+	}
+	compliant = compliant + " [19]"
+	comment = comment + " [19]"
+	footnote[18] = writeFN(footnote[18], footnote19, fmt.Sprintf("%s: %s", mapKey, tmpl), "TEMPLATE")
+	return compliant, comment, footnote
+}
+
 // writeFN customizes the text for footnotes by replacing strings/placeholder
 func writeFN(footnote, fntxt, info, pat string) string {
 	if footnote == "" {
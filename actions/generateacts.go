@@ -0,0 +1,572 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/sap/solution"
+	"github.com/SUSE/saptune/system"
+	"io"
+	"strings"
+)
+
+// GenerateAction prints the shell completion or the man page synopsis
+// generated from the saptune command tree
+func GenerateAction(writer io.Writer, actionName, shell string) {
+	switch actionName {
+	case "completion":
+		switch shell {
+		case "bash":
+			fmt.Fprint(writer, BashCompletion())
+		case "zsh":
+			fmt.Fprint(writer, ZshCompletion())
+		case "fish":
+			fmt.Fprint(writer, FishCompletion())
+		default:
+			PrintHelpAndExit(writer, 1)
+		}
+	case "synopsis":
+		fmt.Fprint(writer, ManSynopsis())
+	default:
+		PrintHelpAndExit(writer, 1)
+	}
+}
+
+// completionLists are the shell commands listing the dynamic values offered
+// by the shell completion
+var completionLists = map[string]string{
+	"notes":     fmt.Sprintf(`ls -1q %s 2>/dev/null; find %s -name '*.conf' -printf '%%f\n' 2>/dev/null | sed 's/\.conf$//'`, solution.NoteTuningSheets, solution.ExtraTuningSheets),
+	"solutions": fmt.Sprintf(`find %s %s -name '*.sol' -printf '%%f\n' 2>/dev/null | sed 's/\.sol$//'`, solution.SolutionSheets, solution.ExtraTuningSheets),
+	"staging":   fmt.Sprintf(`ls -1q %s 2>/dev/null | cut -d '-' -f 1`, StagingSheets),
+	"baselines": fmt.Sprintf(`ls -1q %s 2>/dev/null | sed 's/\.json$//'`, BaselineArea),
+}
+
+// completionWords returns the fixed values offered by the shell completion
+// for a completion kind
+func completionWords(kind string) []string {
+	switch kind {
+	case "formats":
+		return system.OutputFormats()
+	case "colorschemes":
+		return colorSchemes
+	case "sections":
+		return verifySections
+	case "compliance":
+		return complianceValues
+	case "severities":
+		return note.Severities
+	}
+	return []string{}
+}
+
+// completionListKinds are the dynamic completion kinds in a stable order
+var completionListKinds = []string{"notes", "solutions", "staging", "baselines"}
+
+// cmdPath is a command of the command tree together with its command line
+// words (e.g. 'note customise' and 'note customize')
+type cmdPath struct {
+	node  *system.CmdNode
+	names []string
+}
+
+// visibleCmdPaths returns all realms and commands, which are offered by the
+// shell completion
+func visibleCmdPaths() []cmdPath {
+	paths := []cmdPath{}
+	for _, realm := range system.CmdTree {
+		if realm.Hidden {
+			continue
+		}
+		paths = append(paths, cmdPath{node: realm, names: append([]string{realm.Name}, realm.Aliases...)})
+		for _, cmd := range realm.VisibleCommands() {
+			names := []string{}
+			for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+				names = append(names, realm.Name+" "+name)
+			}
+			paths = append(paths, cmdPath{node: cmd, names: names})
+		}
+	}
+	return paths
+}
+
+// flagSpellings returns all command line spellings of a flag
+func flagSpellings(flag *system.CmdFlag) []string {
+	spellings := []string{}
+	for _, name := range flag.Spellings {
+		if len(name) > 1 {
+			spellings = append(spellings, "--"+name)
+		}
+		spellings = append(spellings, "-"+name)
+	}
+	return spellings
+}
+
+// separateFlagPattern returns the shell case pattern matching the flags,
+// which get their value as next command line argument
+func separateFlagPattern() string {
+	spellings := []string{}
+	for _, flag := range system.CliFlags {
+		if flag.Separate {
+			spellings = append(spellings, flagSpellings(flag)...)
+		}
+	}
+	return strings.Join(spellings, "|")
+}
+
+// shellWords returns the shell words offered for a completion kind and
+// additional fixed values. Dynamic lists are read by '_saptune_list'
+func shellWords(kind string, values []string) string {
+	words := append([]string{}, values...)
+	words = append(words, completionWords(kind)...)
+	if _, ok := completionLists[kind]; ok {
+		words = append(words, "$(_saptune_list "+kind+")")
+	}
+	return strings.Join(words, " ")
+}
+
+// shellFlags returns the flags of a node offered by the shell completion.
+// Flags, which need their value with '=', are returned separately
+func shellFlags(flags []string) (string, string) {
+	plain := []string{}
+	withValue := []string{}
+	for _, name := range flags {
+		for _, flag := range system.CliFlags {
+			if flag.Name != name {
+				continue
+			}
+			if flag.Value != "" && !flag.Separate {
+				withValue = append(withValue, "--"+flag.Spellings[0]+"=")
+			} else {
+				plain = append(plain, "--"+flag.Spellings[0])
+			}
+		}
+	}
+	return strings.Join(plain, " "), strings.Join(withValue, " ")
+}
+
+// shellCompletionBody returns the part of the completion function, which is
+// common to bash and zsh. It expects 'prev', 'key' (realm and command) and
+// 'n' (number of command arguments before the current word) and sets
+// 'opts', 'flags', 'eqflags' and 'files'
+func shellCompletionBody() string {
+	body := &strings.Builder{}
+	fmt.Fprintf(body, "    case \"${prev}\" in\n")
+	for _, flag := range system.CliFlags {
+		if flag.Value == "" {
+			continue
+		}
+		fmt.Fprintf(body, "        %s)\n", strings.Join(flagSpellings(flag), "|"))
+		switch flag.Complete {
+		case "files":
+			fmt.Fprintf(body, "            files=1\n")
+		case "":
+			fmt.Fprintf(body, "            return 0\n")
+		default:
+			fmt.Fprintf(body, "            opts=\"%s\"\n", shellWords(flag.Complete, nil))
+		}
+		fmt.Fprintf(body, "            ;;\n")
+	}
+	fmt.Fprintf(body, "        *)\n")
+	fmt.Fprintf(body, "            case \"${key}\" in\n")
+	globalFlags := []string{}
+	for _, flag := range system.CliFlags {
+		if flag.Global {
+			globalFlags = append(globalFlags, flag.Name)
+		}
+	}
+	realms := []string{}
+	for _, realm := range system.CmdTree {
+		if !realm.Hidden {
+			realms = append(realms, realm.Name)
+		}
+	}
+	plain, withValue := shellFlags(globalFlags)
+	fmt.Fprintf(body, "                \"\")\n")
+	fmt.Fprintf(body, "                    opts=\"%s\"\n", strings.Join(realms, " "))
+	fmt.Fprintf(body, "                    flags=\"%s\"\n", plain)
+	fmt.Fprintf(body, "                    eqflags=\"%s\"\n", withValue)
+	fmt.Fprintf(body, "                    ;;\n")
+	for _, cmd := range visibleCmdPaths() {
+		fmt.Fprintf(body, "                \"%s\")\n", strings.Join(cmd.names, "\"|\""))
+		if cmds := cmd.node.VisibleCommands(); len(cmds) > 0 {
+			names := []string{}
+			for _, sub := range cmds {
+				names = append(names, sub.Name)
+			}
+			fmt.Fprintf(body, "                    opts=\"%s\"\n", strings.Join(names, " "))
+			fmt.Fprintf(body, "                    ;;\n")
+			continue
+		}
+		plain, withValue := shellFlags(cmd.node.Flags)
+		if plain != "" {
+			fmt.Fprintf(body, "                    flags=\"%s\"\n", plain)
+		}
+		if withValue != "" {
+			fmt.Fprintf(body, "                    eqflags=\"%s\"\n", withValue)
+		}
+		if len(cmd.node.Args) > 0 {
+			fmt.Fprintf(body, "                    case ${n} in\n")
+			for i, arg := range cmd.node.Args {
+				pattern := fmt.Sprintf("%d", i)
+				if arg.Multi {
+					pattern = "*"
+				}
+				switch {
+				case arg.Complete == "files":
+					fmt.Fprintf(body, "                        %s)  files=1 ;;\n", pattern)
+				case arg.Complete != "" || len(arg.Values) > 0:
+					fmt.Fprintf(body, "                        %s)  opts=\"%s\" ;;\n", pattern, shellWords(arg.Complete, arg.Values))
+				}
+			}
+			fmt.Fprintf(body, "                    esac\n")
+		}
+		fmt.Fprintf(body, "                    ;;\n")
+	}
+	fmt.Fprintf(body, "            esac\n")
+	fmt.Fprintf(body, "            ;;\n")
+	fmt.Fprintf(body, "    esac\n")
+	return body.String()
+}
+
+// shellListFunction returns the shell function '_saptune_list' printing
+// the dynamic completion values
+func shellListFunction() string {
+	list := &strings.Builder{}
+	fmt.Fprintf(list, "_saptune_list() {\n    case \"$1\" in\n")
+	for _, kind := range completionListKinds {
+		fmt.Fprintf(list, "        %s)\n            %s\n            ;;\n", kind, completionLists[kind])
+	}
+	fmt.Fprintf(list, "    esac\n}\n")
+	return list.String()
+}
+
+// realmsWithCommands returns the shell case pattern matching all realms,
+// which have commands
+func realmsWithCommands() string {
+	realms := []string{}
+	for _, realm := range system.CmdTree {
+		if len(realm.Commands) > 0 {
+			realms = append(realms, realm.Name)
+		}
+	}
+	return strings.Join(realms, "|")
+}
+
+// BashCompletion returns the bash completion script
+func BashCompletion() string {
+	script := &strings.Builder{}
+	fmt.Fprintf(script, "# bash completion for saptune(8)\n# generated by 'saptune generate completion bash' - do not edit\n\n")
+	fmt.Fprint(script, shellListFunction())
+	fmt.Fprintf(script, `
+_saptune() {
+    local cur prev word key n i skip opts flags eqflags files
+    local -a pos
+    COMPREPLY=()
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    opts=""
+    flags=""
+    eqflags=""
+    files=0
+    # bash splits '--format=json' into '--format', '=' and 'json'
+    if [ "${cur}" = "=" ]; then
+        cur=""
+    elif [ "${prev}" = "=" ] && [ ${COMP_CWORD} -gt 1 ]; then
+        prev="${COMP_WORDS[COMP_CWORD-2]}"
+    fi
+
+    # positional arguments before the current word
+    pos=()
+    skip=0
+    for (( i=1; i < COMP_CWORD; i++ )); do
+        word="${COMP_WORDS[i]}"
+        if [ ${skip} -eq 1 ]; then
+            skip=0
+            continue
+        fi
+        case "${word}" in
+            %s|=)
+                skip=1
+                ;;
+            -*)
+                ;;
+            *)
+                pos+=("${word}")
+                ;;
+        esac
+    done
+    key=""
+    n=0
+    if [ ${#pos[@]} -gt 0 ]; then
+        case "${pos[0]}" in
+            %s)
+                key="${pos[*]:0:2}"
+                n=$(( ${#pos[@]} - 2 ))
+                ;;
+            *)
+                key="${pos[0]}"
+                n=$(( ${#pos[@]} - 1 ))
+                ;;
+        esac
+    fi
+
+`, separateFlagPattern(), realmsWithCommands())
+	fmt.Fprint(script, shellCompletionBody())
+	fmt.Fprint(script, `
+    if [[ "${cur}" == -* ]]; then
+        COMPREPLY=($(compgen -W "${flags} ${eqflags} --help" -- "${cur}"))
+    elif [ ${files} -eq 1 ]; then
+        COMPREPLY=($(compgen -f -- "${cur}"))
+    else
+        COMPREPLY=($(compgen -W "${opts}" -- "${cur}"))
+    fi
+    if [ ${#COMPREPLY[@]} -eq 1 ] && [[ "${COMPREPLY[0]}" == *= ]]; then
+        compopt -o nospace 2>/dev/null
+    fi
+    return 0
+}
+
+complete -F _saptune saptune
+`)
+	return script.String()
+}
+
+// ZshCompletion returns the zsh completion script
+func ZshCompletion() string {
+	script := &strings.Builder{}
+	fmt.Fprintf(script, "#compdef saptune\n# zsh completion for saptune(8)\n# generated by 'saptune generate completion zsh' - do not edit\n\n")
+	fmt.Fprint(script, shellListFunction())
+	fmt.Fprintf(script, `
+_saptune() {
+    local cur prev word key n i skip opts flags eqflags files
+    local -a pos
+    cur="${words[CURRENT]}"
+    prev="${words[CURRENT-1]}"
+    opts=""
+    flags=""
+    eqflags=""
+    files=0
+    if [[ "${cur}" == -*=* ]]; then
+        # value of '--format=json'
+        prev="${cur%%%%=*}"
+        compset -P '*='
+        cur="${cur#*=}"
+    fi
+
+    # positional arguments before the current word
+    pos=()
+    skip=0
+    for (( i=2; i < CURRENT; i++ )); do
+        word="${words[i]}"
+        if [ ${skip} -eq 1 ]; then
+            skip=0
+            continue
+        fi
+        case "${word}" in
+            %s)
+                skip=1
+                ;;
+            -*)
+                ;;
+            *)
+                pos+=("${word}")
+                ;;
+        esac
+    done
+    key=""
+    n=0
+    if [ ${#pos} -gt 0 ]; then
+        case "${pos[1]}" in
+            %s)
+                key="${pos[1]} ${pos[2]}"
+                key="${key%% }"
+                n=$(( ${#pos} - 2 ))
+                ;;
+            *)
+                key="${pos[1]}"
+                n=$(( ${#pos} - 1 ))
+                ;;
+        esac
+    fi
+
+`, separateFlagPattern(), realmsWithCommands())
+	fmt.Fprint(script, shellCompletionBody())
+	fmt.Fprint(script, `
+    if [[ "${cur}" == -* ]]; then
+        compadd -- ${=flags} --help
+        compadd -S '' -- ${=eqflags}
+    elif [ ${files} -eq 1 ]; then
+        _files
+    else
+        compadd -- ${=opts}
+    fi
+}
+
+if [ "${funcstack[1]}" = "_saptune" ]; then
+    _saptune "$@"
+else
+    compdef _saptune saptune
+fi
+`)
+	return script.String()
+}
+
+// fishQuote quotes a string for the fish shell
+func fishQuote(str string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(str) + "'"
+}
+
+// fishFlag returns the fish 'complete' options of a flag
+func fishFlag(flag *system.CmdFlag) string {
+	opts := ""
+	for _, name := range flag.Spellings[:1] {
+		if len(name) == 1 {
+			opts = opts + " -s " + name
+		} else {
+			opts = opts + " -l " + name
+		}
+	}
+	if flag.Value != "" {
+		switch flag.Complete {
+		case "files":
+			opts = opts + " -r -F"
+		case "":
+			opts = opts + " -x"
+		default:
+			opts = opts + " -x -a " + fishWords(flag.Complete, nil)
+		}
+	}
+	return opts + " -d " + fishQuote(flag.Help)
+}
+
+// fishWords returns the fish argument list for a completion kind and
+// additional fixed values
+func fishWords(kind string, values []string) string {
+	words := append([]string{}, values...)
+	words = append(words, completionWords(kind)...)
+	if _, ok := completionLists[kind]; ok {
+		words = append(words, "(__saptune_list "+kind+")")
+	}
+	return "\"" + strings.Join(words, " ") + "\""
+}
+
+// FishCompletion returns the fish completion script
+func FishCompletion() string {
+	script := &strings.Builder{}
+	fmt.Fprintf(script, "# fish completion for saptune(8)\n# generated by 'saptune generate completion fish' - do not edit\n\n")
+	fmt.Fprintf(script, "function __saptune_list\n    switch $argv[1]\n")
+	for _, kind := range completionListKinds {
+		fmt.Fprintf(script, "        case %s\n            sh -c %s\n", kind, fishQuote(completionLists[kind]))
+	}
+	fmt.Fprintf(script, "    end\nend\n")
+	fmt.Fprintf(script, `
+# positional arguments before the current word
+function __saptune_pos
+    set -l skip 0
+    for word in (commandline -opc)[2..-1]
+        if test $skip -eq 1
+            set skip 0
+            continue
+        end
+        switch $word
+            case %s
+                set skip 1
+            case '-*'
+            case '*'
+                echo $word
+        end
+    end
+end
+
+# __saptune_at checks the realm and command and the number of the command
+# arguments: __saptune_at 'note verify' [NUMBER|+NUMBER]
+function __saptune_at
+    set -l pos (__saptune_pos)
+    set -l cmd (string split ' ' -- $argv[1])
+    if test -z "$argv[1]"
+        set cmd
+    end
+    set -l depth (count $cmd)
+    test (count $pos) -ge $depth; or return 1
+    if test $depth -gt 0
+        test "$pos[1..$depth]" = "$cmd"; or return 1
+    end
+    set -l n (math (count $pos) - $depth)
+    switch "$argv[2]"
+        case ''
+            return 0
+        case '+*'
+            test $n -ge (string sub -s 2 -- $argv[2])
+        case '*'
+            test $n -eq $argv[2]
+    end
+end
+
+complete -c saptune -f
+`, strings.ReplaceAll(separateFlagPattern(), "|", " "))
+	for _, flag := range system.CliFlags {
+		if flag.Global {
+			fmt.Fprintf(script, "complete -c saptune%s\n", fishFlag(flag))
+		}
+	}
+	for _, realm := range system.CmdTree {
+		if !realm.Hidden {
+			fmt.Fprintf(script, "complete -c saptune -n \"__saptune_at '' 0\" -a %s -d %s\n", realm.Name, fishQuote(realm.Help))
+		}
+	}
+	for _, cmd := range visibleCmdPaths() {
+		for _, name := range cmd.names {
+			cond := fishQuote(name)
+			for _, sub := range cmd.node.VisibleCommands() {
+				fmt.Fprintf(script, "complete -c saptune -n \"__saptune_at %s 0\" -a %s -d %s\n", cond, sub.Name, fishQuote(sub.Help))
+			}
+			for _, flagName := range cmd.node.Flags {
+				for _, flag := range system.CliFlags {
+					if flag.Name == flagName {
+						fmt.Fprintf(script, "complete -c saptune -n \"__saptune_at %s\"%s\n", cond, fishFlag(flag))
+					}
+				}
+			}
+			for i, arg := range cmd.node.Args {
+				num := fmt.Sprintf("%d", i)
+				if arg.Multi {
+					num = "+" + num
+				}
+				switch {
+				case arg.Complete == "files":
+					fmt.Fprintf(script, "complete -c saptune -n \"__saptune_at %s %s\" -F\n", cond, num)
+				case arg.Complete != "" || len(arg.Values) > 0:
+					fmt.Fprintf(script, "complete -c saptune -n \"__saptune_at %s %s\" -a %s\n", cond, num, fishWords(arg.Complete, arg.Values))
+				}
+			}
+		}
+	}
+	return script.String()
+}
+
+// ManSynopsis returns the SYNOPSIS section of the man page saptune(8).
+// Commands of a realm with the same arguments and flags are combined
+func ManSynopsis() string {
+	synopsis := &strings.Builder{}
+	fmt.Fprintf(synopsis, ".SH SYNOPSIS\n")
+	for _, realm := range system.CmdTree {
+		if realm.Hidden {
+			continue
+		}
+		deprecated := ""
+		if realm.Deprecated {
+			deprecated = ` \fBATTENTION: deprecated\fP`
+		}
+		if len(realm.VisibleCommands()) == 0 {
+			line := `\fBsaptune ` + realm.Name + `\fP`
+			if usage := realm.ArgsUsage(); usage != "" {
+				line = line + " " + usage
+			}
+			fmt.Fprintf(synopsis, "%s%s\n\n", line, deprecated)
+			continue
+		}
+		for _, line := range realm.UsageLines() {
+			fmt.Fprintf(synopsis, "\\fBsaptune %s\\fP\n%s%s\n\n", realm.Name, line, deprecated)
+		}
+	}
+	return synopsis.String()
+}
@@ -0,0 +1,95 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/system"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	srcDir := path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/ospackage")
+	content, err := ioutil.ReadFile(path.Join(srcDir, "usr/share/bash-completion/completions/saptune.completion"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != BashCompletion() {
+		t.Error("saptune.completion is outdated, please regenerate with 'saptune generate completion bash'")
+	}
+	content, err = ioutil.ReadFile(path.Join(srcDir, "man/saptune.8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), ManSynopsis()) {
+		t.Error("SYNOPSIS of saptune.8 is outdated, please regenerate with 'saptune generate synopsis'")
+	}
+}
+
+func TestHelpCoversCmdTree(t *testing.T) {
+	oldOSExit := system.OSExit
+	defer func() { system.OSExit = oldOSExit }()
+	system.OSExit = tstosExit
+	oldErrorExitOut := system.ErrorExitOut
+	defer func() { system.ErrorExitOut = oldErrorExitOut }()
+	system.ErrorExitOut = tstErrorExitOut
+	tstwriter = &bytes.Buffer{}
+
+	buffer := bytes.Buffer{}
+	PrintHelpAndExit(&buffer, 0)
+	help := buffer.String()
+	for _, realm := range system.CmdTree {
+		if !strings.Contains(help, "saptune "+realm.Name) {
+			t.Errorf("realm '%s' missing in the help text", realm.Name)
+		}
+		for _, cmd := range realm.VisibleCommands() {
+			if !strings.Contains(help, " "+cmd.Name) {
+				t.Errorf("command '%s %s' missing in the help text", realm.Name, cmd.Name)
+			}
+		}
+	}
+}
+
+func TestGenerateAction(t *testing.T) {
+	oldOSExit := system.OSExit
+	defer func() { system.OSExit = oldOSExit }()
+	system.OSExit = tstosExit
+	oldErrorExitOut := system.ErrorExitOut
+	defer func() { system.ErrorExitOut = oldErrorExitOut }()
+	system.ErrorExitOut = tstErrorExitOut
+	tstwriter = &bytes.Buffer{}
+
+	buffer := bytes.Buffer{}
+	GenerateAction(&buffer, "completion", "zsh")
+	txt := buffer.String()
+	for _, exp := range []string{"#compdef saptune\n", "compdef _saptune saptune\n", `"note verify")`} {
+		if !strings.Contains(txt, exp) {
+			t.Errorf("missing '%s' in zsh completion", exp)
+		}
+	}
+	buffer.Reset()
+	GenerateAction(&buffer, "completion", "fish")
+	txt = buffer.String()
+	for _, exp := range []string{"function __saptune_list\n", "complete -c saptune "} {
+		if !strings.Contains(txt, exp) {
+			t.Errorf("missing '%s' in fish completion", exp)
+		}
+	}
+	buffer.Reset()
+	GenerateAction(&buffer, "synopsis", "")
+	if !strings.HasPrefix(buffer.String(), ".SH SYNOPSIS\n") {
+		t.Errorf("wrong synopsis '%s'", buffer.String())
+	}
+
+	for _, args := range [][]string{{"completion", "csh"}, {"unknown", ""}} {
+		tstRetErrorExit = -1
+		buffer.Reset()
+		GenerateAction(&buffer, args[0], args[1])
+		checkOut(t, buffer.String(), PrintHelpAndExitMatchText)
+		if tstRetErrorExit != 1 {
+			t.Errorf("error exit should be '1' and NOT '%v'\n", tstRetErrorExit)
+		}
+	}
+}
@@ -34,6 +34,8 @@ func NoteAction(writer io.Writer, actionName, noteID, newNoteID string, tuneApp
 		NoteActionCreate(writer, noteID, tuneApp)
 	case "show":
 		NoteActionShow(writer, noteID, tuneApp)
+	case "lint":
+		NoteActionLint(writer, noteID, tuneApp)
 	case "delete":
 		NoteActionDelete(os.Stdin, writer, noteID, tuneApp)
 	case "rename":
@@ -93,6 +95,7 @@ func NoteActionList(writer io.Writer, tuneApp *app.App) {
 			ManReverted:  false,
 			NoteOverride: false,
 			CustomNote:   false,
+			Conflicts:    make([]string, 0),
 		}
 		noteObj := tuneApp.AllNotes[noteID]
 		format := "\t%s\t\t%s\n"
@@ -126,6 +129,11 @@ func NoteActionList(writer io.Writer, tuneApp *app.App) {
 		// like the 'only' in SAP Note 1656250
 		bonly := " " + setBoldText + "only" + resetBoldText + " "
 		nname := strings.Replace(noteObj.Name(), " only ", bonly, 1)
+		// flag notes, which conflict with enabled notes
+		if conflicts := tuneApp.NoteConflicts(noteID, tuneApp.NoteApplyOrder); len(conflicts) != 0 {
+			nname = fmt.Sprintf("%s %s(conflicts with enabled note(s) %s)%s", nname, setRedText, strings.Join(conflicts, " "), resetTextColor)
+			jnoteListEntry.Conflicts = conflicts
+		}
 		fmt.Fprintf(writer, format, noteID, nname)
 		jnoteListEntry.NoteID = noteID
 		jnoteListEntry.NoteDesc, jnoteListEntry.NoteVers, jnoteListEntry.NoteRdate, jnoteListEntry.NoteRef = note.GetNoteHeadData(noteObj)
@@ -133,7 +141,7 @@ func NoteActionList(writer io.Writer, tuneApp *app.App) {
 	}
 	tuneApp.PrintNoteApplyOrder(writer)
 	remember := bytes.Buffer{}
-	if system.GetFlagVal("format") == "json" {
+	if system.StructuredOut() {
 		writer = &remember
 	}
 	rememberMessage(writer)
@@ -160,7 +168,8 @@ func NoteActionVerify(writer io.Writer, noteID string, tuneApp *app.App) {
 		}
 		noteComp := make(map[string]map[string]note.FieldComparison)
 		noteComp[noteID] = comparisons
-		PrintNoteFields(writer, "HEAD", noteComp, true, &result)
+		noteComp, conforming = filterVerifyResult(writer, noteComp, conforming, tuneApp)
+		printVerifyResult(writer, "HEAD", noteComp, tuneApp, &result)
 		tuneApp.PrintNoteApplyOrder(writer)
 		result.NotesOrder = tuneApp.NoteApplyOrder
 		result.SysCompliance = &conforming
@@ -214,6 +223,10 @@ func NoteActionCustomise(writer io.Writer, noteID string, tuneApp *app.App) {
 		editSrcFile = ovFileName
 		editDestFile = ovFileName
 	}
+	if system.IsFlagSet("set") || system.IsFlagSet("unset") || system.IsFlagSet("untouched") {
+		noteActionCustomiseFlags(writer, noteID, fileName, editSrcFile, editDestFile, tuneApp)
+		return
+	}
 
 	changed, err := system.EditAndCheckFile(editSrcFile, editDestFile, noteID, "note")
 	if err != nil {
@@ -230,6 +243,66 @@ func NoteActionCustomise(writer io.Writer, noteID string, tuneApp *app.App) {
 	}
 }
 
+// noteActionCustomiseFlags changes the override file of a Note without an
+// editor session regarding the flags '--set', '--untouched' and '--unset'
+// and validates the result before writing the override file
+func noteActionCustomiseFlags(writer io.Writer, noteID, noteFile, srcFile, destFile string, tuneApp *app.App) {
+	noteContent, err := ioutil.ReadFile(noteFile)
+	if err != nil {
+		system.ErrorExit("Failed to read file '%s' - %v", noteFile, err)
+	}
+	content, err := ioutil.ReadFile(srcFile)
+	if err != nil {
+		system.ErrorExit("Failed to read file '%s' - %v", srcFile, err)
+	}
+	newContent, changes, err := note.CustomiseOverride(string(content), string(noteContent), system.GetFlagVals("set"), system.GetFlagVals("untouched"), system.GetFlagVals("unset"))
+	if err != nil {
+		system.ErrorExit("Problems while customising Note '%s' - %v", noteID, err)
+	}
+	// only report problems introduced by the changes
+	oldErrors := make(map[string]bool)
+	for _, finding := range note.LintNote(string(content)) {
+		oldErrors[finding.Message] = true
+	}
+	lintErrors := 0
+	for _, finding := range note.LintNote(newContent) {
+		if finding.Severity == note.LintError && !oldErrors[finding.Message] {
+			system.ErrorLog("%s", finding)
+			lintErrors++
+		}
+	}
+	if lintErrors != 0 {
+		system.ErrorExit("The customised Note definition is not valid, so no update of the override file '%s'", destFile)
+	}
+
+	_, applied := tuneApp.IsNoteApplied(noteID)
+	result := system.JCustomise{Name: noteID, File: destFile, Changes: []system.JCustomiseChange{}, Applied: applied, Reapply: applied && len(changes) != 0}
+	for _, change := range changes {
+		result.Changes = append(result.Changes, system.JCustomiseChange{Parameter: change.Parameter, Action: change.Action, OldValue: change.OldValue, NewValue: change.NewValue})
+		switch change.Action {
+		case "set":
+			fmt.Fprintf(writer, "Parameter '%s' set to '%s' (was '%s')\n", change.Parameter, change.NewValue, change.OldValue)
+		case "untouched":
+			fmt.Fprintf(writer, "Parameter '%s' set to 'untouched' (was '%s')\n", change.Parameter, change.OldValue)
+		case "unset":
+			fmt.Fprintf(writer, "Parameter '%s' no longer overridden (was '%s')\n", change.Parameter, change.OldValue)
+		}
+	}
+	system.Jcollect(result)
+	if len(changes) == 0 {
+		system.NoticeLog("Nothing changed, so no update of the override file '%s'", destFile)
+		return
+	}
+	if err := ioutil.WriteFile(destFile, []byte(newContent), 0644); err != nil {
+		system.ErrorExit("Problems while writing override file '%s' - %v", destFile, err)
+	}
+	if !applied {
+		system.NoticeLog("Do not forget to apply the just edited Note to get your changes to take effect\n")
+	} else {
+		system.NoticeLog("Your just edited Note is already applied. To get your changes to take effect, please 'revert' the Note and apply again.\n")
+	}
+}
+
 // NoteActionEdit allows to editing the custom/vendor specific Note definition
 // file and NOT the override file
 func NoteActionEdit(writer io.Writer, noteID string, tuneApp *app.App) {
@@ -281,6 +354,11 @@ func NoteActionCreate(writer io.Writer, noteID string, tuneApp *app.App) {
 		system.ErrorExit("Note '%s' already exists in %s. Please use 'saptune note edit %s' instead to modify this custom specific Note or 'saptune note customise %s' to create an override file or choose another NoteID.", noteID, ExtraTuningSheets, noteID)
 	}
 
+	if system.IsFlagSet("from-system") || system.IsFlagSet("from-note") {
+		noteActionScaffold(writer, noteID, extraFileName, tuneApp)
+		return
+	}
+
 	changed, err := system.EditAndCheckFile(templateFile, extraFileName, noteID, "note")
 	if err != nil {
 		system.ErrorExit("Problems while editing note definition file '%s' - %v", extraFileName, err)
@@ -292,6 +370,51 @@ func NoteActionCreate(writer io.Writer, noteID string, tuneApp *app.App) {
 	}
 }
 
+// noteActionScaffold creates a new Note definition file without an editor
+// session, pre-filled with the current system values selected by the
+// '--from-system' flag and/or sections of the Note selected by the
+// '--from-note' flag (NoteID[:section,section...])
+func noteActionScaffold(writer io.Writer, noteID, extraFileName string, tuneApp *app.App) {
+	sections := []note.ScaffoldSection{}
+	sources := []string{}
+	if spec := system.GetFlagVal("from-system"); spec != "" {
+		sysSections, err := note.ScaffoldFromSystem(spec)
+		if err != nil {
+			system.ErrorExit("Problems while reading the system values for '%s' - %v", spec, err)
+		}
+		sections = append(sections, sysSections...)
+		host, _ := os.Hostname()
+		sources = append(sources, fmt.Sprintf("the system values of host '%s'", host))
+	}
+	if from := system.GetFlagVal("from-note"); from != "" {
+		fromFields := strings.SplitN(from, ":", 2)
+		fromID := fromFields[0]
+		fromSections := []string{}
+		if len(fromFields) == 2 {
+			fromSections = strings.Split(fromFields[1], ",")
+		}
+		if _, err := tuneApp.GetNoteByID(fromID); err != nil {
+			system.ErrorExit("%v", err)
+		}
+		fileName, _ := getFileName(fromID, NoteTuningSheets, ExtraTuningSheets)
+		noteSections, err := note.ScaffoldFromNote(fileName, fromSections)
+		if err != nil {
+			system.ErrorExit("Problems while reading the sections of Note '%s' - %v", fromID, err)
+		}
+		sections = append(sections, noteSections...)
+		sources = append(sources, fmt.Sprintf("Note %s", fromID))
+	}
+	content := note.ScaffoldNote(noteID, "created from "+strings.Join(sources, " and "), sections)
+	if err := ioutil.WriteFile(extraFileName, []byte(content), 0644); err != nil {
+		system.ErrorExit("Problems while writing note definition file '%s' - %v", extraFileName, err)
+	}
+	findings := note.LintNote(content)
+	for _, finding := range findings {
+		fmt.Fprintf(writer, "%s: %s\n", extraFileName, finding)
+	}
+	system.NoticeLog("Note '%s' created successfully from %s. Please check and adapt the content of your Note definition file by using 'saptune note edit %s'.", noteID, strings.Join(sources, " and "), noteID)
+}
+
 // NoteActionShow shows the content of the Note definition file
 func NoteActionShow(writer io.Writer, noteID string, tuneApp *app.App) {
 	if noteID == "" {
@@ -308,6 +431,42 @@ func NoteActionShow(writer io.Writer, noteID string, tuneApp *app.App) {
 	fmt.Fprintf(writer, "\nContent of Note %s:\n%s\n", noteID, string(cont))
 }
 
+// NoteActionLint statically checks a Note definition file, given by file
+// name or Note ID, and exits with an error, if problems were found
+func NoteActionLint(writer io.Writer, noteArg string, tuneApp *app.App) {
+	if noteArg == "" {
+		PrintHelpAndExit(writer, 1)
+	}
+	fileName := noteArg
+	if !strings.Contains(noteArg, "/") {
+		// Note ID, use a path like './file' for files in the
+		// current directory
+		if _, err := tuneApp.GetNoteByID(noteArg); err != nil {
+			system.ErrorExit("%v", err)
+		}
+		fileName, _ = getFileName(noteArg, NoteTuningSheets, ExtraTuningSheets)
+	}
+	findings, err := note.LintNoteFile(fileName)
+	if err != nil {
+		system.ErrorExit("Failed to read file '%s' - %v", fileName, err)
+	}
+	result := system.JNoteLint{File: fileName, Findings: []system.JLintFinding{}}
+	for _, finding := range findings {
+		if finding.Severity == note.LintError {
+			result.Errors++
+		} else {
+			result.Warnings++
+		}
+		result.Findings = append(result.Findings, system.JLintFinding{Line: finding.Line, Section: finding.Section, Severity: finding.Severity, Message: finding.Message})
+		fmt.Fprintf(writer, "%s: %s\n", fileName, finding)
+	}
+	system.Jcollect(result)
+	if result.Errors != 0 {
+		system.ErrorExit("%d error(s) and %d warning(s) found in '%s'", result.Errors, result.Warnings, fileName)
+	}
+	fmt.Fprintf(writer, "%d warning(s) found in '%s', no errors.\n", result.Warnings, fileName)
+}
+
 // NoteActionDelete deletes a custom Note definition file and
 // the corresponding override file
 func NoteActionDelete(reader io.Reader, writer io.Writer, noteID string, tuneApp *app.App) {
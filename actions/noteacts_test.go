@@ -6,6 +6,7 @@ import (
 	"github.com/SUSE/saptune/app"
 	"github.com/SUSE/saptune/sap/note"
 	"github.com/SUSE/saptune/system"
+	"io/ioutil"
 	"os"
 	"path"
 	"strings"
@@ -381,6 +382,30 @@ func TestNoteActionCreate(t *testing.T) {
 	if _, err := os.Stat(fname); err == nil {
 		t.Errorf("found a created file '%s' even that no input was provided to the editor", fname)
 	}
+
+	// test scaffolding from an existing Note without editor
+	os.Args = []string{"saptune", "note", "create", nID, "--from-note", "simpleNote:sysctl"}
+	system.RereadArgs()
+	defer func() {
+		os.Args = []string{"saptune"}
+		system.RereadArgs()
+	}()
+	createBuf.Reset()
+	buffer.Reset()
+	tstRetErrorExit = -1
+	NoteActionCreate(&createBuf, nID, nApp)
+	defer os.Remove(fname)
+	if tstRetErrorExit != -1 {
+		t.Errorf("error exit should be '-1' and NOT '%v'\n", tstRetErrorExit)
+	}
+	checkOut(t, createBuf.String(), "")
+	cont, err := ioutil.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(cont), "DESCRIPTION=created from Note simpleNote\nREFERENCES=\n\n[sysctl]\nnet.ipv4.ip_local_port_range = 31768 61999\n") {
+		t.Errorf("unexpected content '%s'", string(cont))
+	}
 }
 
 func TestNoteActionRenameShowDelete(t *testing.T) {
@@ -520,6 +545,68 @@ net.ipv4.ip_local_port_range = 31768 61999
 	}
 }
 
+func TestNoteActionCustomiseFlags(t *testing.T) {
+	tstRetErrorExit = -1
+	oldOSExit := system.OSExit
+	defer func() { system.OSExit = oldOSExit }()
+	system.OSExit = tstosExit
+	oldErrorExitOut := system.ErrorExitOut
+	defer func() { system.ErrorExitOut = oldErrorExitOut }()
+	system.ErrorExitOut = tstErrorExitOut
+	errExBuffer := bytes.Buffer{}
+	tstwriter = &errExBuffer
+	oldNoteTuningSheets := NoteTuningSheets
+	defer func() { NoteTuningSheets = oldNoteTuningSheets }()
+	NoteTuningSheets = ""
+	oldExtraTuningSheets := ExtraTuningSheets
+	defer func() { ExtraTuningSheets = oldExtraTuningSheets }()
+	ExtraTuningSheets = ExtraFilesInGOPATH
+	oldOverrideTuningSheets := OverrideTuningSheets
+	defer func() { OverrideTuningSheets = oldOverrideTuningSheets }()
+	OverrideTuningSheets = "/tmp/"
+	ovFileName := path.Join(OverrideTuningSheets, "simpleNote")
+	os.Remove(ovFileName)
+	defer os.Remove(ovFileName)
+	defer func() {
+		os.Args = []string{"saptune"}
+		system.RereadArgs()
+	}()
+
+	// create the override file
+	os.Args = []string{"saptune", "note", "customise", "simpleNote", "--set", "net.ipv4.ip_local_port_range=32768 60999"}
+	system.RereadArgs()
+	buffer := bytes.Buffer{}
+	NoteActionCustomise(&buffer, "simpleNote", tApp)
+	checkOut(t, buffer.String(), "Parameter 'net.ipv4.ip_local_port_range' set to '32768 60999' (was '31768 61999')\n")
+	if tstRetErrorExit != -1 {
+		t.Errorf("error exit should be '-1' and NOT '%v'\n", tstRetErrorExit)
+	}
+	cont, err := ioutil.ReadFile(ovFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(cont), "[sysctl]\nnet.ipv4.ip_local_port_range = 32768 60999\n") {
+		t.Errorf("unexpected content '%s'", string(cont))
+	}
+
+	// same value again, nothing to change
+	buffer.Reset()
+	NoteActionCustomise(&buffer, "simpleNote", tApp)
+	checkOut(t, buffer.String(), "")
+
+	// unknown parameter
+	// as we are in 'test mode' collect all errExit messages and continue,
+	// instead of exit function - so differ from real life
+	os.Args = []string{"saptune", "note", "customise", "simpleNote", "--untouched", "vm.swappiness"}
+	system.RereadArgs()
+	buffer.Reset()
+	NoteActionCustomise(&buffer, "simpleNote", tApp)
+	if tstRetErrorExit != 1 {
+		t.Errorf("error exit should be '1' and NOT '%v'\n", tstRetErrorExit)
+	}
+	checkOut(t, errExBuffer.String(), "ERROR: Problems while customising Note 'simpleNote' - parameter 'vm.swappiness' is not part of the Note definition, only available parameters can be customised\nERROR: The customised Note definition is not valid, so no update of the override file '/tmp/simpleNote'\n")
+}
+
 func TestNoteActionCustomise(t *testing.T) {
 	tstRetErrorExit = -1
 	oldOSExit := system.OSExit
@@ -806,3 +893,48 @@ ERROR: Problems while editing Note definition file '/home/ci_tst/gopath/src/gith
 	checkOut(t, txt, errMatchText)
 
 }
+
+func TestNoteActionLint(t *testing.T) {
+	tstRetErrorExit = -1
+	oldOSExit := system.OSExit
+	defer func() { system.OSExit = oldOSExit }()
+	system.OSExit = tstosExit
+	oldErrorExitOut := system.ErrorExitOut
+	defer func() { system.ErrorExitOut = oldErrorExitOut }()
+	system.ErrorExitOut = tstErrorExitOut
+	errExBuffer := bytes.Buffer{}
+	tstwriter = &errExBuffer
+	oldNoteTuningSheets := NoteTuningSheets
+	defer func() { NoteTuningSheets = oldNoteTuningSheets }()
+	NoteTuningSheets = ""
+	oldExtraTuningSheets := ExtraTuningSheets
+	defer func() { ExtraTuningSheets = oldExtraTuningSheets }()
+	ExtraTuningSheets = ExtraFilesInGOPATH
+
+	// Note ID
+	buffer := bytes.Buffer{}
+	NoteActionLint(&buffer, "simpleNote", tApp)
+	fileName := path.Join(ExtraFilesInGOPATH, "simpleNote.conf")
+	lintMatchText := fmt.Sprintf("%s: file [version]: warning: old style version header used, please switch to the VERSION, DATE, DESCRIPTION and REFERENCES fields\n1 warning(s) found in '%s', no errors.\n", fileName, fileName)
+	checkOut(t, buffer.String(), lintMatchText)
+	if tstRetErrorExit != -1 {
+		t.Errorf("error exit should be '-1' and NOT '%v'\n", tstRetErrorExit)
+	}
+
+	// Note file with errors
+	lintFile := path.Join(os.TempDir(), "lintNote.conf")
+	content := "[version]\nVERSION=1\nDATE=01.01.2026\nDESCRIPTION=lint test\nREFERENCES=none\n\n[vm]\nTHP=sometimes\n"
+	if err := ioutil.WriteFile(lintFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(lintFile)
+	buffer.Reset()
+	NoteActionLint(&buffer, lintFile, tApp)
+	if !strings.Contains(buffer.String(), fmt.Sprintf("%s: line 8 [vm]: error: wrong value 'sometimes' for 'THP', supported are 'always', 'madvise', 'never'\n", lintFile)) {
+		t.Errorf("wrong output: '%s'", buffer.String())
+	}
+	if tstRetErrorExit != 1 {
+		t.Errorf("error exit should be '1' and NOT '%v'\n", tstRetErrorExit)
+	}
+	checkOut(t, errExBuffer.String(), fmt.Sprintf("ERROR: 1 error(s) and 0 warning(s) found in '%s'\n", lintFile))
+}
@@ -0,0 +1,207 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParameterAction handles parameter actions like list, show and revert
+func ParameterAction(writer io.Writer, actionName, param string, tuneApp *app.App) {
+	switch actionName {
+	case "list":
+		ParameterActionList(writer)
+	case "show":
+		ParameterActionShow(writer, param, tuneApp)
+	case "revert":
+		ParameterActionRevert(writer, param, system.GetFlagVal("note"), tuneApp)
+	default:
+		PrintHelpAndExit(writer, 1)
+	}
+}
+
+// ParameterActionList lists all parameters changed by the applied Notes
+// together with their start values and the Notes, which set the parameter
+func ParameterActionList(writer io.Writer) {
+	jparamList := []system.JParamListEntry{}
+	allParams := note.GetAllSavedParameters()
+	params := make([]string, 0, len(allParams))
+	for param := range allParams {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	fmtlen := len("Parameter")
+	for _, param := range params {
+		if len(param) > fmtlen {
+			fmtlen = len(param)
+		}
+	}
+	format := "   %-" + strconv.Itoa(fmtlen) + "s | %-11s | %s\n"
+	if len(params) == 0 {
+		fmt.Fprintf(writer, "\nNo parameter changed by saptune.\n\n")
+	} else {
+		fmt.Fprintf(writer, "\nAll parameters changed by the applied Notes (the last Note owns the value):\n\n")
+		fmt.Fprintf(writer, format, "Parameter", "Start value", "Notes (apply order)")
+		fmt.Fprintf(writer, "%s\n", tableSeparator([]int{fmtlen, 11}, 22))
+	}
+	for _, param := range params {
+		start, owner, notes := paramStack(allParams[param])
+		noteTxt := []string{}
+		for _, entry := range notes {
+			noteTxt = append(noteTxt, fmt.Sprintf("%s(%s)", entry.NoteID, entry.Value))
+		}
+		fmt.Fprintf(writer, format, param, start, strings.Join(noteTxt, " "))
+		jparamList = append(jparamList, system.JParamListEntry{Parameter: param, StartValue: start, Owner: owner, Notes: notes})
+	}
+	if len(params) != 0 {
+		fmt.Fprintf(writer, "\n")
+	}
+	system.Jcollect(system.JParamList{ParamsList: jparamList})
+}
+
+// ParameterActionShow shows the current value, the start value, the Notes,
+// which set the parameter and the override files defining the parameter
+func ParameterActionShow(writer io.Writer, param string, tuneApp *app.App) {
+	if param == "" {
+		PrintHelpAndExit(writer, 1)
+	}
+	start, owner, notes := paramStack(note.GetSavedParameterNotes(param))
+	if len(notes) == 0 {
+		system.NoticeLog("Parameter '%s' is not changed by any applied Note.", param)
+	}
+	noteIDs := []string{}
+	for _, entry := range notes {
+		noteIDs = append(noteIDs, entry.NoteID)
+	}
+	overrides := paramOverrides(param, noteIDs)
+	jparam := system.JParamShow{
+		Parameter:  param,
+		ActValue:   nil,
+		StartValue: start,
+		Owner:      owner,
+		Notes:      notes,
+		Overrides:  overrides,
+	}
+	actValue := currentParamValue(param, noteIDs, tuneApp)
+	if actValue != "" {
+		jparam.ActValue = &actValue
+	}
+
+	fmt.Fprintf(writer, "\nParameter:     %s\n", param)
+	fmt.Fprintf(writer, "current value: %s\n", actValue)
+	fmt.Fprintf(writer, "start value:   %s\n", start)
+	fmt.Fprintf(writer, "owner:         %s\n", owner)
+	fmt.Fprintf(writer, "Notes:         ")
+	for cnt, entry := range notes {
+		if cnt > 0 {
+			fmt.Fprintf(writer, "               ")
+		}
+		fmt.Fprintf(writer, "%s (%s)\n", entry.NoteID, entry.Value)
+	}
+	if len(notes) == 0 {
+		fmt.Fprintf(writer, "\n")
+	}
+	fmt.Fprintf(writer, "overrides:     ")
+	for cnt, over := range overrides {
+		if cnt > 0 {
+			fmt.Fprintf(writer, "               ")
+		}
+		fmt.Fprintf(writer, "%s (%s)\n", over.File, over.Value)
+	}
+	fmt.Fprintf(writer, "\n\n")
+	system.Jcollect(jparam)
+}
+
+// ParameterActionRevert reverts a single parameter set by the given Note
+// to the value of the Note applied before or to the start value
+func ParameterActionRevert(writer io.Writer, param, noteID string, tuneApp *app.App) {
+	if param == "" || noteID == "" {
+		PrintHelpAndExit(writer, 1)
+	}
+	if err := tuneApp.RevertParameter(param, noteID); err != nil {
+		system.ErrorExit("Failed to revert parameter '%s' of note '%s': %v", param, noteID, err)
+	}
+	system.InfoLog("Parameter '%s' tuned by the note '%s' has been successfully reverted.", param, noteID)
+	fmt.Fprintf(writer, "Parameter '%s' tuned by the note '%s' has been successfully reverted.\n", param, noteID)
+}
+
+// paramStack splits the content of a parameter state file into the start
+// value, the owner of the current value and the Notes, which set the
+// parameter (in apply order)
+func paramStack(pEntries note.ParameterNotes) (string, string, []system.JParamNote) {
+	start := ""
+	owner := ""
+	notes := []system.JParamNote{}
+	for _, entry := range pEntries.AllNotes {
+		if entry.NoteID == "start" {
+			start = entry.Value
+			continue
+		}
+		notes = append(notes, system.JParamNote{NoteID: entry.NoteID, Value: entry.Value})
+		owner = entry.NoteID
+	}
+	return start, owner, notes
+}
+
+// paramOverrides returns the override files of the given Notes, which
+// define the parameter
+func paramOverrides(param string, noteIDs []string) []system.JParamOverride {
+	overrides := []system.JParamOverride{}
+	for _, noteID := range noteIDs {
+		ovFile, exists := getovFile(noteID, OverrideTuningSheets)
+		if !exists {
+			continue
+		}
+		ovIni, err := txtparser.ParseINIFile(ovFile, false)
+		if err != nil {
+			continue
+		}
+		for _, entry := range ovIni.AllValues {
+			if entry.Key != param {
+				continue
+			}
+			value := entry.Value
+			if value == "" {
+				value = "untouched"
+			}
+			overrides = append(overrides, system.JParamOverride{NoteID: noteID, File: ovFile, Value: value})
+		}
+	}
+	return overrides
+}
+
+// currentParamValue returns the current system value of the parameter
+// by examining the system with the last of the given Notes, which is
+// still available. Sysctl parameters not set by any Note are read directly
+func currentParamValue(param string, noteIDs []string, tuneApp *app.App) string {
+	for i := len(noteIDs) - 1; i >= 0; i-- {
+		iniNote, ok := tuneApp.AllNotes[noteIDs[i]].(note.INISettings)
+		if !ok {
+			continue
+		}
+		// prevent storing of parameter state files
+		current, err := iniNote.SetValuesToApply([]string{"verify"}).Initialise()
+		if err != nil {
+			continue
+		}
+		if val, ok := current.(note.INISettings).SysctlParams[param]; ok {
+			return val
+		}
+	}
+	if strings.Contains(param, ".") {
+		oldStdout, oldSdterr := system.SwitchOffOut()
+		val, err := system.GetSysctlString(param)
+		system.SwitchOnOut(oldStdout, oldSdterr)
+		if err == nil {
+			return val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParamStack(t *testing.T) {
+	pEntries := note.ParameterNotes{
+		AllNotes: []note.ParameterNoteEntry{
+			{NoteID: "start", Value: "60"},
+			{NoteID: "1680803", Value: "10"},
+			{NoteID: "941735", Value: "20"},
+		},
+	}
+	start, owner, notes := paramStack(pEntries)
+	expNotes := []system.JParamNote{{NoteID: "1680803", Value: "10"}, {NoteID: "941735", Value: "20"}}
+	if start != "60" || owner != "941735" || !reflect.DeepEqual(notes, expNotes) {
+		t.Errorf("got: '%s', '%s', '%+v', expected: '60', '941735', '%+v'\n", start, owner, notes, expNotes)
+	}
+	start, owner, notes = paramStack(note.ParameterNotes{})
+	if start != "" || owner != "" || len(notes) != 0 {
+		t.Errorf("got: '%s', '%s', '%+v', expected empty values\n", start, owner, notes)
+	}
+}
+
+func TestParameterActionListShow(t *testing.T) {
+	param := "saptune.test.param"
+	pEntries := note.ParameterNotes{
+		AllNotes: []note.ParameterNoteEntry{
+			{NoteID: "start", Value: "1"},
+			{NoteID: "4711", Value: "2"},
+			{NoteID: "4712", Value: "3"},
+		},
+	}
+	if err := note.StoreParameter(param, pEntries, true); err != nil {
+		t.Fatal(err)
+	}
+	defer note.CleanUpParamFile(param)
+
+	buffer := bytes.Buffer{}
+	ParameterActionList(&buffer)
+	if !strings.Contains(buffer.String(), "4711(2) 4712(3)") {
+		t.Errorf("missing parameter line in output '%s'\n", buffer.String())
+	}
+
+	showMatchText := `
+Parameter:     saptune.test.param
+current value: 
+start value:   1
+owner:         4712
+Notes:         4711 (2)
+               4712 (3)
+overrides:     
+
+`
+	buffer.Reset()
+	ParameterActionShow(&buffer, param, tApp)
+	txt := buffer.String()
+	checkOut(t, txt, showMatchText)
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
 	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"io"
 	"os"
 	"strings"
@@ -20,12 +22,17 @@ func ServiceAction(writer io.Writer, actionName, saptuneVersion string, tApp *ap
 	case "apply":
 		// This action name is only used by saptune service, hence it is not advertised to end user.
 		ServiceActionApply(tApp)
+		// the service keeps running, if the API is enabled
+		ServiceActionAPI(saptuneVersion)
 	case "disable":
 		ServiceActionDisable()
 	case "disablestop":
 		ServiceActionStop(true)
 	case "enable":
 		ServiceActionEnable()
+	case "driftcheck":
+		// This action name is only used by saptune drift check service, hence it is not advertised to end user.
+		ServiceActionDriftCheck(tApp)
 	case "enablestart":
 		ServiceActionStart(true, tApp)
 	case "restart":
@@ -64,6 +71,9 @@ func ServiceActionTakeover(tuneApp *app.App) {
 	// disable and stop 'tuned.service'
 	disableAndStopTuned()
 
+	// check for other components competing with saptune
+	handleConflicts(tuneApp, system.IsFlagSet("neutralize-conflicts"))
+
 	// check, if sapconf is enabled or active
 	if system.IsSapconfActive(SapconfService) {
 		// disable and stop 'sapconf.service'
@@ -208,6 +218,9 @@ func ServiceActionStatus(writer io.Writer, tuneApp *app.App, saptuneVersion stri
 	// check for running tuned.service and print status
 	printTunedStatus(writer, &jstatServs)
 
+	// check for other components competing with saptune
+	infoTrigger["conflicts"] = printConflicts(writer, tuneApp, &jstatus)
+
 	// check for system(d) state
 	infoTrigger["chkHint"] = printSystemdStatus(writer, &jstatus)
 
@@ -218,7 +231,7 @@ func ServiceActionStatus(writer io.Writer, tuneApp *app.App, saptuneVersion stri
 	infoTrigger["notCompliant"] = chkTuningResult(writer, tuneApp, &jstatus)
 
 	infoMsg := bytes.Buffer{}
-	if system.GetFlagVal("format") == "json" {
+	if system.StructuredOut() {
 		writer = &infoMsg
 	}
 	printInfoBlock(writer, infoTrigger)
@@ -400,6 +413,46 @@ func disableAndStopTuned() {
 	}
 }
 
+// handleConflicts reports the components competing with saptune and
+// disables and stops the related services, if neutralize is set.
+// Conflicting sysctl configuration files are never touched.
+func handleConflicts(tuneApp *app.App, neutralize bool) {
+	for _, conflict := range system.ConflictInventory(managedParams(tuneApp)) {
+		if !neutralize || conflict.Service == "" || conflict.Service == TunedService {
+			if len(conflict.Params) == 0 {
+				system.WarningLog("'%s' (%s) is tuning the %s, which interferes with the tuning of saptune", conflict.Component, conflict.Source, conflict.Tunes)
+			} else {
+				system.WarningLog("'%s' (%s) is tuning the parameters '%s' managed by saptune too", conflict.Component, conflict.Source, strings.Join(conflict.Params, ", "))
+			}
+			continue
+		}
+		if err := system.SystemctlDisableStop(conflict.Service); err != nil {
+			system.ErrorExit("%v", err)
+		}
+		system.NoticeLog("Service '%s' of the conflicting component '%s' disabled and stopped", conflict.Service, conflict.Component)
+	}
+}
+
+// managedParams returns the parameters of all enabled Notes together with
+// their section names
+func managedParams(tuneApp *app.App) map[string]string {
+	managed := make(map[string]string)
+	for _, noteID := range tuneApp.NoteApplyOrder {
+		iniNote, ok := tuneApp.AllNotes[noteID].(note.INISettings)
+		if !ok {
+			continue
+		}
+		ini, err := txtparser.ParseINIFile(iniNote.ConfFilePath, false)
+		if err != nil {
+			continue
+		}
+		for _, param := range ini.AllValues {
+			managed[param.Key] = param.Section
+		}
+	}
+	return managed
+}
+
 // getLeaveTxt prepares the information to print at the end of start or stop
 func getLeaveTxt(action string, active, enabled bool) string {
 	sleave := ""
@@ -535,32 +588,69 @@ func printTunedStatus(writer io.Writer, jstat *system.JStatusServs) {
 	}
 }
 
+// printConflicts prints the components competing with saptune
+func printConflicts(writer io.Writer, tuneApp *app.App, jstat *system.JStatus) bool {
+	conflicts := system.ConflictInventory(managedParams(tuneApp))
+	jstat.Conflicts = []system.JConflict{}
+	fmt.Fprintf(writer, "conflicts:                ")
+	if len(conflicts) == 0 {
+		fmt.Fprintf(writer, "none\n")
+		return false
+	}
+	for cnt, conflict := range conflicts {
+		if cnt > 0 {
+			fmt.Fprintf(writer, "                          ")
+		}
+		tunes := strings.Join(conflict.Params, ", ")
+		if tunes == "" {
+			tunes = conflict.Tunes
+		}
+		fmt.Fprintf(writer, "%s (%s): %s\n", conflict.Component, conflict.Source, tunes)
+		jstat.Conflicts = append(jstat.Conflicts, system.JConflict{Component: conflict.Component, Source: conflict.Source, Params: conflict.Params, Tunes: conflict.Tunes})
+	}
+	return true
+}
+
 // printNoteAndSols prints all enabled/active notes and solutions
 func printNoteAndSols(writer io.Writer, tuneApp *app.App, jstat *system.JStatus) bool {
 	notTuned := true
-	partial := false
 	fmt.Fprintf(writer, "enabled Solution:         ")
-	solName := ""
-	if len(tuneApp.TuneForSolutions) > 0 {
-		solName = tuneApp.TuneForSolutions[0]
+	for cnt, solName := range tuneApp.TuneForSolutions {
+		if cnt > 0 {
+			fmt.Fprintf(writer, "\n                          ")
+		}
 		fmt.Fprintf(writer, "%s (%s)", solName, strings.Join(tuneApp.AllSolutions[solName], ", "))
 		notTuned = false
 	}
 	fmt.Fprintf(writer, "\n")
+	if len(tuneApp.TuneForSolutions) > 1 {
+		// show the notes contributed by more than one solution
+		fmt.Fprintf(writer, "shared Solution Notes:    ")
+		shared := []string{}
+		for _, noteID := range tuneApp.GetSortedSolutionEnabledNotes() {
+			if sols := tuneApp.SolutionsOfNote(noteID); len(sols) > 1 {
+				shared = append(shared, fmt.Sprintf("%s (%s)", noteID, strings.Join(sols, ", ")))
+			}
+		}
+		fmt.Fprintf(writer, "%s\n", strings.Join(shared, ", "))
+	}
 	fmt.Fprintf(writer, "applied Solution:         ")
-	appliedSol, state := tuneApp.AppliedSolution()
-	appliedSolNotes := []string{}
-	if appliedSol != "" {
+	appliedSols, states := tuneApp.AppliedSolutions()
+	appliedSolNotes := make(map[string][]string)
+	for cnt, appliedSol := range appliedSols {
+		if cnt > 0 {
+			fmt.Fprintf(writer, "\n                          ")
+		}
+		appliedSolNotes[appliedSol] = []string{}
 		for _, note := range tuneApp.AllSolutions[appliedSol] {
 			if _, ok := tuneApp.IsNoteApplied(note); ok {
-				appliedSolNotes = append(appliedSolNotes, note)
+				appliedSolNotes[appliedSol] = append(appliedSolNotes[appliedSol], note)
 			}
 		}
-		if state == "partial" {
-			fmt.Fprintf(writer, "%s (%s -> %s)", appliedSol, strings.Join(appliedSolNotes, ", "), state)
-			partial = true
+		if states[cnt] == "partial" {
+			fmt.Fprintf(writer, "%s (%s -> %s)", appliedSol, strings.Join(appliedSolNotes[appliedSol], ", "), states[cnt])
 		} else {
-			fmt.Fprintf(writer, "%s (%s)", appliedSol, strings.Join(appliedSolNotes, ", "))
+			fmt.Fprintf(writer, "%s (%s)", appliedSol, strings.Join(appliedSolNotes[appliedSol], ", "))
 		}
 	}
 	fmt.Fprintf(writer, "\n")
@@ -589,20 +679,22 @@ func printNoteAndSols(writer io.Writer, tuneApp *app.App, jstat *system.JStatus)
 	if appliedNotes != "" {
 		jstat.AppliedNotes = strings.Split(appliedNotes, " ")
 	}
-	if appliedSol != "" {
+	for cnt, appliedSol := range appliedSols {
 		appSol := system.JAppliedSol{
 			SolName: appliedSol,
-			Partial: partial,
+			Partial: states[cnt] == "partial",
 		}
 		jstat.AppliedSol = append(jstat.AppliedSol, appSol)
 		appSolNotes := system.JSol{
 			SolName:   appliedSol,
-			NotesList: appliedSolNotes,
+			NotesList: appliedSolNotes[appliedSol],
 		}
 		jstat.AppliedSolNotes = append(jstat.AppliedSolNotes, appSolNotes)
 	}
 	jstat.ConfiguredSol = tuneApp.TuneForSolutions
-	if solName != "" {
+	// one entry per enabled solution shows, which solution contributed
+	// each note
+	for _, solName := range tuneApp.TuneForSolutions {
 		confSolNotes := system.JSol{
 			SolName:   solName,
 			NotesList: tuneApp.AllSolutions[solName],
@@ -750,6 +842,9 @@ func printInfoBlock(writer io.Writer, infoTrigger map[string]bool) {
 	if infoTrigger["stenabled"] && infoTrigger["scenabled"] {
 		fmt.Fprintf(writer, "WARNING! saptune.service and sapconf.service are BOTH enabled!\nOnly one tool may tune the system.\n")
 	}
+	if infoTrigger["conflicts"] {
+		fmt.Fprintf(writer, "Other components are tuning parameters managed by saptune, so we may encounter conflicting tuning values.\nUse 'saptune service takeover --neutralize-conflicts' to disable and stop the competing services.\n")
+	}
 	if infoTrigger["notCompliant"] {
 		fmt.Fprintf(writer, "Regarding the tuning state of the system please use 'saptune note verify' for detailed information.\n")
 	}
@@ -57,10 +57,13 @@ func SolutionActionApply(writer io.Writer, solName string, tuneApp *app.App) {
 	if solName == "" {
 		PrintHelpAndExit(writer, 1)
 	}
-	if len(tuneApp.TuneForSolutions) > 0 {
-		// already one solution applied.
-		// do not apply another solution. Does not make sense
-		system.ErrorExit("There is already one solution applied. Applying another solution is NOT supported.", 1)
+	if len(tuneApp.TuneForSolutions) > 0 && !tuneApp.IsSolutionEnabled(solName) {
+		// already other solutions enabled. Check the union of the
+		// notes for parameters set to different values
+		sols := append([]string{}, tuneApp.TuneForSolutions...)
+		for _, conflict := range tuneApp.SolutionParamConflicts(append(sols, solName)) {
+			system.WarningLog("parameter '%s' is set to '%s' by note '%s' (solution '%s') and to '%s' by note '%s' (solution '%s'). The value of the note applied last wins.", conflict.Param, conflict.Value1, conflict.Note1, conflict.Sol1, conflict.Value2, conflict.Note2, conflict.Sol2)
+		}
 	}
 	removedAdditionalNotes, err := tuneApp.TuneSolution(solName)
 	if err != nil {
@@ -143,7 +146,7 @@ func SolutionActionList(writer io.Writer, tuneApp *app.App) {
 		jsolutionList = append(jsolutionList, jsolutionListEntry)
 	}
 	remember := bytes.Buffer{}
-	if system.GetFlagVal("format") == "json" {
+	if system.StructuredOut() {
 		writer = &remember
 	}
 	rememberMessage(writer)
@@ -166,8 +169,9 @@ func SolutionActionVerify(writer io.Writer, solName string, tuneApp *app.App) {
 		if err != nil {
 			system.ErrorExit("Failed to test the current system against the specified SAP solution: %v", err)
 		}
-		PrintNoteFields(writer, "NONE", comparisons, true, &result)
-		if len(unsatisfiedNotes) == 0 {
+		comparisons, conforming := filterVerifyResult(writer, comparisons, len(unsatisfiedNotes) == 0, tuneApp)
+		printVerifyResult(writer, "NONE", comparisons, tuneApp, &result)
+		if conforming {
 			fmt.Fprintf(writer, "%s%sThe system fully conforms to the tuning guidelines of the specified SAP solution.%s%s\n", setGreenText, setBoldText, resetBoldText, resetTextColor)
 		} else {
 			system.ErrorExit("The parameters listed above have deviated from the specified SAP solution recommendations.\n", "colorPrint", setRedText, setBoldText, resetBoldText, resetTextColor)
@@ -215,7 +219,7 @@ func SolutionActionRevert(writer io.Writer, solName string, tuneApp *app.App) {
 // SolutionActionEnabled prints out the enabled solution definition
 func SolutionActionEnabled(writer io.Writer, tuneApp *app.App) {
 	if len(tuneApp.TuneForSolutions) != 0 {
-		fmt.Fprintf(writer, "%s", tuneApp.TuneForSolutions[0])
+		fmt.Fprintf(writer, "%s", strings.Join(tuneApp.TuneForSolutions, " "))
 	}
 	system.Jcollect(tuneApp.TuneForSolutions)
 	//system.Jcollect(strings.Join(tuneApp.TuneForSolutions, " "))
@@ -223,19 +227,26 @@ func SolutionActionEnabled(writer io.Writer, tuneApp *app.App) {
 
 // SolutionActionApplied prints out the applied solution
 func SolutionActionApplied(writer io.Writer, tuneApp *app.App) {
-	partial := false
-	solApplied, state := tuneApp.AppliedSolution()
-	if state == "partial" {
-		fmt.Fprintf(writer, "%s (partial)", solApplied)
-		partial = true
-	} else {
-		fmt.Fprintf(writer, "%s", solApplied)
+	solsApplied, states := tuneApp.AppliedSolutions()
+	appSols := []system.JAppliedSol{}
+	for cnt, solApplied := range solsApplied {
+		partial := false
+		if cnt > 0 {
+			fmt.Fprintf(writer, " ")
+		}
+		if states[cnt] == "partial" {
+			fmt.Fprintf(writer, "%s (partial)", solApplied)
+			partial = true
+		} else {
+			fmt.Fprintf(writer, "%s", solApplied)
+		}
+		appSols = append(appSols, system.JAppliedSol{SolName: solApplied, Partial: partial})
 	}
-	appSol := system.JAppliedSol{
-		SolName: solApplied,
-		Partial: partial,
+	if len(appSols) == 0 {
+		// keep the former output for 'no solution applied'
+		appSols = append(appSols, system.JAppliedSol{SolName: "", Partial: false})
 	}
-	system.Jcollect(appSol)
+	system.Jcollect(appSols)
 }
 
 // SolutionActionCustomise creates an override file and allows to editing the
@@ -266,6 +277,10 @@ func SolutionActionCustomise(writer io.Writer, customSol string, tuneApp *app.Ap
 		editSrcFile = ovFileName
 		editDestFile = ovFileName
 	}
+	if system.IsFlagSet("add-note") || system.IsFlagSet("remove-note") {
+		solutionActionCustomiseFlags(writer, customSol, editSrcFile, editDestFile, tuneApp)
+		return
+	}
 
 	changed, err := system.EditAndCheckFile(editSrcFile, editDestFile, customSol, "solution")
 	if err != nil {
@@ -285,6 +300,52 @@ func SolutionActionCustomise(writer io.Writer, customSol string, tuneApp *app.Ap
 	}
 }
 
+// solutionActionCustomiseFlags changes the Note list of the current
+// architecture in the override file of a solution without an editor session
+// regarding the flags '--add-note' and '--remove-note'
+func solutionActionCustomiseFlags(writer io.Writer, customSol, srcFile, destFile string, tuneApp *app.App) {
+	for _, noteID := range system.GetFlagVals("add-note") {
+		if _, err := tuneApp.GetNoteByID(noteID); err != nil {
+			system.ErrorExit("%v", err)
+		}
+	}
+	content, err := ioutil.ReadFile(srcFile)
+	if err != nil {
+		system.ErrorExit("Failed to read file '%s' - %v", srcFile, err)
+	}
+	newContent, added, removed, err := solution.CustomiseSolution(string(content), solution.ArchSection(solutionSelector), system.GetFlagVals("add-note"), system.GetFlagVals("remove-note"))
+	if err != nil {
+		system.ErrorExit("Problems while customising solution '%s' - %v", customSol, err)
+	}
+
+	_, applied := tuneApp.IsSolutionApplied(customSol)
+	changed := len(added) != 0 || len(removed) != 0
+	result := system.JCustomise{Name: customSol, File: destFile, Changes: []system.JCustomiseChange{}, Applied: applied, Reapply: applied && changed}
+	for _, noteID := range removed {
+		result.Changes = append(result.Changes, system.JCustomiseChange{Parameter: noteID, Action: "remove"})
+		fmt.Fprintf(writer, "Note '%s' removed from solution '%s'\n", noteID, customSol)
+	}
+	for _, noteID := range added {
+		result.Changes = append(result.Changes, system.JCustomiseChange{Parameter: noteID, Action: "add"})
+		fmt.Fprintf(writer, "Note '%s' added to solution '%s'\n", noteID, customSol)
+	}
+	system.Jcollect(result)
+	if !changed {
+		system.NoticeLog("Nothing changed, so no update of the override file '%s'", destFile)
+		return
+	}
+	if err := ioutil.WriteFile(destFile, []byte(newContent), 0644); err != nil {
+		system.ErrorExit("Problems while writing override file '%s' - %v", destFile, err)
+	}
+	if applied {
+		system.NoticeLog("Your just edited Solution is already applied. To get your changes to take effect, please 'revert' the Solution and apply again.\n")
+	} else if tuneApp.IsSolutionEnabled(customSol) {
+		system.NoticeLog("Your just edited Solution is enabled, but not applied yet. To get your changes to take effect, please apply the just edited Solution or start saptune.service\n")
+	} else {
+		system.NoticeLog("Do not forget to apply the just edited Solution to get your changes to take effect\n")
+	}
+}
+
 // SolutionActionEdit allows to editing the custom/vendor specific
 // solution definition file and NOT the override file
 func SolutionActionEdit(writer io.Writer, customSol string, tuneApp *app.App) {
@@ -378,6 +439,14 @@ func SolutionActionShow(writer io.Writer, solName string) {
 		system.ErrorExit("Failed to read file '%s' - %v", fileName, err)
 	}
 	fmt.Fprintf(writer, "\nContent of Solution %s:\n%s\n", solName, string(cont))
+	// solutions including other solutions
+	if origins := solution.GetNoteOrigins(solName, solutionSelector); len(origins) != 0 {
+		fmt.Fprintf(writer, "Expanded list of Notes of Solution %s (Note - origin):\n", solName)
+		for _, origin := range origins {
+			fmt.Fprintf(writer, "\t%s\t%s\n", origin.NoteID, origin.Origin)
+		}
+		fmt.Fprintf(writer, "\n")
+	}
 }
 
 // SolutionActionDelete deletes a custom solution definition file and
@@ -87,6 +87,7 @@ func stagingActionStatus(writer io.Writer) {
 	} else {
 		fmt.Fprintf(writer, "Staging is disabled\n")
 	}
+	system.Jcollect(system.JStaging{Enabled: stagingSwitch})
 }
 
 // stagingActionEnable enables staging by setting STAGING in /etc/sysconfig/saptune.
@@ -114,17 +115,21 @@ func stagingActionDisable() {
 // If a Note or the solution definition is part of the working area, but not
 // in the package area, it will be listed as deleted.
 func stagingActionList(writer io.Writer) {
+	result := system.JStaging{Enabled: stagingSwitch, Staged: []system.JStagingEntry{}}
 	fmt.Fprintf(writer, "\n")
 	for _, stageName := range stgFiles.AllStageFiles {
 		desc := stgFiles.StageAttributes[stageName]["desc"]
 		flag := ""
+		state := ""
 		flags := []string{"deleted", "updated", "new"}
 		for _, f := range flags {
 			if stgFiles.StageAttributes[stageName][f] == "true" {
 				flag = fmt.Sprintf("(%s)", f)
+				state = f
 				break
 			}
 		}
+		result.Staged = append(result.Staged, system.JStagingEntry{Name: stageName, Description: desc, State: state})
 		format := "\t%s\t\t%s\n\t\t\t%s\n"
 		if len(stageName) >= 8 {
 			format = "\t%s\t%s\n\t\t\t%s\n"
@@ -132,6 +137,7 @@ func stagingActionList(writer io.Writer) {
 		fmt.Fprintf(writer, format, stageName, desc, flag)
 	}
 	fmt.Fprintf(writer, "\nRemember: To release from staging use the command 'saptune staging release ...'.\n          You can check the differences with 'saptune staging diff ...'.\n")
+	system.Jcollect(result)
 }
 
 // stagingActionDiff shows the differences between the Note, the solution definition
@@ -437,7 +443,14 @@ func printNoteAnalysis(writer io.Writer, stageName, txtPrefix, flag string) (boo
 				}
 			}
 			sol = strings.TrimSpace(sol)
-			if sol == stgFiles.StageAttributes[stageName]["enabledSol"] {
+			solEnabled := false
+			for _, esol := range strings.Split(stgFiles.StageAttributes[stageName]["enabledSol"], ",") {
+				if esol == sol {
+					solEnabled = true
+					break
+				}
+			}
+			if solEnabled {
 				fmt.Fprintf(writer, txtSEnabled, sol)
 			} else {
 				fmt.Fprintf(writer, txtSNotEnabled, sol)
@@ -539,7 +552,7 @@ func collectStageFileInfo(tuneApp *app.App) stageFiles {
 		stageMap["sfilename"] = stagingFile
 		// enabled solution
 		if len(tuneApp.TuneForSolutions) > 0 {
-			stageMap["enabledSol"] = tuneApp.TuneForSolutions[0]
+			stageMap["enabledSol"] = strings.Join(tuneApp.TuneForSolutions, ",")
 		}
 
 		// get flags
@@ -632,10 +645,8 @@ func getStageEnabledState(tApp *app.App, sol, note string) string {
 	if sol != "" {
 		// solution
 		enabled = "false"
-		if len(tApp.TuneForSolutions) != 0 {
-			if tApp.TuneForSolutions[0] == sol {
-				enabled = "true"
-			}
+		if tApp.IsSolutionEnabled(sol) {
+			enabled = "true"
 		}
 	} else {
 		// note
@@ -689,10 +700,7 @@ func diffStageObj(writer io.Writer, sName string) {
 	stgNote := map[string]string{}
 	wrkNote := map[string]string{}
 	solName := ""
-	solSelect := "ArchX86"
-	if solutionSelector == "ppc64le" {
-		solSelect = "ArchPPC64LE"
-	}
+	solSelect := solution.ArchSection(solutionSelector)
 	if strings.HasSuffix(sName, ".sol") {
 		solName = strings.TrimSuffix(sName, ".sol")
 	}
@@ -6,9 +6,11 @@ import (
 	"github.com/SUSE/saptune/system"
 	"github.com/SUSE/saptune/txtparser"
 	"io"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // PrintNoteFields Print mismatching fields in the note comparison result.
@@ -19,8 +21,10 @@ func PrintNoteFields(writer io.Writer, header string, noteComparisons map[string
 	compliant := "yes"
 	printHead := ""
 	noteField := ""
-	footnote := make([]string, 16)
+	footnote := make([]string, 19)
 	reminder := make(map[string]string)
+	overMetas := make(map[string]map[string]txtparser.OverrideMeta)
+	now := time.Now()
 	override := ""
 	comment := ""
 	hasDiff := false
@@ -33,8 +37,17 @@ func PrintNoteFields(writer io.Writer, header string, noteComparisons map[string
 	// sort output
 	sortkeys := sortNoteComparisonsOutput(noteComparisons)
 
+	// collect the owner of the parameter values
+	owners, fmtlen5 := getParamOwners(sortkeys, noteComparisons)
+
+	// width of the severity column, only used by verify
+	fmtlen6 := 0
+	if printComparison {
+		fmtlen6 = getSeverityWidth(noteComparisons)
+	}
+
 	// setup table format values
-	fmtlen0, fmtlen1, fmtlen2, fmtlen3, fmtlen4, format := setupTableFormat(sortkeys, noteComparisons, printComparison)
+	fmtlen0, fmtlen1, fmtlen2, fmtlen3, fmtlen4, format := setupTableFormat(sortkeys, noteComparisons, printComparison, fmtlen5, fmtlen6)
 
 	// print
 	noteID := ""
@@ -58,10 +71,26 @@ func PrintNoteFields(writer io.Writer, header string, noteComparisons map[string
 		// prepare footnote
 		compliant, comment, footnote = prepareFootnote(comparison, compliant, comment, inform, footnote)
 
+		// template expression of the expected value
+		tmpl := strings.Replace(noteComparisons[noteID][fmt.Sprintf("%s[%s]", "Templates", key)].ExpectedValueJS, "\t", " ", -1)
+		compliant, comment, footnote = setTemplate(key, tmpl, compliant, comment, footnote)
+
+		// check metadata of the override file (reason, expiry)
+		var overMeta *system.JOverMeta
+		if printComparison {
+			if _, ok := overMetas[noteID]; !ok {
+				overMetas[noteID] = txtparser.GetOverrideMetaFile(path.Join(OverrideTuningSheets, noteID))
+			}
+			compliant, comment, footnote, overMeta = setOverMeta(key, override, compliant, comment, overMetas[noteID], footnote, now)
+			if strings.Contains(compliant, "no ") {
+				hasDiff = true
+			}
+		}
+
 		// print table header
 		if printHead != "" {
 			printHeadline(writer, header, noteID, noteComparisons)
-			printTableHeader(writer, format, fmtlen0, fmtlen1, fmtlen2, fmtlen3, fmtlen4, printComparison)
+			printTableHeader(writer, format, fmtlen0, fmtlen1, fmtlen2, fmtlen3, fmtlen4, fmtlen5, fmtlen6, printComparison)
 		}
 
 		// print table body
@@ -73,6 +102,8 @@ func PrintNoteFields(writer io.Writer, header string, noteComparisons map[string
 		}
 		noteLine.ActValue = &pAct
 		pExp = strings.Replace(comparison.ExpectedValueJS, "\t", " ", -1)
+		owner := owners[skey]
+		severity := note.ParamSeverity(noteComparisons[noteID], key)
 		if printComparison {
 			// verify
 			if system.IsFlagSet("show-non-compliant") && (strings.Contains(compliant, "yes") || strings.Contains(compliant, "-")) {
@@ -80,12 +111,21 @@ func PrintNoteFields(writer io.Writer, header string, noteComparisons map[string
 				continue
 			}
 			colFormat, colCompliant = colorPrint(format, compliant, colorScheme)
-			fmt.Fprintf(writer, colFormat, noteField, comparison.ReflectMapKey, pExp, override, pAct, colCompliant)
+			fmt.Fprintf(writer, colFormat, severityColumn(fmtlen6, severity, tableColumns(fmtlen5, owner.String(), noteField, comparison.ReflectMapKey, pExp, override, pAct, colCompliant))...)
 		} else {
 			// simulate
-			fmt.Fprintf(writer, format, comparison.ReflectMapKey, pAct, pExp, override, comment)
+			fmt.Fprintf(writer, format, tableColumns(fmtlen5, owner.String(), comparison.ReflectMapKey, pAct, pExp, override, comment)...)
 		}
 		noteLine = collectMRO(noteLine, compliant, noteID, noteComparisons, comparison, pExp, override, printComparison, comment, footnote, pAct)
+		noteLine.Owner = owner.owner
+		noteLine.DefinedBy = owner.others
+		noteLine.OverActive = owner.override
+		noteLine.OverMeta = overMeta
+		noteLine.Template = tmpl
+		noteLine.Severity = ""
+		if printComparison {
+			noteLine.Severity = severity
+		}
 		noteList = append(noteList, noteLine)
 	}
 
@@ -160,8 +200,9 @@ func sortNoteComparisonsOutput(noteCompare map[string]map[string]note.FieldCompa
 	// sort output
 	for noteID, comparisons := range noteCompare {
 		for _, comparison := range comparisons {
-			if comparison.ReflectFieldName == "Inform" {
-				// skip inform map to avoid double entries in verify table
+			if comparison.ReflectFieldName == "Inform" || comparison.ReflectFieldName == "Templates" || comparison.ReflectFieldName == "Severities" {
+				// skip inform, template and severity map to avoid
+				// double entries in verify table
 				continue
 			}
 			if len(comparison.ReflectMapKey) != 0 && comparison.ReflectFieldName != "OverrideParams" {
@@ -179,9 +220,20 @@ func sortNoteComparisonsOutput(noteCompare map[string]map[string]note.FieldCompa
 }
 
 // setupTableFormat sets the format of the table columns dependent on the content
-func setupTableFormat(skeys []string, noteCompare map[string]map[string]note.FieldComparison, printComp bool) (int, int, int, int, int, string) {
+// an owner column is added behind the Override column, if fmtlen5 is not 0
+// a severity column is added in front of the Compliant column, if fmtlen6 is
+// not 0
+func setupTableFormat(skeys []string, noteCompare map[string]map[string]note.FieldComparison, printComp bool, fmtlen5, fmtlen6 int) (int, int, int, int, int, string) {
 	var fmtlen0, fmtlen1, fmtlen2, fmtlen3, fmtlen4 int
 	format := "\t%s : %s\n"
+	ownerFmt := ""
+	if fmtlen5 != 0 {
+		ownerFmt = "%-" + strconv.Itoa(fmtlen5) + "s | "
+	}
+	sevFmt := ""
+	if fmtlen6 != 0 {
+		sevFmt = "%-" + strconv.Itoa(fmtlen6) + "s | "
+	}
 	// define start values for the column width
 	if printComp {
 		// verify
@@ -204,7 +256,7 @@ func setupTableFormat(skeys []string, noteCompare map[string]map[string]note.Fie
 		noteField := fmt.Sprintf("%s, %s", noteID, txtparser.GetINIFileVersionSectionEntry(noteCompare[noteID]["ConfFilePath"].ActualValue.(string), "version"))
 		comparisons := noteCompare[noteID]
 		for _, comparison := range comparisons {
-			if comparison.ReflectMapKey == "reminder" || comparison.ReflectFieldName == "Inform" {
+			if comparison.ReflectMapKey == "reminder" || comparison.ReflectFieldName == "Inform" || comparison.ReflectFieldName == "Templates" || comparison.ReflectFieldName == "Severities" {
 				continue
 			}
 			if printComp {
@@ -214,12 +266,12 @@ func setupTableFormat(skeys []string, noteCompare map[string]map[string]note.Fie
 				}
 				// 3:override, 1:mapkey, 2:expval, 4:actval
 				fmtlen3, fmtlen1, fmtlen2, fmtlen4 = setWidthOfColums(comparison, fmtlen3, fmtlen1, fmtlen2, fmtlen4)
-				format = "   %-" + strconv.Itoa(fmtlen0) + "s | %-" + strconv.Itoa(fmtlen1) + "s | %-" + strconv.Itoa(fmtlen2) + "s | %-" + strconv.Itoa(fmtlen3) + "s | %-" + strconv.Itoa(fmtlen4) + "s | %2s\n"
+				format = "   %-" + strconv.Itoa(fmtlen0) + "s | %-" + strconv.Itoa(fmtlen1) + "s | %-" + strconv.Itoa(fmtlen2) + "s | %-" + strconv.Itoa(fmtlen3) + "s | " + ownerFmt + "%-" + strconv.Itoa(fmtlen4) + "s | " + sevFmt + "%2s\n"
 			} else {
 				// simulate
 				// 4:override, 1:mapkey, 3:expval, 2:actval
 				fmtlen4, fmtlen1, fmtlen3, fmtlen2 = setWidthOfColums(comparison, fmtlen4, fmtlen1, fmtlen3, fmtlen2)
-				format = "   %-" + strconv.Itoa(fmtlen1) + "s | %-" + strconv.Itoa(fmtlen2) + "s | %-" + strconv.Itoa(fmtlen3) + "s | %-" + strconv.Itoa(fmtlen4) + "s | %2s\n"
+				format = "   %-" + strconv.Itoa(fmtlen1) + "s | %-" + strconv.Itoa(fmtlen2) + "s | %-" + strconv.Itoa(fmtlen3) + "s | %-" + strconv.Itoa(fmtlen4) + "s | " + ownerFmt + "%2s\n"
 			}
 		}
 	}
@@ -237,30 +289,93 @@ func printHeadline(writer io.Writer, header, id string, noteComparisons map[stri
 }
 
 // printTableHeader prints the header of the table
-func printTableHeader(writer io.Writer, format string, col0, col1, col2, col3, col4 int, printComp bool) {
+// an owner column is added behind the Override column, if col5 is not 0
+// a severity column is added in front of the Compliant column, if col6 is
+// not 0
+func printTableHeader(writer io.Writer, format string, col0, col1, col2, col3, col4, col5, col6 int, printComp bool) {
+	widths := []int{}
 	if printComp {
 		// verify
-		fmt.Fprintf(writer, format, "SAPNote, Version", "Parameter", "Expected", "Override", "Actual", "Compliant")
-		for i := 0; i < col0+col1+col2+col3+col4+28; i++ {
-			if i == 3+col0+1 || i == 3+col0+3+col1+1 || i == 3+col0+3+col1+4+col2 || i == 3+col0+3+col1+4+col2+2+col3+1 || i == 3+col0+3+col1+4+col2+2+col3+3+col4+1 {
-				fmt.Fprintf(writer, "+")
-			} else {
-				fmt.Fprintf(writer, "-")
-			}
+		fmt.Fprintf(writer, format, severityColumn(col6, "Severity", tableColumns(col5, "Owner", "SAPNote, Version", "Parameter", "Expected", "Override", "Actual", "Compliant"))...)
+		widths = append(widths, col0, col1, col2, col3)
+		if col5 != 0 {
+			widths = append(widths, col5)
 		}
-		fmt.Fprintf(writer, "\n")
+		widths = append(widths, col4)
+		if col6 != 0 {
+			widths = append(widths, col6)
+		}
+		fmt.Fprintf(writer, "%s\n", tableSeparator(widths, 11))
 	} else {
 		// simulate
-		fmt.Fprintf(writer, format, "Parameter", "Value set", "Value expected", "Override", "Comment")
-		for i := 0; i < col1+col2+col3+col4+28; i++ {
-			if i == 3+col1+1 || i == 3+col1+3+col2+1 || i == 3+col1+3+col2+3+col3+1 || i == 3+col1+3+col2+3+col3+3+col4+1 {
-				fmt.Fprintf(writer, "+")
-			} else {
-				fmt.Fprintf(writer, "-")
+		fmt.Fprintf(writer, format, tableColumns(col5, "Owner", "Parameter", "Value set", "Value expected", "Override", "Comment")...)
+		widths = append(widths, col1, col2, col3, col4)
+		if col5 != 0 {
+			widths = append(widths, col5)
+		}
+		fmt.Fprintf(writer, "%s\n", tableSeparator(widths, 14))
+	}
+}
+
+// tableSeparator returns the separator line between table header and
+// table body for the given column widths. The last column has a fixed width
+func tableSeparator(widths []int, last int) string {
+	segments := []string{}
+	for cnt, width := range widths {
+		if cnt == 0 {
+			// first column is indented by 3 characters
+			segments = append(segments, strings.Repeat("-", width+4))
+		} else {
+			segments = append(segments, strings.Repeat("-", width+2))
+		}
+	}
+	segments = append(segments, strings.Repeat("-", last))
+	return strings.Join(segments, "+")
+}
+
+// tableColumns returns the values of a table row as argument list for the
+// table format. The owner is added behind the fourth column (Override),
+// if the owner column is available (width not 0)
+func tableColumns(width int, owner string, cols ...interface{}) []interface{} {
+	if width == 0 || len(cols) < 4 {
+		return cols
+	}
+	row := append([]interface{}{}, cols[:4]...)
+	row = append(row, owner)
+	return append(row, cols[4:]...)
+}
+
+// severityColumn adds the severity in front of the last table column
+// (Compliant), if the severity column is available (width not 0)
+func severityColumn(width int, severity string, cols []interface{}) []interface{} {
+	if width == 0 || len(cols) == 0 {
+		return cols
+	}
+	row := append([]interface{}{}, cols[:len(cols)-1]...)
+	row = append(row, severity)
+	return append(row, cols[len(cols)-1])
+}
+
+// getSeverityWidth returns the width of the severity column of the verify
+// table. The column is only shown (width not 0), if at least one of the
+// Notes classifies its parameters in the section [severity]
+func getSeverityWidth(noteComparisons map[string]map[string]note.FieldComparison) int {
+	width := 0
+	for _, comparisons := range noteComparisons {
+		for _, comparison := range comparisons {
+			if comparison.ReflectFieldName != "Severities" {
+				continue
+			}
+			if width == 0 {
+				// parameters without severity are 'recommended'
+				width = len(note.SeverityRecommended)
+			}
+			if sev, ok := comparison.ExpectedValue.(string); ok && len(sev) > width {
+				width = len(sev)
 			}
 		}
-		fmt.Fprintf(writer, "\n")
 	}
+	return width
 }
 
 // printTableFooter prints the footer of the table
@@ -350,6 +465,19 @@ func setWidthOfColums(compare note.FieldComparison, c1, c2, c3, c4 int) (int, in
 	return c1, c2, c3, c4
 }
 
+// colorSchemes are the supported color schemes of the 'verify' table print
+var colorSchemes = []string{"full-green-zebra", "cmpl-green-zebra", "full-blue-zebra", "cmpl-blue-zebra", "full-red-noncmpl", "red-noncmpl", "full-yellow-noncmpl", "yellow-noncmpl"}
+
+// isColorScheme checks, if the color scheme is supported
+func isColorScheme(scheme string) bool {
+	for _, cs := range colorSchemes {
+		if cs == scheme {
+			return true
+		}
+	}
+	return false
+}
+
 // getColorScheme reads the color scheme from CLI flag or from saptune
 // sysconfig file or sets default
 func getColorScheme() string {
@@ -450,3 +578,83 @@ func colorFormating(colCmpl, colNonCmpl, txt, compliant string) string {
 	}
 	return colFormat
 }
+
+// paramOwner describes the provenance of the effective value of a parameter
+type paramOwner struct {
+	owner    string              // Note, which has set the effective value
+	others   []system.JParamNote // other Notes defining the parameter
+	override bool                // value from the override file is in force
+}
+
+// String returns the content of the owner column
+func (po paramOwner) String() string {
+	txt := po.owner
+	if txt == "" {
+		txt = "-"
+	}
+	for _, other := range po.others {
+		txt = txt + fmt.Sprintf(" +%s(%s)", other.NoteID, other.Value)
+	}
+	if po.override {
+		txt = txt + " (override)"
+	}
+	return txt
+}
+
+// getParamOwners collects for all parameters of the table the Note, which
+// owns the effective value (from the parameter state files), the other
+// Notes, which define the parameter too and if an override is in force.
+// Returns the width of the owner column or 0, if no owner information is
+// available at all, so the column is not needed
+func getParamOwners(skeys []string, noteCompare map[string]map[string]note.FieldComparison) (map[string]paramOwner, int) {
+	owners := make(map[string]paramOwner)
+	width := 0
+	for _, skey := range skeys {
+		keyFields := strings.Split(skey, "§")
+		noteID := keyFields[0]
+		key := keyFields[1]
+		if key == "reminder" {
+			continue
+		}
+		po := paramOwner{others: []system.JParamNote{}}
+		known := map[string]bool{noteID: true}
+		// the parameter state file contains the applied Notes in
+		// apply order, the last one owns the effective value
+		stack := note.GetSavedParameterNotes(key).AllNotes
+		if len(stack) > 1 {
+			po.owner = stack[len(stack)-1].NoteID
+			known[po.owner] = true
+		}
+		for _, entry := range stack {
+			if entry.NoteID == "start" || known[entry.NoteID] {
+				continue
+			}
+			po.others = append(po.others, system.JParamNote{NoteID: entry.NoteID, Value: entry.Value})
+			known[entry.NoteID] = true
+		}
+		// Notes of the same verify or simulate run, which are not
+		// applied (yet)
+		otherIDs := []string{}
+		for otherID := range noteCompare {
+			if _, ok := noteCompare[otherID][fmt.Sprintf("%s[%s]", "SysctlParams", key)]; ok && !known[otherID] {
+				otherIDs = append(otherIDs, otherID)
+			}
+		}
+		sort.Strings(otherIDs)
+		for _, otherID := range otherIDs {
+			po.others = append(po.others, system.JParamNote{NoteID: otherID, Value: strings.Replace(noteCompare[otherID][fmt.Sprintf("%s[%s]", "SysctlParams", key)].ExpectedValueJS, "\t", " ", -1)})
+		}
+		over := noteCompare[noteID][fmt.Sprintf("%s[%s]", "OverrideParams", key)].ExpectedValueJS
+		po.override = over != "" && (po.owner == "" || po.owner == noteID)
+		owners[skey] = po
+		if po.owner != "" || len(po.others) != 0 || po.override {
+			if width < len(po.String()) {
+				width = len(po.String())
+			}
+		}
+	}
+	if width != 0 && width < len("Owner") {
+		width = len("Owner")
+	}
+	return owners, width
+}
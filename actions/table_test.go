@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"github.com/SUSE/saptune/sap/note"
 	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"os"
 	"path"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSetWidthOfColums(t *testing.T) {
@@ -523,3 +526,153 @@ func TestColorPrint(t *testing.T) {
 		t.Errorf("got: %+v, expected: %+v\n", cCompl, compliant)
 	}
 }
+
+func TestGetParamOwners(t *testing.T) {
+	param := "saptune.test.owner"
+	pEntries := note.ParameterNotes{AllNotes: []note.ParameterNoteEntry{{NoteID: "start", Value: "1"}, {NoteID: "941735", Value: "10"}, {NoteID: "1680803", Value: "20"}}}
+	if err := note.StoreParameter(param, pEntries, true); err != nil {
+		t.Fatal(err)
+	}
+	defer note.CleanUpParamFile(param)
+
+	fcomp1 := note.FieldComparison{ReflectFieldName: "SysctlParams", ReflectMapKey: param, ActualValue: "20", ExpectedValue: "10", ActualValueJS: "20", ExpectedValueJS: "10", MatchExpectation: false}
+	fcomp2 := note.FieldComparison{ReflectFieldName: "SysctlParams", ReflectMapKey: param, ActualValue: "20", ExpectedValue: "30", ActualValueJS: "20", ExpectedValueJS: "30", MatchExpectation: false}
+	fcomp3 := note.FieldComparison{ReflectFieldName: "SysctlParams", ReflectMapKey: "kernel.shmmni", ActualValue: "4096", ExpectedValue: "4096", ActualValueJS: "4096", ExpectedValueJS: "4096", MatchExpectation: true}
+	fcomp4 := note.FieldComparison{ReflectFieldName: "OverrideParams", ReflectMapKey: "kernel.shmmni", ActualValue: "4096", ExpectedValue: "4096", ActualValueJS: "4096", ExpectedValueJS: "4096", MatchExpectation: true}
+	noteComp := map[string]map[string]note.FieldComparison{
+		"941735":  {fmt.Sprintf("SysctlParams[%s]", param): fcomp1, "SysctlParams[kernel.shmmni]": fcomp3, "OverrideParams[kernel.shmmni]": fcomp4},
+		"2205917": {fmt.Sprintf("SysctlParams[%s]", param): fcomp2},
+	}
+	skeys := []string{"941735§" + param, "941735§kernel.shmmni", "2205917§" + param}
+
+	owners, width := getParamOwners(skeys, noteComp)
+	exp := "1680803 +941735(10)"
+	if owners["2205917§"+param].String() != exp {
+		t.Errorf("got: '%s', expected: '%s'\n", owners["2205917§"+param].String(), exp)
+	}
+	exp = "1680803 +2205917(30)"
+	if owners["941735§"+param].String() != exp {
+		t.Errorf("got: '%s', expected: '%s'\n", owners["941735§"+param].String(), exp)
+	}
+	exp = "- (override)"
+	if owners["941735§kernel.shmmni"].String() != exp {
+		t.Errorf("got: '%s', expected: '%s'\n", owners["941735§kernel.shmmni"].String(), exp)
+	}
+	if width != len("1680803 +2205917(30)") {
+		t.Errorf("wrong column width '%d'\n", width)
+	}
+
+	// no owner information available, no owner column
+	delete(noteComp["941735"], "OverrideParams[kernel.shmmni]")
+	if _, width = getParamOwners([]string{"941735§kernel.shmmni"}, noteComp); width != 0 {
+		t.Errorf("wrong column width '%d', expected '0'\n", width)
+	}
+}
+
+func TestTableColumns(t *testing.T) {
+	cols := tableColumns(0, "owner", "a", "b", "c", "d", "e")
+	if fmt.Sprint(cols...) != fmt.Sprint("a", "b", "c", "d", "e") {
+		t.Errorf("got: '%v'\n", cols)
+	}
+	cols = tableColumns(5, "owner", "a", "b", "c", "d", "e")
+	if fmt.Sprint(cols...) != fmt.Sprint("a", "b", "c", "d", "owner", "e") {
+		t.Errorf("got: '%v'\n", cols)
+	}
+	sep := tableSeparator([]int{2, 3}, 4)
+	if sep != "------+-----+----" {
+		t.Errorf("got: '%s'\n", sep)
+	}
+}
+
+func TestSetOverMeta(t *testing.T) {
+	metas := map[string]txtparser.OverrideMeta{
+		"vm.swappiness": {Reason: "sizing", Expires: "2026-01-31", ApprovedBy: "jdoe"},
+		"kernel.shmmni": {Reason: "sizing"},
+	}
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.Local)
+	footnote := make([]string, 18)
+
+	compliant, comment, footnote, meta := setOverMeta("vm.swappiness", "20", "yes", "", metas, footnote, now)
+	if compliant != "no  [17] [18]" || comment != " [17] [18]" {
+		t.Errorf("got '%s', '%s'", compliant, comment)
+	}
+	if meta == nil || !meta.Expired || meta.ApprovedBy != "jdoe" {
+		t.Errorf("got '%+v'", meta)
+	}
+	if footnote[16] != footnote17 || footnote[17] != "[18] override of vm.swappiness: reason 'sizing', approved by jdoe, expires 2026-01-31" {
+		t.Errorf("got '%+v'", footnote)
+	}
+
+	compliant, _, footnote, meta = setOverMeta("kernel.shmmni", "32768", "yes", "", metas, footnote, now)
+	if compliant != "yes [18]" || meta == nil || meta.Expired {
+		t.Errorf("got '%s', '%+v'", compliant, meta)
+	}
+	if footnote[17] != "[18] override of vm.swappiness: reason 'sizing', approved by jdoe, expires 2026-01-31\n [18] override of kernel.shmmni: reason 'sizing'" {
+		t.Errorf("got '%s'", footnote[17])
+	}
+
+	// parameter not overridden
+	compliant, _, _, meta = setOverMeta("vm.swappiness", "", "yes", "", metas, footnote, now)
+	if compliant != "yes" || meta != nil {
+		t.Errorf("got '%s', '%+v'", compliant, meta)
+	}
+}
+
+func TestSeverityColumn(t *testing.T) {
+	defer func() {
+		os.Args = []string{"saptune"}
+		system.RereadArgs()
+	}()
+	os.Args = []string{"saptune", "note", "verify", "--colorscheme=black"}
+	system.RereadArgs()
+
+	cols := severityColumn(0, "critical", []interface{}{"a", "b", "yes"})
+	if fmt.Sprint(cols...) != fmt.Sprint("a", "b", "yes") {
+		t.Errorf("got: '%v'\n", cols)
+	}
+	cols = severityColumn(8, "critical", []interface{}{"a", "b", "yes"})
+	if fmt.Sprint(cols...) != fmt.Sprint("a", "b", "critical", "yes") {
+		t.Errorf("got: '%v'\n", cols)
+	}
+
+	cfgFile := fmt.Sprintf("%ssimpleNote.conf", ExtraFilesInGOPATH)
+	noteComp := map[string]map[string]note.FieldComparison{
+		"941735": {
+			"ConfFilePath":                        {ReflectFieldName: "ConfFilePath", ActualValue: cfgFile, ExpectedValue: cfgFile, ActualValueJS: cfgFile, ExpectedValueJS: cfgFile, MatchExpectation: true},
+			"SysctlParams[kernel.numa_balancing]": {ReflectFieldName: "SysctlParams", ReflectMapKey: "kernel.numa_balancing", ActualValue: "1", ExpectedValue: "0", ActualValueJS: "1", ExpectedValueJS: "0", MatchExpectation: false},
+			"SysctlParams[kernel.shmmni]":         {ReflectFieldName: "SysctlParams", ReflectMapKey: "kernel.shmmni", ActualValue: "32768", ExpectedValue: "32768", ActualValueJS: "32768", ExpectedValueJS: "32768", MatchExpectation: true},
+			"Severities[kernel.numa_balancing]":   {ReflectFieldName: "Severities", ReflectMapKey: "kernel.numa_balancing", ActualValue: "critical", ExpectedValue: "critical", ActualValueJS: "critical", ExpectedValueJS: "critical", MatchExpectation: true},
+		},
+	}
+	if width := getSeverityWidth(noteComp); width != len(note.SeverityRecommended) {
+		t.Errorf("wrong column width '%d'\n", width)
+	}
+	buffer := bytes.Buffer{}
+	result := system.JPNotes{}
+	PrintNoteFields(&buffer, "NONE", noteComp, true, &result)
+	txt := buffer.String()
+	for _, line := range []string{
+		"   SAPNote, Version | Parameter             | Expected  | Override  | Actual  | Severity    | Compliant\n",
+		"--------------------+-----------------------+-----------+-----------+---------+-------------+-----------\n",
+		"   941735, 1        | kernel.numa_balancing | 0         |           | 1       | critical    | no \n",
+		"   941735, 1        | kernel.shmmni         | 32768     |           | 32768   | recommended | yes\n",
+	} {
+		if !strings.Contains(txt, line) {
+			t.Errorf("missing '%s' in '%s'", line, txt)
+		}
+	}
+	if len(result.Verifications) != 2 || result.Verifications[0].Severity != "critical" || result.Verifications[1].Severity != "recommended" {
+		t.Errorf("wrong severity in '%+v'", result.Verifications)
+	}
+
+	// no severity classification, no severity column
+	delete(noteComp["941735"], "Severities[kernel.numa_balancing]")
+	if width := getSeverityWidth(noteComp); width != 0 {
+		t.Errorf("wrong column width '%d', expected '0'\n", width)
+	}
+	buffer.Reset()
+	PrintNoteFields(&buffer, "NONE", noteComp, true, &result)
+	if strings.Contains(buffer.String(), "Severity") {
+		t.Errorf("unexpected severity column in '%s'", buffer.String())
+	}
+}
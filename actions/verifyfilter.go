@@ -0,0 +1,229 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// verifySections are the sections supported by the verify filter '--section'
+var verifySections = []string{note.INISectionSysctl, note.INISectionSys, note.INISectionVM, note.INISectionFS, note.INISectionCPU, note.INISectionMEM, note.INISectionBlock, note.INISectionService, note.INISectionLimits, note.INISectionLogin, note.INISectionPagecache, note.INISectionRpm, note.INISectionGrub}
+
+// complianceValues are the values supported by the verify filter '--compliant'
+var complianceValues = []string{"yes", "no", "na", "info"}
+
+// verifyFilter selects the parameters shown and evaluated by 'verify'
+// failOn is the lowest severity of the non-compliant parameters, which
+// breaks the compliance
+type verifyFilter struct {
+	notes     []string
+	sections  []string
+	params    []string
+	devices   []string
+	compliant string
+	failOn    string
+}
+
+// getVerifyFilter reads the verify filters from the command line
+// and checks their values
+func getVerifyFilter() verifyFilter {
+	filter := verifyFilter{
+		notes:     splitFilterList(system.GetFlagVal("note")),
+		sections:  splitFilterList(system.GetFlagVal("section")),
+		params:    splitFilterList(system.GetFlagVal("param")),
+		devices:   splitFilterList(system.GetFlagVal("device")),
+		compliant: system.GetFlagVal("compliant"),
+		failOn:    system.GetFlagVal("fail-on"),
+	}
+	for _, section := range filter.sections {
+		if !system.IsInList(section, verifySections) {
+			system.ErrorExit("section '%s' is not supported, supported are '%s'", section, strings.Join(verifySections, "', '"))
+		}
+	}
+	if filter.compliant != "" && !system.IsInList(filter.compliant, complianceValues) {
+		system.ErrorExit("wrong value '%s' for '--compliant', supported are '%s'", filter.compliant, strings.Join(complianceValues, "', '"))
+	}
+	if filter.failOn != "" && !note.IsSeverity(filter.failOn) {
+		system.ErrorExit("wrong value '%s' for '--fail-on', supported are '%s'", filter.failOn, strings.Join(note.Severities, "', '"))
+	}
+	return filter
+}
+
+// splitFilterList splits a comma separated list of filter values
+func splitFilterList(list string) []string {
+	values := []string{}
+	for _, val := range strings.Split(list, ",") {
+		if val = strings.TrimSpace(val); val != "" {
+			values = append(values, val)
+		}
+	}
+	return values
+}
+
+// matchesAny checks, if the name matches one of the shell patterns
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isSet checks, if at least one verify filter is used
+func (filter verifyFilter) isSet() bool {
+	return len(filter.notes) > 0 || len(filter.sections) > 0 || len(filter.params) > 0 || len(filter.devices) > 0 || filter.compliant != ""
+}
+
+// complianceFilterValues maps the compliance categories of the parameters
+// to the values of the filter '--compliant'. Untouched parameters count as
+// compliant. Informational deviations do not make a Note non-compliant, but
+// differ from the expected value, so they have their own value
+var complianceFilterValues = map[string]string{
+	note.ComplianceCompliant:     "yes",
+	note.ComplianceUntouched:     "yes",
+	note.ComplianceInformational: "info",
+	note.ComplianceNonCompliant:  "no",
+	note.ComplianceNotApplicable: "na",
+}
+
+// expiredOverrides returns the parameters of a Note, which are overridden
+// by an expired override
+func expiredOverrides(noteID string, comparisons map[string]note.FieldComparison, now time.Time) map[string]bool {
+	expired := make(map[string]bool)
+	for _, key := range note.ExpiredOverrides(txtparser.GetOverrideMetaFile(path.Join(OverrideTuningSheets, noteID)), comparisons, now) {
+		expired[key] = true
+	}
+	return expired
+}
+
+// noteParamSections returns the section of each parameter of a Note
+// definition file
+func noteParamSections(noteID string, tuneApp *app.App) map[string]string {
+	sections := make(map[string]string)
+	iniNote, ok := tuneApp.AllNotes[noteID].(note.INISettings)
+	if !ok {
+		return sections
+	}
+	ini, err := txtparser.ParseINIFile(iniNote.ConfFilePath, false)
+	if err != nil {
+		system.WarningLog("failed to read the sections of Note '%s' - %v", noteID, err)
+		return sections
+	}
+	for _, entry := range ini.AllValues {
+		sections[entry.Key] = entry.Section
+	}
+	return sections
+}
+
+// selectParam checks, if a parameter of a Note is selected by the filter
+func (filter verifyFilter) selectParam(key, section, compliance string) bool {
+	if len(filter.sections) > 0 && !system.IsInList(section, filter.sections) {
+		return false
+	}
+	if len(filter.params) > 0 && !matchesAny(key, filter.params) {
+		return false
+	}
+	if len(filter.devices) > 0 {
+		bdev := note.BlockDeviceOfParam(key)
+		if section != note.INISectionBlock || bdev == "" || !matchesAny(bdev, filter.devices) {
+			return false
+		}
+	}
+	return filter.compliant == "" || filter.compliant == compliance
+}
+
+// apply returns the comparison results of the parameters selected by the
+// filter. Besides the parameter comparison the related override, inform,
+// template and severity entries and the reminder of the Note are kept. Notes without
+// selected parameters are removed
+func (filter verifyFilter) apply(comparisons map[string]map[string]note.FieldComparison, tuneApp *app.App) map[string]map[string]note.FieldComparison {
+	filtered := make(map[string]map[string]note.FieldComparison)
+	now := time.Now()
+	for noteID, noteComparisons := range comparisons {
+		if len(filter.notes) > 0 && !system.IsInList(noteID, filter.notes) {
+			continue
+		}
+		sections := noteParamSections(noteID, tuneApp)
+		expired := expiredOverrides(noteID, noteComparisons, now)
+		selected := make(map[string]note.FieldComparison)
+		for ckey, comparison := range noteComparisons {
+			if comparison.ReflectFieldName != "SysctlParams" || comparison.ReflectMapKey == "reminder" {
+				continue
+			}
+			key := comparison.ReflectMapKey
+			if !filter.selectParam(key, sections[key], complianceFilterValues[note.ParamCompliance(comparison, expired[key])]) {
+				continue
+			}
+			selected[ckey] = comparison
+			for _, field := range []string{"OverrideParams", "Inform", "Templates", "Severities"} {
+				fkey := fmt.Sprintf("%s[%s]", field, key)
+				if fcomp, ok := noteComparisons[fkey]; ok {
+					selected[fkey] = fcomp
+				}
+			}
+		}
+		if len(selected) == 0 {
+			continue
+		}
+		for ckey, comparison := range noteComparisons {
+			if comparison.ReflectMapKey == "reminder" || comparison.ReflectMapKey == "" {
+				// reminder and the fields ConfFilePath, ID and
+				// DescriptiveName
+				selected[ckey] = comparison
+			}
+		}
+		filtered[noteID] = selected
+	}
+	return filtered
+}
+
+// conforming evaluates the compliance of the filtered comparison results.
+// With '--fail-on' only the non-compliant parameters of the given or a
+// higher severity break the compliance
+func (filter verifyFilter) conforming(filtered map[string]map[string]note.FieldComparison) bool {
+	now := time.Now()
+	for noteID, noteComparisons := range filtered {
+		expired := expiredOverrides(noteID, noteComparisons, now)
+		if filter.failOn == "" {
+			if !note.ComparisonsConform(noteComparisons) || len(expired) > 0 {
+				return false
+			}
+			continue
+		}
+		for _, comparison := range noteComparisons {
+			if comparison.ReflectFieldName != "SysctlParams" || comparison.ReflectMapKey == "reminder" {
+				continue
+			}
+			key := comparison.ReflectMapKey
+			if note.ParamCompliance(comparison, expired[key]) == note.ComplianceNonCompliant && note.SeverityReaches(note.ParamSeverity(noteComparisons, key), filter.failOn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// filterVerifyResult applies the verify filters of the command line to the
+// comparison results and evaluates the compliance of the selected
+// parameters honouring '--fail-on'. Without filters and '--fail-on' the
+// results are returned unchanged
+func filterVerifyResult(writer io.Writer, comparisons map[string]map[string]note.FieldComparison, conforming bool, tuneApp *app.App) (map[string]map[string]note.FieldComparison, bool) {
+	filter := getVerifyFilter()
+	if !filter.isSet() && filter.failOn == "" {
+		return comparisons, conforming
+	}
+	if filter.isSet() {
+		comparisons = filter.apply(comparisons, tuneApp)
+		if len(comparisons) == 0 {
+			fmt.Fprintf(writer, "No parameters match the given filters.\n")
+		}
+	}
+	return comparisons, filter.conforming(comparisons)
+}
@@ -0,0 +1,164 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// verifyFilterComparisons returns comparison results of two Notes
+func verifyFilterComparisons() map[string]map[string]note.FieldComparison {
+	param := func(key, act, exp string, match bool) note.FieldComparison {
+		return note.FieldComparison{ReflectFieldName: "SysctlParams", ReflectMapKey: key, ActualValue: act, ExpectedValue: exp, ActualValueJS: act, ExpectedValueJS: exp, MatchExpectation: match}
+	}
+	return map[string]map[string]note.FieldComparison{
+		"filterNote": {
+			"SysctlParams[vm.swappiness]":     param("vm.swappiness", "60", "10", false),
+			"OverrideParams[vm.swappiness]":   {ReflectFieldName: "OverrideParams", ReflectMapKey: "vm.swappiness", ActualValue: "", ExpectedValue: "10"},
+			"SysctlParams[vm.dirty_ratio]":    param("vm.dirty_ratio", "10", "10", true),
+			"SysctlParams[kernel.shmmni]":     param("kernel.shmmni", "32768", "32768", true),
+			"SysctlParams[force_latency]":     param("force_latency", "all:none", "70", false),
+			"SysctlParams[reminder]":          param("reminder", "", "check the filter", true),
+			"SysctlParams[VSZ_TMPFS_PERCENT]": param("VSZ_TMPFS_PERCENT", "50", "75", false),
+			"ID":                              {ReflectFieldName: "ID", ActualValue: "filterNote", ExpectedValue: "filterNote", MatchExpectation: true},
+		},
+		"otherNote": {
+			"SysctlParams[vm.max_map_count]": param("vm.max_map_count", "65530", "2147483647", false),
+		},
+	}
+}
+
+// comparisonKeys returns the sorted keys of the filtered comparison results
+func comparisonKeys(comparisons map[string]map[string]note.FieldComparison) []string {
+	keys := []string{}
+	for noteID, noteComparisons := range comparisons {
+		for key := range noteComparisons {
+			keys = append(keys, noteID+" "+key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestVerifyFilter(t *testing.T) {
+	defer func() {
+		os.Args = []string{"saptune"}
+		system.RereadArgs()
+	}()
+	tstDir := t.TempDir()
+	confFile := path.Join(tstDir, "filterNote.conf")
+	_ = ioutil.WriteFile(confFile, []byte("[sysctl]\nvm.swappiness = 10\nvm.dirty_ratio = 10\nkernel.shmmni = 32768\n\n[cpu]\nforce_latency = 70\n"), 0644)
+	filterApp := &app.App{AllNotes: map[string]note.Note{"filterNote": note.INISettings{ConfFilePath: confFile, ID: "filterNote"}}}
+
+	tests := []struct {
+		args       []string
+		keys       []string
+		conforming bool
+	}{
+		{[]string{"--section", "cpu"}, []string{"filterNote ID", "filterNote SysctlParams[force_latency]", "filterNote SysctlParams[reminder]"}, true},
+		{[]string{"--param", "vm.*", "--compliant=no"}, []string{"filterNote ID", "filterNote OverrideParams[vm.swappiness]", "filterNote SysctlParams[reminder]", "filterNote SysctlParams[vm.swappiness]", "otherNote SysctlParams[vm.max_map_count]"}, false},
+		{[]string{"--note", "filterNote", "--compliant=yes"}, []string{"filterNote ID", "filterNote SysctlParams[kernel.shmmni]", "filterNote SysctlParams[reminder]", "filterNote SysctlParams[vm.dirty_ratio]"}, true},
+		// informational deviations are neither compliant nor do they make
+		// the Note non-compliant
+		{[]string{"--note", "filterNote", "--compliant=info"}, []string{"filterNote ID", "filterNote SysctlParams[VSZ_TMPFS_PERCENT]", "filterNote SysctlParams[reminder]"}, true},
+		{[]string{"--note", "filterNote", "--compliant=no"}, []string{"filterNote ID", "filterNote OverrideParams[vm.swappiness]", "filterNote SysctlParams[reminder]", "filterNote SysctlParams[vm.swappiness]"}, false},
+		{[]string{"--compliant=na"}, []string{"filterNote ID", "filterNote SysctlParams[force_latency]", "filterNote SysctlParams[reminder]"}, true},
+		{[]string{"--device", "sd*"}, []string{}, true},
+		{[]string{}, comparisonKeys(verifyFilterComparisons()), false},
+	}
+	for _, tt := range tests {
+		os.Args = append([]string{"saptune", "note", "verify"}, tt.args...)
+		system.RereadArgs()
+		buffer := bytes.Buffer{}
+		filtered, conforming := filterVerifyResult(&buffer, verifyFilterComparisons(), false, filterApp)
+		if keys := comparisonKeys(filtered); !reflect.DeepEqual(keys, tt.keys) {
+			t.Errorf("'%v': got '%+v', expected '%+v'", tt.args, keys, tt.keys)
+		}
+		if conforming != tt.conforming {
+			t.Errorf("'%v': got compliance '%v', expected '%v'", tt.args, conforming, tt.conforming)
+		}
+		if len(tt.keys) == 0 && buffer.String() != "No parameters match the given filters.\n" {
+			t.Errorf("'%v': wrong output '%s'", tt.args, buffer.String())
+		}
+	}
+}
+
+func TestVerifyFilterSelectParam(t *testing.T) {
+	filter := verifyFilter{sections: []string{"block"}, devices: []string{"sd*", "vdb"}}
+	for key, selected := range map[string]bool{"IO_SCHEDULER_sda": true, "NRREQ_vdb": true, "NRREQ_vdc": false, "READ_AHEAD_KB_nvme0n1": false} {
+		if filter.selectParam(key, "block", "yes") != selected {
+			t.Errorf("'%s': expected '%v'", key, selected)
+		}
+	}
+	if filter.selectParam("vm.swappiness", "sysctl", "yes") {
+		t.Error("parameter of section sysctl selected by a device filter")
+	}
+}
+
+func TestVerifyFilterErrors(t *testing.T) {
+	oldOSExit := system.OSExit
+	defer func() { system.OSExit = oldOSExit }()
+	system.OSExit = tstosExit
+	oldErrorExitOut := system.ErrorExitOut
+	defer func() { system.ErrorExitOut = oldErrorExitOut }()
+	system.ErrorExitOut = tstErrorExitOut
+	defer func() {
+		os.Args = []string{"saptune"}
+		system.RereadArgs()
+	}()
+
+	for _, args := range [][]string{{"--section", "unknown"}, {"--compliant=maybe"}} {
+		tstRetErrorExit = -1
+		errExitbuffer := bytes.Buffer{}
+		tstwriter = &errExitbuffer
+		os.Args = append([]string{"saptune", "note", "verify"}, args...)
+		system.RereadArgs()
+		_ = getVerifyFilter()
+		if tstRetErrorExit != 1 || errExitbuffer.String() == "" {
+			t.Errorf("'%v': expected an error exit, got '%d' - '%s'", args, tstRetErrorExit, errExitbuffer.String())
+		}
+	}
+}
+
+func TestVerifyFailOn(t *testing.T) {
+	defer func() {
+		os.Args = []string{"saptune"}
+		system.RereadArgs()
+	}()
+	filterApp := &app.App{AllNotes: map[string]note.Note{}}
+	sevComparisons := func() map[string]map[string]note.FieldComparison {
+		comparisons := verifyFilterComparisons()
+		comparisons["filterNote"]["Severities[vm.swappiness]"] = note.FieldComparison{ReflectFieldName: "Severities", ReflectMapKey: "vm.swappiness", ActualValue: "informational", ExpectedValue: "informational", MatchExpectation: true}
+		comparisons["otherNote"]["Severities[vm.max_map_count]"] = note.FieldComparison{ReflectFieldName: "Severities", ReflectMapKey: "vm.max_map_count", ActualValue: "critical", ExpectedValue: "critical", MatchExpectation: true}
+		return comparisons
+	}
+	tests := []struct {
+		args       []string
+		conforming bool
+	}{
+		{[]string{"--fail-on=critical"}, false},
+		{[]string{"--fail-on=critical", "--note", "filterNote"}, true},
+		{[]string{"--fail-on=recommended", "--note", "filterNote"}, true},
+		{[]string{"--fail-on=informational", "--note", "filterNote"}, false},
+		{[]string{"--note", "filterNote"}, false},
+	}
+	for _, tt := range tests {
+		os.Args = append([]string{"saptune", "note", "verify"}, tt.args...)
+		system.RereadArgs()
+		buffer := bytes.Buffer{}
+		filtered, conforming := filterVerifyResult(&buffer, sevComparisons(), true, filterApp)
+		if conforming != tt.conforming {
+			t.Errorf("'%v': got compliance '%v', expected '%v'", tt.args, conforming, tt.conforming)
+		}
+		if len(tt.args) == 1 && !reflect.DeepEqual(comparisonKeys(filtered), comparisonKeys(sevComparisons())) {
+			t.Errorf("'%v': comparison results changed by '--fail-on'", tt.args)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// summaryTopOffenders is the number of non-compliant parameters listed in
+// the compliance summary
+const summaryTopOffenders = 5
+
+// summaryOtherSection is used for parameters without known section
+const summaryOtherSection = "other"
+
+// printVerifyResult prints the verify table or, if '--summary' is set, the
+// compliance summary of the comparison results. The verify table and the
+// host facts are collected for the machine readable output in both cases
+func printVerifyResult(writer io.Writer, header string, comparisons map[string]map[string]note.FieldComparison, tuneApp *app.App, result *system.JPNotes) {
+	if system.StructuredOut() {
+		// needed by 'saptune compare'
+		result.Host = hostFacts()
+	}
+	if !system.IsFlagSet("summary") {
+		PrintNoteFields(writer, header, comparisons, true, result)
+		return
+	}
+	PrintNoteFields(ioutil.Discard, header, comparisons, true, result)
+	summary := summariseVerify(comparisons, tuneApp)
+	printVerifySummary(writer, summary)
+	result.Summary = &summary
+}
+
+// addCompliance counts a parameter in its compliance category
+func addCompliance(counts *system.JComplCounts, compliance string) {
+	switch compliance {
+	case note.ComplianceCompliant:
+		counts.Compliant++
+	case note.ComplianceNonCompliant:
+		counts.NonCompliant++
+	case note.ComplianceNotApplicable:
+		counts.NotApplicable++
+	case note.ComplianceUntouched:
+		counts.Untouched++
+	case note.ComplianceInformational:
+		counts.Informational++
+	}
+}
+
+// complianceScore returns the percentage of the compliant parameters of all
+// evaluated (compliant and non-compliant) parameters. Not applicable,
+// untouched and informational parameters do not influence the score
+func complianceScore(counts system.JComplCounts) int {
+	evaluated := counts.Compliant + counts.NonCompliant
+	if evaluated == 0 {
+		return 100
+	}
+	return counts.Compliant * 100 / evaluated
+}
+
+// summariseVerify counts the parameters of the comparison results per Note
+// and per section and collects the top offending parameters.
+// The overall score is the score of all Notes weighted by their number of
+// evaluated parameters
+func summariseVerify(comparisons map[string]map[string]note.FieldComparison, tuneApp *app.App) system.JVerifySummary {
+	summary := system.JVerifySummary{Notes: []system.JSummaryEntry{}, Sections: []system.JSummaryEntry{}, TopOffenders: []system.JOffender{}}
+	sectCounts := make(map[string]*system.JComplCounts)
+	offenders := make(map[string]*system.JOffender)
+	noteIDs := make([]string, 0, len(comparisons))
+	for noteID := range comparisons {
+		noteIDs = append(noteIDs, noteID)
+	}
+	sort.Strings(noteIDs)
+	now := time.Now()
+	for _, noteID := range noteIDs {
+		sections := noteParamSections(noteID, tuneApp)
+		expired := expiredOverrides(noteID, comparisons[noteID], now)
+		noteCounts := system.JComplCounts{}
+		for _, comparison := range comparisons[noteID] {
+			if comparison.ReflectFieldName != "SysctlParams" || comparison.ReflectMapKey == "reminder" {
+				continue
+			}
+			key := comparison.ReflectMapKey
+			compliance := note.ParamCompliance(comparison, expired[key])
+			section := sections[key]
+			if section == "" {
+				section = summaryOtherSection
+			}
+			if _, ok := sectCounts[section]; !ok {
+				sectCounts[section] = &system.JComplCounts{}
+			}
+			addCompliance(&noteCounts, compliance)
+			addCompliance(sectCounts[section], compliance)
+			addCompliance(&summary.Counts, compliance)
+			if compliance != note.ComplianceNonCompliant {
+				continue
+			}
+			if _, ok := offenders[key]; !ok {
+				offenders[key] = &system.JOffender{Parameter: key, Notes: []string{}, ExpValue: strings.Replace(comparison.ExpectedValueJS, "\t", " ", -1), ActValue: strings.Replace(comparison.ActualValueJS, "\t", " ", -1)}
+			}
+			offenders[key].Notes = append(offenders[key].Notes, noteID)
+		}
+		summary.Notes = append(summary.Notes, system.JSummaryEntry{Name: noteID, Score: complianceScore(noteCounts), Counts: noteCounts})
+	}
+	sectNames := make([]string, 0, len(sectCounts))
+	for section := range sectCounts {
+		sectNames = append(sectNames, section)
+	}
+	sort.Strings(sectNames)
+	for _, section := range sectNames {
+		summary.Sections = append(summary.Sections, system.JSummaryEntry{Name: section, Score: complianceScore(*sectCounts[section]), Counts: *sectCounts[section]})
+	}
+	summary.Score = complianceScore(summary.Counts)
+
+	// parameters deviating in most Notes first
+	for _, offender := range offenders {
+		summary.TopOffenders = append(summary.TopOffenders, *offender)
+	}
+	sort.Slice(summary.TopOffenders, func(i, j int) bool {
+		oi, oj := summary.TopOffenders[i], summary.TopOffenders[j]
+		if len(oi.Notes) != len(oj.Notes) {
+			return len(oi.Notes) > len(oj.Notes)
+		}
+		return oi.Parameter < oj.Parameter
+	})
+	if len(summary.TopOffenders) > summaryTopOffenders {
+		summary.TopOffenders = summary.TopOffenders[:summaryTopOffenders]
+	}
+	return summary
+}
+
+// printSummaryTable prints the compliance counts of the Notes or sections
+func printSummaryTable(writer io.Writer, title string, entries []system.JSummaryEntry) {
+	fmtlen := len(title)
+	for _, entry := range entries {
+		if len(entry.Name) > fmtlen {
+			fmtlen = len(entry.Name)
+		}
+	}
+	hdrFormat := "   %-" + strconv.Itoa(fmtlen) + "s | %9s | %13s | %14s | %9s | %13s | %s\n"
+	format := "   %-" + strconv.Itoa(fmtlen) + "s | %9d | %13d | %14d | %9d | %13d | %4d%%\n"
+	fmt.Fprintf(writer, hdrFormat, title, "compliant", "non-compliant", "not applicable", "untouched", "informational", "score")
+	fmt.Fprintf(writer, "%s\n", tableSeparator([]int{fmtlen, 9, 13, 14, 9, 13}, 6))
+	for _, entry := range entries {
+		fmt.Fprintf(writer, format, entry.Name, entry.Counts.Compliant, entry.Counts.NonCompliant, entry.Counts.NotApplicable, entry.Counts.Untouched, entry.Counts.Informational, entry.Score)
+	}
+}
+
+// printVerifySummary prints the compliance summary
+func printVerifySummary(writer io.Writer, summary system.JVerifySummary) {
+	fmt.Fprintf(writer, "\nCompliance summary per Note:\n\n")
+	printSummaryTable(writer, "Note", summary.Notes)
+	fmt.Fprintf(writer, "\nCompliance summary per section:\n\n")
+	printSummaryTable(writer, "Section", summary.Sections)
+	fmt.Fprintf(writer, "\nOverall compliance score: %d%% (%d of %d evaluated parameters are compliant)\n", summary.Score, summary.Counts.Compliant, summary.Counts.Compliant+summary.Counts.NonCompliant)
+	if len(summary.TopOffenders) == 0 {
+		fmt.Fprintf(writer, "\nNo non-compliant parameters found.\n\n")
+		return
+	}
+	fmtlen := len("Parameter")
+	notelen := len("Notes")
+	explen := len("Expected")
+	for _, offender := range summary.TopOffenders {
+		if len(offender.Parameter) > fmtlen {
+			fmtlen = len(offender.Parameter)
+		}
+		if l := len(strings.Join(offender.Notes, ", ")); l > notelen {
+			notelen = l
+		}
+		if len(offender.ExpValue) > explen {
+			explen = len(offender.ExpValue)
+		}
+	}
+	format := "   %-" + strconv.Itoa(fmtlen) + "s | %-" + strconv.Itoa(notelen) + "s | %-" + strconv.Itoa(explen) + "s | %s\n"
+	fmt.Fprintf(writer, "\nTop offending parameters:\n\n")
+	fmt.Fprintf(writer, format, "Parameter", "Notes", "Expected", "Actual")
+	fmt.Fprintf(writer, "%s\n", tableSeparator([]int{fmtlen, notelen, explen}, 10))
+	for _, offender := range summary.TopOffenders {
+		fmt.Fprintf(writer, format, offender.Parameter, strings.Join(offender.Notes, ", "), offender.ExpValue, offender.ActValue)
+	}
+	fmt.Fprintf(writer, "\n")
+}
@@ -0,0 +1,89 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummariseVerify(t *testing.T) {
+	tstDir := t.TempDir()
+	confFile := path.Join(tstDir, "filterNote.conf")
+	_ = ioutil.WriteFile(confFile, []byte("[sysctl]\nvm.swappiness = 10\nvm.dirty_ratio = 10\nkernel.shmmni = 32768\n\n[cpu]\nforce_latency = 70\n"), 0644)
+	filterApp := &app.App{AllNotes: map[string]note.Note{"filterNote": note.INISettings{ConfFilePath: confFile, ID: "filterNote"}}}
+	comparisons := verifyFilterComparisons()
+	comparisons["otherNote"]["SysctlParams[vm.swappiness]"] = note.FieldComparison{ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.swappiness", ActualValue: "60", ExpectedValue: "20", ActualValueJS: "60", ExpectedValueJS: "20", MatchExpectation: false}
+
+	summary := summariseVerify(comparisons, filterApp)
+	if summary.Score != 40 || summary.Counts != (system.JComplCounts{Compliant: 2, NonCompliant: 3, NotApplicable: 1}) {
+		t.Errorf("wrong overall result '%d' - '%+v'", summary.Score, summary.Counts)
+	}
+	expNotes := []system.JSummaryEntry{
+		{Name: "filterNote", Score: 66, Counts: system.JComplCounts{Compliant: 2, NonCompliant: 1, NotApplicable: 1}},
+		{Name: "otherNote", Score: 0, Counts: system.JComplCounts{NonCompliant: 2}},
+	}
+	if !reflect.DeepEqual(summary.Notes, expNotes) {
+		t.Errorf("got '%+v', expected '%+v'", summary.Notes, expNotes)
+	}
+	// parameters of otherNote have no known section
+	expSections := []system.JSummaryEntry{
+		{Name: "cpu", Score: 100, Counts: system.JComplCounts{NotApplicable: 1}},
+		{Name: "other", Score: 0, Counts: system.JComplCounts{NonCompliant: 2}},
+		{Name: "sysctl", Score: 66, Counts: system.JComplCounts{Compliant: 2, NonCompliant: 1}},
+	}
+	if !reflect.DeepEqual(summary.Sections, expSections) {
+		t.Errorf("got '%+v', expected '%+v'", summary.Sections, expSections)
+	}
+	if len(summary.TopOffenders) != 2 || summary.TopOffenders[0].Parameter != "vm.swappiness" || !reflect.DeepEqual(summary.TopOffenders[0].Notes, []string{"filterNote", "otherNote"}) || summary.TopOffenders[1].Parameter != "vm.max_map_count" {
+		t.Errorf("wrong top offenders '%+v'", summary.TopOffenders)
+	}
+
+	buffer := bytes.Buffer{}
+	printVerifySummary(&buffer, summary)
+	txt := buffer.String()
+	for _, line := range []string{
+		"   Note       | compliant | non-compliant | not applicable | untouched | informational | score\n",
+		"   filterNote |         2 |             1 |              1 |         0 |             0 |   66%\n",
+		"Overall compliance score: 40% (2 of 5 evaluated parameters are compliant)\n",
+		"   vm.swappiness    | filterNote, otherNote | 10         | 60\n",
+	} {
+		if !strings.Contains(txt, line) {
+			t.Errorf("missing '%s' in '%s'", line, txt)
+		}
+	}
+}
+
+func TestPrintVerifyResultSummary(t *testing.T) {
+	defer func() {
+		os.Args = []string{"saptune"}
+		system.RereadArgs()
+	}()
+	os.Args = []string{"saptune", "note", "verify", "--summary"}
+	system.RereadArgs()
+	comparisons := map[string]map[string]note.FieldComparison{
+		"otherNote": {
+			"ConfFilePath":                   {ReflectFieldName: "ConfFilePath", ActualValue: "/nonexisting/otherNote", ExpectedValue: "/nonexisting/otherNote", MatchExpectation: true},
+			"SysctlParams[vm.max_map_count]": {ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.max_map_count", ActualValue: "2147483647", ExpectedValue: "2147483647", ActualValueJS: "2147483647", ExpectedValueJS: "2147483647", MatchExpectation: true},
+		},
+	}
+	buffer := bytes.Buffer{}
+	result := system.JPNotes{}
+	printVerifyResult(&buffer, "NONE", comparisons, tApp, &result)
+	if result.Summary == nil || result.Summary.Score != 100 {
+		t.Errorf("wrong summary '%+v'", result.Summary)
+	}
+	if len(result.Verifications) != 1 {
+		t.Errorf("verify table not collected '%+v'", result.Verifications)
+	}
+	txt := buffer.String()
+	if strings.Contains(txt, "vm.max_map_count") || !strings.Contains(txt, "No non-compliant parameters found.\n") {
+		t.Errorf("wrong summary output '%s'", txt)
+	}
+}
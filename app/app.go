@@ -14,6 +14,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 )
 
 // define saptunes main configuration file and variables
@@ -194,7 +195,7 @@ func (app *App) IsSolutionApplied(sol string) (string, bool) {
 	state := ""
 	ret := false
 	if len(app.TuneForSolutions) != 0 {
-		if app.TuneForSolutions[0] == sol {
+		if app.IsSolutionEnabled(sol) {
 			noteOK := 0
 			noteCnt := 0
 			for _, note := range app.AllSolutions[sol] {
@@ -215,18 +216,44 @@ func (app *App) IsSolutionApplied(sol string) (string, bool) {
 	return state, ret
 }
 
-// AppliedSolution returns the currently applied Solution
+// AppliedSolution returns the first currently applied Solution
 func (app *App) AppliedSolution() (string, string) {
 	solApplied := ""
 	state := ""
-	if len(app.TuneForSolutions) != 0 {
-		solName := app.TuneForSolutions[0]
+	sols, states := app.AppliedSolutions()
+	if len(sols) != 0 {
+		solApplied = sols[0]
+		state = states[0]
+	}
+	return solApplied, state
+}
+
+// AppliedSolutions returns all currently applied Solutions and their
+// states ('fully' or 'partial')
+func (app *App) AppliedSolutions() ([]string, []string) {
+	solsApplied := []string{}
+	states := []string{}
+	for _, solName := range app.TuneForSolutions {
 		if st, ok := app.IsSolutionApplied(solName); ok {
-			solApplied = solName
-			state = st
+			solsApplied = append(solsApplied, solName)
+			states = append(states, st)
 		}
 	}
-	return solApplied, state
+	return solsApplied, states
+}
+
+// SolutionsOfNote returns the enabled Solutions, which contain the note
+func (app *App) SolutionsOfNote(noteID string) []string {
+	sols := []string{}
+	for _, solName := range app.TuneForSolutions {
+		for _, solNote := range app.AllSolutions[solName] {
+			if solNote == noteID {
+				sols = append(sols, solName)
+				break
+			}
+		}
+	}
+	return sols
 }
 
 // NoteSanityCheck checks, if for all notes listed in
@@ -350,6 +377,12 @@ func (app *App) TuneNote(noteID string) error {
 	if err != nil {
 		return err
 	}
+	if app.PositionInNoteApplyOrder(noteID) < 0 {
+		// note not yet enabled, check the declared dependencies
+		if err := app.CheckNoteDependencies(noteID, app.NoteApplyOrder); err != nil {
+			return err
+		}
+	}
 	solNotes := app.GetSortedSolutionEnabledNotes()
 	searchInSol := sort.SearchStrings(solNotes, noteID)
 	searchInNote := sort.SearchStrings(app.TuneForNotes, noteID)
@@ -373,7 +406,7 @@ func (app *App) TuneNote(noteID string) error {
 
 	// check, if system already complies with the requirements.
 	// set values for later use
-	conforming, _, valApplyList, err := app.VerifyNote(noteID)
+	conforming, _, valApplyList, err := app.compareNote(noteID)
 	if err != nil {
 		return err
 	}
@@ -422,6 +455,21 @@ func (app *App) TuneSolution(solName string) (removedExplicitNotes []string, err
 	if err != nil {
 		return
 	}
+	// apply required notes first and refuse conflicting notes, before
+	// changing the configuration
+	if sol, err = app.OrderByDependencies(sol); err != nil {
+		return
+	}
+	enabled := append([]string{}, app.NoteApplyOrder...)
+	for _, noteID := range sol {
+		if app.PositionInNoteApplyOrder(noteID) >= 0 {
+			continue
+		}
+		if err = app.CheckNoteDependencies(noteID, enabled); err != nil {
+			return
+		}
+		enabled = append(enabled, noteID)
+	}
 	if i := sort.SearchStrings(app.TuneForSolutions, solName); !(i < len(app.TuneForSolutions) && app.TuneForSolutions[i] == solName) {
 		app.TuneForSolutions = append(app.TuneForSolutions, solName)
 		sort.Strings(app.TuneForSolutions)
@@ -512,6 +560,70 @@ func (app *App) RevertNote(noteID string, permanent bool) error {
 	return nil
 }
 
+// RevertParameter reverts a single parameter set by the given applied note.
+// The parameter is set to the value of the note applied before or to the
+// start value, if there is no other note left. The note itself stays
+// applied, only the note entry is removed from the parameter state file.
+func (app *App) RevertParameter(param, noteID string) error {
+	if _, applied := app.IsNoteApplied(noteID); !applied {
+		return fmt.Errorf("note '%s' is not applied", noteID)
+	}
+	if !note.IDInParameterList(noteID, note.GetSavedParameterNotes(param).AllNotes) {
+		return fmt.Errorf("parameter '%s' is not set by note '%s'", param, noteID)
+	}
+	noteTemplate, err := app.GetNoteByID(noteID)
+	if err != nil {
+		return err
+	}
+	iniNote, ok := noteTemplate.(note.INISettings)
+	if !ok {
+		return fmt.Errorf("reverting single parameters is not supported for note '%s'", noteID)
+	}
+	ini, err := txtparser.ParseINIFile(iniNote.ConfFilePath, false)
+	if err != nil {
+		return err
+	}
+	switch section := paramSection(ini, param); section {
+	case note.INISectionSysctl, note.INISectionSys, note.INISectionVM, note.INISectionBlock, note.INISectionCPU, note.INISectionMEM:
+	case "":
+		return fmt.Errorf("parameter '%s' is not defined in note '%s'", param, noteID)
+	default:
+		return fmt.Errorf("reverting single parameters of section [%s] is not supported, please revert the whole note '%s'", section, noteID)
+	}
+
+	// the saved state of the note is needed to apply the value
+	var noteReflectValue = reflect.New(reflect.TypeOf(noteTemplate))
+	var noteIface interface{} = noteReflectValue.Interface()
+	if err := app.State.Retrieve(noteID, &noteIface); err != nil {
+		return err
+	}
+	savedNote, ok := noteIface.(*note.INISettings)
+	if !ok {
+		return fmt.Errorf("reverting single parameters is not supported for note '%s'", noteID)
+	}
+	value, _ := note.RevertParameter(param, noteID)
+	if value == "" {
+		return nil
+	}
+	params := make(map[string]string)
+	for key, val := range savedNote.SysctlParams {
+		params[key] = val
+	}
+	params[param] = value
+	savedNote.SysctlParams = params
+	return savedNote.SetValuesToApply([]string{param}).Apply()
+}
+
+// paramSection returns the section of the parameter in the note definition
+func paramSection(ini *txtparser.INIFile, param string) string {
+	for _, entry := range ini.AllValues {
+		if entry.Key == param {
+			return entry.Section
+		}
+	}
+	return ""
+}
+
 // RemoveSolFromConfig removes the given solution from the configuration
 func (app *App) RemoveSolFromConfig(solName string) error {
 	i := sort.SearchStrings(app.TuneForSolutions, solName)
@@ -609,7 +721,24 @@ func (app *App) RevertAll(permanent bool) error {
 // to the note's guidelines.
 // The note comparison results will always contain all fields, no matter
 // the note is currently conforming or not.
+// A note with expired overrides (see '@expires' in the override file) is
+// not conforming.
 func (app *App) VerifyNote(noteID string) (conforming bool, comparisons map[string]note.FieldComparison, valApplyList []string, err error) {
+	conforming, comparisons, valApplyList, err = app.compareNote(noteID)
+	if err != nil {
+		return
+	}
+	expired := note.ExpiredOverrides(txtparser.GetOverrideMeta(noteID), comparisons, time.Now())
+	for _, key := range expired {
+		system.WarningLog("override of parameter '%s' in note '%s' has expired", key, noteID)
+		conforming = false
+	}
+	return
+}
+
+// compareNote compares the current system settings with the optimised
+// settings of the note
+func (app *App) compareNote(noteID string) (conforming bool, comparisons map[string]note.FieldComparison, valApplyList []string, err error) {
 	theNote, err := app.GetNoteByID(noteID)
 	if err != nil {
 		return
@@ -7,6 +7,7 @@ import (
 	"github.com/SUSE/saptune/sap/param"
 	"github.com/SUSE/saptune/sap/solution"
 	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"io/ioutil"
 	"os"
 	"path"
@@ -737,3 +738,14 @@ func TestInitialiseApp(t *testing.T) {
 		t.Error(tstApp)
 	}
 }
+
+func TestParamSection(t *testing.T) {
+	// the classification in [severity] does not hide the real section
+	ini := txtparser.ParseINI("[severity]\nvm.swappiness = critical\n\n[sysctl]\nvm.swappiness = 10\n")
+	if section := paramSection(ini, "vm.swappiness"); section != "sysctl" {
+		t.Errorf("got '%s', expected 'sysctl'", section)
+	}
+	if section := paramSection(ini, "KSM"); section != "" {
+		t.Errorf("got '%s', expected an empty section", section)
+	}
+}
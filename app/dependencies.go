@@ -0,0 +1,114 @@
+package app
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"strings"
+)
+
+// noteDeps holds the dependency declarations of a Note from the fields
+// REQUIRES, CONFLICTS and SUPERSEDES of the version section
+type noteDeps struct {
+	requires   []string
+	conflicts  []string
+	supersedes []string
+}
+
+// getNoteDeps returns the dependency declarations of the given Note
+func (app *App) getNoteDeps(noteID string) noteDeps {
+	aNote, ok := app.AllNotes[noteID]
+	if !ok {
+		return noteDeps{}
+	}
+	requires, conflicts, supersedes := note.GetNoteDependencies(aNote)
+	return noteDeps{requires: requires, conflicts: conflicts, supersedes: supersedes}
+}
+
+// NoteConflicts returns the Notes out of 'others', which conflict with the
+// given Note. A conflict is declared by CONFLICTS or SUPERSEDES in the
+// definition of one of the two Notes
+func (app *App) NoteConflicts(noteID string, others []string) []string {
+	conflicting := []string{}
+	deps := app.getNoteDeps(noteID)
+	for _, other := range others {
+		if other == noteID {
+			continue
+		}
+		odeps := app.getNoteDeps(other)
+		if system.IsInList(other, deps.conflicts) || system.IsInList(other, deps.supersedes) || system.IsInList(noteID, odeps.conflicts) || system.IsInList(noteID, odeps.supersedes) {
+			conflicting = append(conflicting, other)
+		}
+	}
+	return conflicting
+}
+
+// CheckNoteDependencies checks, if the Note can be applied on top of the
+// given already enabled Notes.
+// All Notes required by the Note have to be enabled before, no enabled Note
+// may conflict with the Note and neither the Note nor an enabled Note may be
+// superseded by the other one.
+func (app *App) CheckNoteDependencies(noteID string, enabled []string) error {
+	deps := app.getNoteDeps(noteID)
+	for _, other := range enabled {
+		if other == noteID {
+			continue
+		}
+		odeps := app.getNoteDeps(other)
+		switch {
+		case system.IsInList(other, deps.supersedes):
+			return fmt.Errorf("note '%s' supersedes the enabled note '%s'. Please revert note '%s' first", noteID, other, other)
+		case system.IsInList(noteID, odeps.supersedes):
+			return fmt.Errorf("note '%s' is superseded by the enabled note '%s'", noteID, other)
+		case system.IsInList(other, deps.conflicts) || system.IsInList(noteID, odeps.conflicts):
+			return fmt.Errorf("note '%s' conflicts with the enabled note '%s'", noteID, other)
+		}
+	}
+	missing := []string{}
+	for _, req := range deps.requires {
+		if !system.IsInList(req, enabled) {
+			missing = append(missing, req)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("note '%s' requires the note(s) '%s' to be applied before", noteID, strings.Join(missing, " "))
+	}
+	return nil
+}
+
+// OrderByDependencies sorts the given Notes, so that each Note follows the
+// Notes it requires. Apart from that the original order is kept.
+func (app *App) OrderByDependencies(noteIDs []string) ([]string, error) {
+	ordered := []string{}
+	done := make(map[string]bool)
+	visiting := make(map[string]bool)
+	var visit func(noteID string, path []string) error
+	visit = func(noteID string, path []string) error {
+		if done[noteID] {
+			return nil
+		}
+		if visiting[noteID] {
+			return fmt.Errorf("circular note dependency '%s'", strings.Join(append(path, noteID), " -> "))
+		}
+		visiting[noteID] = true
+		for _, req := range app.getNoteDeps(noteID).requires {
+			if !system.IsInList(req, noteIDs) {
+				// not part of the list, checked during apply
+				continue
+			}
+			if err := visit(req, append(path, noteID)); err != nil {
+				return err
+			}
+		}
+		visiting[noteID] = false
+		done[noteID] = true
+		ordered = append(ordered, noteID)
+		return nil
+	}
+	for _, noteID := range noteIDs {
+		if err := visit(noteID, []string{}); err != nil {
+			return noteIDs, err
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/sap/note"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func depsTestApp(t *testing.T, deps map[string]string) *App {
+	tmpDir, err := ioutil.TempDir("", "saptune-deps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allNotes := make(map[string]note.Note)
+	runFiles := []string{}
+	for noteID, dep := range deps {
+		content := fmt.Sprintf("[version]\nVERSION=1\nDATE=01.01.2023\nDESCRIPTION=dependency test %s\nREFERENCES=\n%s\n\n[sysctl]\nvm.swappiness=10\n", noteID, dep)
+		// the version section is cached by file name, so keep it unique
+		confName := fmt.Sprintf("%s_%s.conf", noteID, path.Base(tmpDir))
+		confFile := path.Join(tmpDir, confName)
+		runFiles = append(runFiles, fmt.Sprintf("/run/saptune/sections/version_%s.run", confName))
+		if err := ioutil.WriteFile(confFile, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		allNotes[noteID] = note.INISettings{ConfFilePath: confFile, ID: noteID}
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(tmpDir)
+		for _, runFile := range runFiles {
+			os.Remove(runFile)
+		}
+	})
+	return &App{AllNotes: allNotes}
+}
+
+func TestCheckNoteDependencies(t *testing.T) {
+	tApp := depsTestApp(t, map[string]string{
+		"depA": "",
+		"depB": "REQUIRES=depA",
+		"depC": "CONFLICTS=depA",
+		"depD": "SUPERSEDES=depB",
+	})
+	if err := tApp.CheckNoteDependencies("depB", []string{}); err == nil {
+		t.Error("expected error for missing required note")
+	}
+	if err := tApp.CheckNoteDependencies("depB", []string{"depA"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := tApp.CheckNoteDependencies("depC", []string{"depA"}); err == nil {
+		t.Error("expected error for conflicting note")
+	}
+	// conflicts are checked in both directions
+	if err := tApp.CheckNoteDependencies("depA", []string{"depC"}); err == nil {
+		t.Error("expected error for conflicting note")
+	}
+	if err := tApp.CheckNoteDependencies("depD", []string{"depA", "depB"}); err == nil {
+		t.Error("expected error for superseded note")
+	}
+	if err := tApp.CheckNoteDependencies("depB", []string{"depA", "depD"}); err == nil {
+		t.Error("expected error for superseding note")
+	}
+
+	conflicts := tApp.NoteConflicts("depA", []string{"depA", "depB", "depC"})
+	if !reflect.DeepEqual(conflicts, []string{"depC"}) {
+		t.Errorf("got: '%+v', expected: '[depC]'\n", conflicts)
+	}
+}
+
+func TestOrderByDependencies(t *testing.T) {
+	tApp := depsTestApp(t, map[string]string{
+		"depA": "REQUIRES=depC, depB",
+		"depB": "",
+		"depC": "REQUIRES=depB",
+		"depD": "REQUIRES=depX",
+	})
+	ordered, err := tApp.OrderByDependencies([]string{"depA", "depD", "depB", "depC"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	exp := []string{"depB", "depC", "depA", "depD"}
+	if !reflect.DeepEqual(ordered, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", ordered, exp)
+	}
+
+	tApp = depsTestApp(t, map[string]string{
+		"depA": "REQUIRES=depB",
+		"depB": "REQUIRES=depA",
+	})
+	if _, err := tApp.OrderByDependencies([]string{"depA", "depB"}); err == nil {
+		t.Error("expected error for circular dependency")
+	}
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"path"
+	"sort"
+	"strings"
+)
+
+// overrideDir is the directory of the override files of the notes
+var overrideDir = txtparser.OverrideTuningSheets
+
+// ParamConflict describes a parameter, which is set to different values by
+// two notes of the enabled solutions
+type ParamConflict struct {
+	Param  string
+	Note1  string
+	Value1 string
+	Sol1   string
+	Note2  string
+	Value2 string
+	Sol2   string
+}
+
+// SolutionParamConflicts checks the union of the notes of the given
+// solutions for parameters, which are set to different values by two notes
+// of the union. The value of the note applied last will win in such a case.
+// The solutions are only used to report, which solutions contribute the
+// notes. A note shared by several solutions lists all of them
+func (app *App) SolutionParamConflicts(sols []string) []ParamConflict {
+	conflicts := []ParamConflict{}
+	noteParams := make(map[string]map[string]string)
+	notes := []string{}
+	noteSols := make(map[string][]string)
+	for _, sol := range sols {
+		for _, noteID := range app.AllSolutions[sol] {
+			if _, ok := noteSols[noteID]; !ok {
+				notes = append(notes, noteID)
+			}
+			if !system.IsInList(sol, noteSols[noteID]) {
+				noteSols[noteID] = append(noteSols[noteID], sol)
+			}
+		}
+	}
+	for i, note1 := range notes {
+		params1 := app.cachedNoteParams(note1, noteParams)
+		for _, note2 := range notes[i+1:] {
+			params2 := app.cachedNoteParams(note2, noteParams)
+			for param, value1 := range params1 {
+				if value2, ok := params2[param]; ok && value1 != value2 {
+					conflicts = append(conflicts, ParamConflict{Param: param, Note1: note1, Value1: value1, Sol1: strings.Join(noteSols[note1], ", "), Note2: note2, Value2: value2, Sol2: strings.Join(noteSols[note2], ", ")})
+				}
+			}
+		}
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool { return conflicts[i].Param < conflicts[j].Param })
+	return conflicts
+}
+
+// cachedNoteParams returns the parameters defined by the note definition
+// file with the values of an existing override file. Parameters disabled by
+// the override file are skipped. The result is cached in 'cache'
+func (app *App) cachedNoteParams(noteID string, cache map[string]map[string]string) map[string]string {
+	if params, ok := cache[noteID]; ok {
+		return params
+	}
+	params := make(map[string]string)
+	cache[noteID] = params
+	iniNote, ok := app.AllNotes[noteID].(note.INISettings)
+	if !ok {
+		return params
+	}
+	ini, err := txtparser.ParseINIFile(iniNote.ConfFilePath, false)
+	if err != nil {
+		return params
+	}
+	// a missing override file results in a nil override
+	override, _ := txtparser.ParseINIFile(path.Join(overrideDir, noteID), false)
+	for _, entry := range ini.AllValues {
+		switch entry.Section {
+		case note.INISectionVersion, note.INISectionReminder, note.INISectionRpm:
+			continue
+		}
+		value := entry.Value
+		if override != nil {
+			if ovEntry, ok := override.KeyValue[entry.Section][entry.Key]; ok {
+				if ovEntry.Value == "" {
+					// parameter untouched by the override
+					continue
+				}
+				value = ovEntry.Value
+			}
+		}
+		params[entry.Key] = value
+	}
+	return params
+}
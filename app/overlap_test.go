@@ -0,0 +1,97 @@
+package app
+
+import (
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/sap/solution"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func TestSolutionParamConflicts(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "saptune-overlap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	notes := map[string]string{
+		"ovNote1": "[sysctl]\nvm.swappiness=10\nkernel.shmmni=4096\n",
+		"ovNote2": "[sysctl]\nvm.swappiness=60\nkernel.shmmni=4096\n",
+		"ovNote3": "[sysctl]\nvm.swappiness=1\n",
+	}
+	allNotes := make(map[string]note.Note)
+	for noteID, content := range notes {
+		confFile := path.Join(tmpDir, noteID)
+		if err := ioutil.WriteFile(confFile, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		allNotes[noteID] = note.INISettings{ConfFilePath: confFile, ID: noteID}
+	}
+	tApp := &App{
+		AllNotes: allNotes,
+		AllSolutions: map[string]solution.Solution{
+			"ovSol1": {"ovNote1", "ovNote3"},
+			"ovSol2": {"ovNote2", "ovNote3"},
+		},
+		TuneForSolutions: []string{"ovSol1", "ovSol2"},
+	}
+	// ovNote3 is part of both solutions and conflicts with the notes
+	// of each solution
+	exp := []ParamConflict{
+		{Param: "vm.swappiness", Note1: "ovNote1", Value1: "10", Sol1: "ovSol1", Note2: "ovNote3", Value2: "1", Sol2: "ovSol1, ovSol2"},
+		{Param: "vm.swappiness", Note1: "ovNote1", Value1: "10", Sol1: "ovSol1", Note2: "ovNote2", Value2: "60", Sol2: "ovSol2"},
+		{Param: "vm.swappiness", Note1: "ovNote3", Value1: "1", Sol1: "ovSol1, ovSol2", Note2: "ovNote2", Value2: "60", Sol2: "ovSol2"},
+	}
+	if conflicts := tApp.SolutionParamConflicts([]string{"ovSol1", "ovSol2"}); !reflect.DeepEqual(conflicts, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", conflicts, exp)
+	}
+	// shared note with the same value as the note of the other solution
+	_ = ioutil.WriteFile(path.Join(tmpDir, "ovNote3"), []byte("[sysctl]\nvm.swappiness=60\n"), 0644)
+	exp = []ParamConflict{
+		{Param: "vm.swappiness", Note1: "ovNote1", Value1: "10", Sol1: "ovSol1", Note2: "ovNote3", Value2: "60", Sol2: "ovSol1, ovSol2"},
+		{Param: "vm.swappiness", Note1: "ovNote1", Value1: "10", Sol1: "ovSol1", Note2: "ovNote2", Value2: "60", Sol2: "ovSol2"},
+	}
+	if conflicts := tApp.SolutionParamConflicts([]string{"ovSol1", "ovSol2"}); !reflect.DeepEqual(conflicts, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", conflicts, exp)
+	}
+	if conflicts := tApp.SolutionParamConflicts([]string{"ovSol2"}); len(conflicts) != 0 {
+		t.Errorf("got: '%+v', expected no conflicts\n", conflicts)
+	}
+
+	// override files resolve and introduce conflicts
+	oldOverrideDir := overrideDir
+	defer func() { overrideDir = oldOverrideDir }()
+	overrideDir = path.Join(tmpDir, "override")
+	_ = os.MkdirAll(overrideDir, 0755)
+	overrides := map[string]string{
+		"ovNote1": "[sysctl]\nvm.swappiness=60\n",
+		"ovNote2": "[sysctl]\nkernel.shmmni=8192\n",
+	}
+	for noteID, content := range overrides {
+		if err := ioutil.WriteFile(path.Join(overrideDir, noteID), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exp = []ParamConflict{{Param: "kernel.shmmni", Note1: "ovNote1", Value1: "4096", Sol1: "ovSol1", Note2: "ovNote2", Value2: "8192", Sol2: "ovSol2"}}
+	if conflicts := tApp.SolutionParamConflicts([]string{"ovSol1", "ovSol2"}); !reflect.DeepEqual(conflicts, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", conflicts, exp)
+	}
+	// parameter untouched by the override file
+	_ = ioutil.WriteFile(path.Join(overrideDir, "ovNote2"), []byte("[sysctl]\nkernel.shmmni=\nvm.swappiness=5\n"), 0644)
+	exp = []ParamConflict{
+		{Param: "vm.swappiness", Note1: "ovNote1", Value1: "60", Sol1: "ovSol1", Note2: "ovNote2", Value2: "5", Sol2: "ovSol2"},
+		{Param: "vm.swappiness", Note1: "ovNote3", Value1: "60", Sol1: "ovSol1, ovSol2", Note2: "ovNote2", Value2: "5", Sol2: "ovSol2"},
+	}
+	if conflicts := tApp.SolutionParamConflicts([]string{"ovSol1", "ovSol2"}); !reflect.DeepEqual(conflicts, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", conflicts, exp)
+	}
+
+	if sols := tApp.SolutionsOfNote("ovNote3"); !reflect.DeepEqual(sols, []string{"ovSol1", "ovSol2"}) {
+		t.Errorf("got: '%+v', expected: '[ovSol1 ovSol2]'\n", sols)
+	}
+	if sols := tApp.SolutionsOfNote("ovNote1"); !reflect.DeepEqual(sols, []string{"ovSol1"}) {
+		t.Errorf("got: '%+v', expected: '[ovSol1]'\n", sols)
+	}
+}
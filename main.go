@@ -34,6 +34,19 @@ func main() {
 	if !system.ChkCliSyntax() {
 		actions.PrintHelpAndExit(os.Stdout, 1)
 	}
+	if system.CliArg(1) == "generate" && !system.IsFlagSet("help") {
+		// needs neither root privilege nor a saptune configuration
+		system.JnotSupportedYet()
+		actions.GenerateAction(os.Stdout, system.CliArg(2), system.CliArg(3))
+		system.ErrorExit("", 0)
+	}
+	if system.CliArg(1) == "compare" && !system.IsFlagSet("help") {
+		// works on snapshot files only, so it needs neither root
+		// privilege nor a saptune configuration
+		system.JnotSupportedYet()
+		actions.CompareAction(os.Stdout, system.CliArg(2), system.CliArg(3))
+		system.ErrorExit("", 0)
+	}
 
 	// get saptune version and log switches from saptune sysconfig file
 	SaptuneVersion = checkSaptuneConfigFile(os.Stderr, app.SysconfigSaptuneFile, logSwitch)
@@ -46,7 +59,7 @@ func main() {
 	}
 	if arg1 == "help" || system.IsFlagSet("help") {
 		system.JnotSupportedYet()
-		actions.PrintHelpAndExit(os.Stdout, 0)
+		actions.PrintCmdHelpAndExit(os.Stdout, 0)
 	}
 	if arg1 == "" {
 		actions.PrintHelpAndExit(os.Stdout, 1)
@@ -66,7 +79,9 @@ func main() {
 
 	if arg1 == "lock" {
 		if arg2 := system.CliArg(2); arg2 == "remove" {
-			system.ReleaseSaptuneLock()
+			if err := system.RemoveSaptuneLock(); err != nil {
+				system.ErrorExit("%v", err)
+			}
 			system.InfoLog("command line triggered remove of lock file '/run/.saptune.lock'\n")
 			system.ErrorExit("", 0)
 		} else {
@@ -75,15 +90,21 @@ func main() {
 	}
 	callSaptuneCheckScript(arg1)
 
-	// only one instance of saptune should run
-	// check and set saptune lock file
-	system.SaptuneLock()
+	// only one instance of saptune should change the system, read-only
+	// commands may run concurrently
+	// check and set saptune lock
+	system.SaptuneLock(system.CmdLockMode(system.CliArgs(1)), system.LockWait())
 	defer system.ReleaseSaptuneLock()
+	// read-only commands share the lock, so they must not change runtime
+	// files, configuration or states used by concurrent saptune calls
+	exclusive := system.HoldsExclusiveLock()
 
-	// cleanup runtime files
-	system.CleanUpRun()
-	// additional clear ignore flag for the sapconf/saptune service deadlock
-	os.Remove("/run/.saptune.ignore")
+	if exclusive {
+		// cleanup runtime files
+		system.CleanUpRun()
+		// additional clear ignore flag for the sapconf/saptune service deadlock
+		os.Remove("/run/.saptune.ignore")
+	}
 
 	//check, running config exists
 	checkWorkingArea()
@@ -118,8 +139,10 @@ func main() {
 	tuneApp = app.InitialiseApp("", "", tuningOptions, archSolutions)
 
 	checkUpdateLeftOvers()
-	if err := tuneApp.NoteSanityCheck(); err != nil {
-		system.ErrorExit("Error during NoteSanityCheck - '%v'\n", err)
+	if exclusive {
+		if err := tuneApp.NoteSanityCheck(); err != nil {
+			system.ErrorExit("Error during NoteSanityCheck - '%v'\n", err)
+		}
 	}
 	checkForTuned()
 	actions.SelectAction(os.Stdout, tuneApp, SaptuneVersion)
@@ -6,3 +6,6 @@
 
 [ArchPPC64LE]
 941735 1771258 1980196 1984787 2205917 2382421 2534844 2993054 1656250
+
+[ArchARM64]
+941735 1771258 1980196 1984787 2205917 2382421 2534844 2993054 1656250
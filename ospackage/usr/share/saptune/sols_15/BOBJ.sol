@@ -6,3 +6,9 @@
 
 [ArchPPC64LE]
 941735 1771258 2578899 SAP_BOBJ 2993054 1656250
+
+[ArchARM64]
+941735 1771258 2578899 SAP_BOBJ 2993054 1656250
+
+[ArchS390X]
+941735 1771258 2578899 SAP_BOBJ 2993054 1656250
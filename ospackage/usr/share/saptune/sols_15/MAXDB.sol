@@ -6,3 +6,9 @@
 
 [ArchPPC64LE]
 941735 1771258 2578899
+
+[ArchARM64]
+941735 1771258 2578899
+
+[ArchS390X]
+941735 1771258 2578899
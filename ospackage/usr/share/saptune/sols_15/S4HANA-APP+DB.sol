@@ -1,8 +1,10 @@
 [version]
 # SAP-NOTE=S4HANA-APP+DB CATEGORY=SOLUTION VERSION=1 DATE=07.07.2021 NAME="Definition of S4HANA-APP+DB solution for SLE15"
+INCLUDES=S4HANA-DBSERVER S4HANA-APPSERVER
+EXCLUDE=900929
 
 [ArchX86]
-941735 1771258 1980196 2578899 2684254 2382421 2534844 2993054 1656250
 
 [ArchPPC64LE]
-941735 1771258 1980196 2578899 2684254 2382421 2534844 2993054 1656250
+
+[ArchARM64]
@@ -0,0 +1,185 @@
+package note
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CustomiseChange describes a change of a parameter in an override file
+type CustomiseChange struct {
+	Parameter string
+	Action    string // 'set', 'untouched' or 'unset'
+	OldValue  string
+	NewValue  string
+}
+
+// regCustomiseArg splits the argument of '--set' into parameter, operator
+// and value
+var regCustomiseArg = regexp.MustCompile(`^([\w.+_-]+)\s*([<=>]+)\s*(.*)$`)
+
+// regCustomiseLine splits a parameter line into the parameter including
+// the following spaces, the parameter name, the operator, the spaces
+// following the operator and the value
+var regCustomiseLine = regexp.MustCompile(`^(\s*([\w.+_-]+)\s*)([<=>]+)(\s*)(.*)$`)
+
+// regMetaLine matches the metadata comments in front of a parameter line
+var regMetaLine = regexp.MustCompile(`^\s*#\s*@(reason|expires|approved-by)\s*:`)
+
+// customiseLine is a parameter line found in an override file
+type customiseLine struct {
+	index  int
+	header string
+	fields []string
+}
+
+// CustomiseOverride changes the content of a Note override file.
+// 'set' contains 'parameter=value' entries, 'untouched' the parameters,
+// which should not be changed by saptune, and 'unset' the parameters, which
+// should no longer be overridden. Only parameters available in the Note
+// definition 'noteContent' can be customised. Block device specific
+// parameters like 'IO_SCHEDULER_sda' are written to a section
+// '[block:blkpat=^sda$]'.
+// Returns the new content and the list of changes. Setting a parameter to
+// its current override value is not a change.
+func CustomiseOverride(content, noteContent string, set, untouched, unset []string) (string, []CustomiseChange, error) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	changes := []CustomiseChange{}
+	type request struct {
+		action, param, op, value string
+	}
+	requests := []request{}
+	for _, arg := range set {
+		kov := regCustomiseArg.FindStringSubmatch(strings.TrimSpace(arg))
+		if kov == nil {
+			return "", nil, fmt.Errorf("wrong syntax '%s', expected 'parameter=value'", arg)
+		}
+		requests = append(requests, request{"set", kov[1], kov[2], strings.TrimSpace(kov[3])})
+	}
+	for _, param := range untouched {
+		requests = append(requests, request{"untouched", strings.TrimSpace(param), "", ""})
+	}
+	for _, param := range unset {
+		requests = append(requests, request{"unset", strings.TrimSpace(param), "", ""})
+	}
+
+	for _, req := range requests {
+		key, bdev := blockDevParam(req.param)
+		noteHeader := ""
+		if noteLines := findCustomiseLines(strings.Split(noteContent, "\n"), key, ""); len(noteLines) != 0 {
+			noteHeader = noteLines[0].header
+		}
+		if noteHeader == "" || (bdev != "" && !strings.HasPrefix(noteHeader, "["+INISectionBlock)) {
+			return "", nil, fmt.Errorf("parameter '%s' is not part of the Note definition, only available parameters can be customised", req.param)
+		}
+		header := ""
+		if bdev != "" {
+			header = fmt.Sprintf("[%s:blkpat=^%s$]", INISectionBlock, bdev)
+		}
+		found := findCustomiseLines(lines, key, header)
+		if req.action == "unset" {
+			if len(found) == 0 {
+				continue
+			}
+			changes = append(changes, CustomiseChange{Parameter: req.param, Action: req.action, OldValue: found[0].fields[5]})
+			// remove from the end to keep the indices valid
+			for i := len(found) - 1; i >= 0; i-- {
+				start := found[i].index
+				for start > 0 && regMetaLine.MatchString(lines[start-1]) {
+					start--
+				}
+				lines = append(lines[:start], lines[found[i].index+1:]...)
+			}
+			continue
+		}
+		if len(found) == 0 {
+			if header == "" {
+				header = noteHeader
+			}
+			op := req.op
+			if op == "" {
+				op = "="
+			}
+			lines = insertCustomiseLine(lines, header, strings.TrimSpace(fmt.Sprintf("%s %s %s", key, op, req.value)))
+			changes = append(changes, CustomiseChange{Parameter: req.param, Action: req.action, NewValue: req.value})
+			continue
+		}
+		changed := false
+		for _, line := range found {
+			op := line.fields[3]
+			if req.op != "" {
+				op = req.op
+			}
+			newLine := strings.TrimRight(line.fields[1]+op+line.fields[4]+req.value, " \t")
+			if newLine != strings.TrimRight(lines[line.index], " \t") {
+				lines[line.index] = newLine
+				changed = true
+			}
+		}
+		if changed {
+			changes = append(changes, CustomiseChange{Parameter: req.param, Action: req.action, OldValue: found[0].fields[5], NewValue: req.value})
+		}
+	}
+	return strings.Join(lines, "\n") + "\n", changes, nil
+}
+
+// findCustomiseLines returns the parameter lines of 'key'. If 'header' is
+// empty, all sections except the block device specific sections written by
+// CustomiseOverride are searched, otherwise only the section 'header'
+func findCustomiseLines(lines []string, key, header string) []customiseLine {
+	found := []customiseLine{}
+	curHeader := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			curHeader = trimmed
+			continue
+		}
+		section := strings.Split(strings.Trim(curHeader, "[]"), ":")[0]
+		if section == "" || section == INISectionVersion || section == INISectionReminder || section == INISectionSeverity || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if header != curHeader && (header != "" || strings.Contains(curHeader, ":blkpat=^")) {
+			continue
+		}
+		fields := regCustomiseLine.FindStringSubmatch(line)
+		if fields != nil && fields[2] == key {
+			found = append(found, customiseLine{index: i, header: curHeader, fields: fields})
+		}
+	}
+	return found
+}
+
+// insertCustomiseLine adds a parameter line at the end of the section
+// 'header'. A missing section is appended
+func insertCustomiseLine(lines []string, header, newLine string) []string {
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == header {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return append(lines, "", header, newLine)
+	}
+	end := start + 1
+	for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), "[") {
+		end++
+	}
+	for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return append(lines[:end], append([]string{newLine}, lines[end:]...)...)
+}
+
+// blockDevParam splits a block device specific parameter like
+// 'IO_SCHEDULER_sda' into the parameter name and the block device
+func blockDevParam(param string) (string, string) {
+	for _, key := range lintKeys[INISectionBlock] {
+		if strings.HasPrefix(param, key+"_") && len(param) > len(key)+1 {
+			return key, strings.TrimPrefix(param, key+"_")
+		}
+	}
+	return param, ""
+}
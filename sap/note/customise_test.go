@@ -0,0 +1,117 @@
+package note
+
+import (
+	"strings"
+	"testing"
+)
+
+var customiseNote = `[version]
+VERSION=1
+DATE=01.01.2026
+DESCRIPTION=customise test
+REFERENCES=none
+
+[sysctl]
+vm.swappiness = 60
+kernel.shmmni = 4096
+
+[vm]
+KSM=0
+
+[block]
+IO_SCHEDULER=none
+`
+
+var customiseOverride = `[version]
+VERSION=1
+DATE=01.01.2026
+DESCRIPTION=customise test
+REFERENCES=none
+
+[sysctl]
+# @reason: sizing
+vm.swappiness = 20
+kernel.shmmni = 4096
+
+[vm]
+KSM=0
+
+[block]
+IO_SCHEDULER=none
+`
+
+func TestCustomiseOverride(t *testing.T) {
+	expected := `[version]
+VERSION=1
+DATE=01.01.2026
+DESCRIPTION=customise test
+REFERENCES=none
+
+[sysctl]
+kernel.shmmni = 8192
+
+[vm]
+KSM=
+
+[block]
+IO_SCHEDULER=none
+
+[block:blkpat=^sda$]
+IO_SCHEDULER =
+`
+	content, changes, err := CustomiseOverride(customiseOverride, customiseNote, []string{"kernel.shmmni=8192", "KSM=0"}, []string{"KSM", "IO_SCHEDULER_sda"}, []string{"vm.swappiness"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != expected {
+		t.Errorf("got '%s', expected '%s'", content, expected)
+	}
+	expChanges := []CustomiseChange{
+		{Parameter: "kernel.shmmni", Action: "set", OldValue: "4096", NewValue: "8192"},
+		{Parameter: "KSM", Action: "untouched", OldValue: "0"},
+		{Parameter: "IO_SCHEDULER_sda", Action: "untouched"},
+		{Parameter: "vm.swappiness", Action: "unset", OldValue: "20"},
+	}
+	if len(changes) != len(expChanges) {
+		t.Fatalf("got '%+v'", changes)
+	}
+	for i, change := range changes {
+		if change != expChanges[i] {
+			t.Errorf("got '%+v', expected '%+v'", change, expChanges[i])
+		}
+	}
+	for _, finding := range LintNote(content) {
+		if finding.Severity == LintError {
+			t.Errorf("unexpected finding '%s'", finding)
+		}
+	}
+
+	// unset parameter is added again to its section of the Note
+	content, changes, err = CustomiseOverride(content, customiseNote, []string{"vm.swappiness<=10"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Action != "set" {
+		t.Errorf("got '%+v'", changes)
+	}
+	if expected = strings.Replace(expected, "kernel.shmmni = 8192\n", "kernel.shmmni = 8192\nvm.swappiness <= 10\n", 1); content != expected {
+		t.Errorf("got '%s', expected '%s'", content, expected)
+	}
+
+	// nothing to change
+	_, changes, err = CustomiseOverride(customiseOverride, customiseNote, []string{"vm.swappiness=20"}, nil, nil)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("got '%+v', '%v'", changes, err)
+	}
+
+	// wrong parameters
+	if _, _, err := CustomiseOverride(customiseOverride, customiseNote, []string{"vm.dirty_ratio=10"}, nil, nil); err == nil {
+		t.Error("expected an error for a parameter not part of the Note")
+	}
+	if _, _, err := CustomiseOverride(customiseOverride, customiseNote, nil, nil, []string{"KSM_sda"}); err == nil {
+		t.Error("expected an error for a block device parameter of section [vm]")
+	}
+	if _, _, err := CustomiseOverride(customiseOverride, customiseNote, []string{"vm.swappiness"}, nil, nil); err == nil {
+		t.Error("expected an error for a missing value")
+	}
+}
@@ -28,6 +28,7 @@ const (
 	INISectionRpm       = "rpm"
 	INISectionGrub      = "grub"
 	INISectionReminder  = "reminder"
+	INISectionSeverity  = "severity"
 
 	// LoginConfDir is the path to systemd's logind configuration directory under /etc.
 	LogindConfDir = "/etc/systemd/logind.conf.d"
@@ -54,6 +55,8 @@ type INISettings struct {
 	ValuesToApply   map[string]string // values to apply
 	OverrideParams  map[string]string // parameter values from the override file
 	Inform          map[string]string // special information for parameter values
+	Templates       map[string]string // template expressions of parameter values
+	Severities      map[string]string // severity of the parameters from section [severity]
 }
 
 // Name returns the name of the related SAP Note or en empty string
@@ -88,6 +91,8 @@ func (vend INISettings) Initialise() (Note, error) {
 	vend.SysctlParams = make(map[string]string)
 	vend.OverrideParams = make(map[string]string)
 	vend.Inform = make(map[string]string)
+	vend.Templates = make(map[string]string)
+	vend.Severities = make(map[string]string)
 	pc = LinuxPagingImprovements{}
 	blck = param.BlockDeviceQueue{BlockDeviceSchedulers: param.BlockDeviceSchedulers{SchedulerChoice: make(map[string]string)}, BlockDeviceNrRequests: param.BlockDeviceNrRequests{NrRequests: make(map[string]int)}, BlockDeviceReadAheadKB: param.BlockDeviceReadAheadKB{ReadAheadKB: make(map[string]int)}, BlockDeviceMaxSectorsKB: param.BlockDeviceMaxSectorsKB{MaxSectorsKB: make(map[string]int)}}
 
@@ -180,6 +185,22 @@ func (vend INISettings) Optimise() (Note, error) {
 		if next {
 			continue
 		}
+		// calculate the value of a template expression
+		if IsTemplate(param.Value) {
+			val, err := ResolveTemplate(param.Value)
+			if err != nil {
+				system.WarningLog("skipping parameter '%s' of Note '%s' - %v", param.Key, vend.ID, err)
+				continue
+			}
+			if vend.Templates != nil {
+				vend.Templates[param.Key] = param.Value
+			}
+			param.Value = val
+		}
+		// severity of the parameter
+		if sev := paramSeverity(ini.Severities, param.Key); sev != "" && vend.Severities != nil {
+			vend.Severities[param.Key] = sev
+		}
 
 		switch param.Section {
 		case INISectionSysctl:
@@ -0,0 +1,365 @@
+package note
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// severity of a lint finding
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// LintFinding is a single problem found in a Note definition file
+type LintFinding struct {
+	Line     int // 0 for problems of the whole file
+	Section  string
+	Severity string
+	Message  string
+}
+
+// String returns the finding as readable text
+func (f LintFinding) String() string {
+	loc := "file"
+	if f.Line > 0 {
+		loc = fmt.Sprintf("line %d", f.Line)
+	}
+	if f.Section != "" {
+		loc = fmt.Sprintf("%s [%s]", loc, f.Section)
+	}
+	return fmt.Sprintf("%s: %s: %s", loc, f.Severity, f.Message)
+}
+
+// lintSections are the sections supported in a Note definition file
+var lintSections = map[string]bool{INISectionVersion: true, INISectionSysctl: true, INISectionSys: true, INISectionVM: true, INISectionFS: true, INISectionCPU: true, INISectionMEM: true, INISectionBlock: true, INISectionService: true, INISectionLimits: true, INISectionLogin: true, INISectionPagecache: true, INISectionRpm: true, INISectionGrub: true, INISectionReminder: true, INISectionSeverity: true}
+
+// lintKeys are the valid parameters of the sections with a fixed set of
+// parameters
+var lintKeys = map[string][]string{
+	INISectionVM:        {"THP", "KSM"},
+	INISectionCPU:       {"force_latency", "energy_perf_bias", "governor"},
+	INISectionMEM:       {"VSZ_TMPFS_PERCENT", "ShmFileSystemSizeMB"},
+	INISectionLogin:     {"UserTasksMax"},
+	INISectionPagecache: {"ENABLE_PAGECACHE_LIMIT", "OVERRIDE_PAGECACHE_LIMIT_MB", system.SysctlPagecacheLimitIgnoreDirty},
+	INISectionBlock:     {"IO_SCHEDULER", "NRREQ", "READ_AHEAD_KB", "MAX_SECTORS_KB"},
+	INISectionFS:        {"xfs_options"},
+	INISectionLimits:    {"LIMITS"},
+}
+
+// lintChoices are the valid values of parameters with a fixed set of values
+var lintChoices = map[string][]string{
+	"THP":                                  {"always", "madvise", "never"},
+	"KSM":                                  {"0", "1"},
+	"energy_perf_bias":                     {"performance", "normal", "powersave"},
+	"ENABLE_PAGECACHE_LIMIT":               {"yes", "no"},
+	system.SysctlPagecacheLimitIgnoreDirty: {"0", "1", "2"},
+}
+
+// lintLimitItems are the valid items of a limits entry (see limits.conf(5))
+var lintLimitItems = []string{"core", "data", "fsize", "memlock", "nofile", "rss", "stack", "cpu", "nproc", "as", "maxlogins", "maxsyslogins", "nonewprivs", "priority", "locks", "sigpending", "msgqueue", "nice", "rtprio", "chroot"}
+
+// lintOperators are the supported operators
+var lintOperators = []string{txtparser.OperatorLessThan, txtparser.OperatorLessThanEqual, txtparser.OperatorMoreThan, txtparser.OperatorMoreThanEqual, txtparser.OperatorEqual}
+
+// lintTagValues are the valid values of the section tags, which do not
+// depend on the system. All other tags are checked against the content of
+// files in /sys/class/dmi/id
+var lintTagValues = map[string][]string{
+	"arch": {system.ArchUname(system.ArchAMD64), system.ArchPPC64LE, system.ArchUname(system.ArchARM64), system.ArchS390X},
+	"csp":  {system.CSPAzure, system.CSPAWS, system.CSPGoogle, system.CSPOVM, system.CSPAlibaba},
+}
+
+var lintOsTag = regexp.MustCompile(`^(1[256](-SP\d+)?|1[256]-\*)$`)
+var lintNumber = regexp.MustCompile(`^-?\d+$`)
+var lintKeyName = regexp.MustCompile(`^[\w.+-]+$`)
+
+// LintNoteFile statically checks a Note definition file and returns the
+// problems found. The system is not inspected
+func LintNoteFile(fileName string) ([]LintFinding, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return LintNote(string(content)), nil
+}
+
+// LintNote statically checks the content of a Note definition file:
+// section names and tags, parameters, operators and values per section,
+// duplicate parameters and the version section
+func LintNote(content string) []LintFinding {
+	findings := []LintFinding{}
+	add := func(line int, section, severity, format string, args ...interface{}) {
+		findings = append(findings, LintFinding{Line: line, Section: section, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	found, oldStyle, missing := txtparser.LintVersionSection(content)
+	switch {
+	case !found:
+		add(0, "", LintError, "missing section [version]")
+	case len(missing) != 0:
+		add(0, INISectionVersion, LintError, "missing mandatory field(s) %s in section [version]", strings.Join(missing, ", "))
+	case oldStyle:
+		add(0, INISectionVersion, LintWarning, "old style version header used, please switch to the VERSION, DATE, DESCRIPTION and REFERENCES fields")
+	}
+
+	section := ""
+	header := ""
+	skip := false
+	defined := make(map[string]map[string]int)
+	for num, line := range strings.Split(content, "\n") {
+		num = num + 1
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line[0] == '[' {
+			section, skip = lintSectionHeader(num, line, add)
+			header = line
+			if _, ok := defined[header]; !ok {
+				defined[header] = make(map[string]int)
+			}
+			continue
+		}
+		if section == "" && !skip {
+			add(num, "", LintError, "line '%s' is not part of a section", line)
+			skip = true
+		}
+		if skip || section == INISectionVersion || section == INISectionReminder {
+			continue
+		}
+		line = system.StripComment(line, `\s#[^#]|"\s#[^#]`)
+		key := lintLine(num, section, line, add)
+		if key == "" {
+			continue
+		}
+		if first, ok := defined[header][key]; ok {
+			add(num, section, LintError, "parameter '%s' already defined in line %d", key, first)
+			continue
+		}
+		defined[header][key] = num
+	}
+	return findings
+}
+
+// lintSectionHeader checks the section definition line including the
+// section tags. It returns the section name and if the lines of the
+// section should be skipped
+func lintSectionHeader(num int, line string, add func(int, string, string, string, ...interface{})) (string, bool) {
+	if !strings.HasSuffix(line, "]") {
+		add(num, "", LintError, "missing ']' at the end of the section definition '%s'", line)
+		return "", true
+	}
+	fields := strings.Split(line[1:len(line)-1], ":")
+	section := fields[0]
+	if section == "" {
+		add(num, "", LintError, "empty section definition '%s', the section will be skipped", line)
+		return "", true
+	}
+	if !lintSections[section] {
+		add(num, section, LintError, "unknown section '%s', the section will be skipped", section)
+		return section, true
+	}
+	for _, tag := range fields[1:] {
+		if tag == "" {
+			continue
+		}
+		tagField := strings.Split(tag, "=")
+		if len(tagField) != 2 || tagField[0] == "" {
+			add(num, section, LintError, "wrong syntax of section tag '%s', the section will be skipped", tag)
+			return section, true
+		}
+		switch tagField[0] {
+		case "os":
+			if !lintOsTag.MatchString(tagField[1]) {
+				add(num, section, LintError, "unsupported os version '%s' in section tag", tagField[1])
+			}
+		case "arch", "csp":
+			if !system.IsInList(tagField[1], lintTagValues[tagField[0]]) {
+				add(num, section, LintError, "unsupported value '%s' of section tag '%s', supported are '%s'", tagField[1], tagField[0], strings.Join(lintTagValues[tagField[0]], "', '"))
+			}
+		case "blkvendor", "blkmodel", "blkpat", "vendor", "model":
+			if tagField[1] == "" {
+				add(num, section, LintError, "empty value of section tag '%s'", tagField[0])
+			}
+		default:
+			add(num, section, LintWarning, "section tag '%s' is not a saptune tag, it is checked against '/sys/class/dmi/id/%s'", tagField[0], tagField[0])
+		}
+	}
+	return section, false
+}
+
+// lintLine checks a parameter line of a section and returns the parameter
+// name for the duplicate check
+func lintLine(num int, section, line string, add func(int, string, string, string, ...interface{})) string {
+	if section == INISectionRpm {
+		fields := strings.Fields(line)
+		switch len(fields) {
+		case 2:
+			return fields[0]
+		case 3:
+			// old syntax with os version
+			if fields[1] != "all" && !lintOsTag.MatchString(fields[1]) {
+				add(num, section, LintWarning, "unknown os version '%s' for package '%s', the line will never be used", fields[1], fields[0])
+			}
+			return fields[0] + " " + fields[1]
+		}
+		add(num, section, LintError, "wrong syntax '%s', expected 'package package-version'", line)
+		return ""
+	}
+	if section == INISectionSeverity {
+		kov := txtparser.RegexSeverity.FindStringSubmatch(line)
+		if kov == nil {
+			add(num, section, LintError, "wrong syntax '%s', expected 'parameter = severity'", line)
+			return ""
+		}
+		if !IsSeverity(kov[3]) {
+			add(num, section, LintError, "wrong severity '%s' for '%s', supported are '%s'", kov[3], kov[1], strings.Join(Severities, "', '"))
+		}
+		return kov[1]
+	}
+	kov := txtparser.RegexKeyOperatorValue.FindStringSubmatch(line)
+	param := strings.Fields(strings.Split(line, "=")[0])
+	if len(param) != 0 && strings.Contains(param[0], "/") {
+		add(num, section, LintError, "unsupported parameter syntax '%s', the parameter name must not contain '/'", line)
+		return ""
+	}
+	if len(kov) != 4 || !strings.HasPrefix(line, kov[1]) {
+		if section == INISectionGrub && lintKeyName.MatchString(line) {
+			// single boot option without value
+			return line
+		}
+		add(num, section, LintError, "wrong syntax '%s', expected 'parameter = value'", line)
+		return ""
+	}
+	key, op, value := kov[1], kov[2], kov[3]
+	if section == INISectionLimits {
+		// the name of the limits parameter is not case sensitive
+		key = strings.ToUpper(key)
+	}
+	if !system.IsInList(op, lintOperators) {
+		add(num, section, LintError, "unknown operator '%s' for parameter '%s'", op, key)
+	} else if op != txtparser.OperatorEqual && section != INISectionSysctl && section != INISectionSys {
+		add(num, section, LintError, "operator '%s' for parameter '%s' is only supported in the sections [sysctl] and [sys]", op, key)
+	}
+	if keys, ok := lintKeys[section]; ok && !system.IsInList(key, keys) {
+		add(num, section, LintError, "unknown parameter '%s', supported are '%s'", key, strings.Join(keys, "', '"))
+		return key
+	}
+	if value == "" {
+		// empty value - parameter is left untouched
+		return key
+	}
+	if IsTemplate(value) {
+		// the value is calculated on the running system
+		if err := CheckTemplate(value); err != nil {
+			add(num, section, LintError, "%v", err)
+		}
+		return key
+	}
+	for _, msg := range lintValue(section, key, value) {
+		add(num, section, LintError, "%s", msg)
+	}
+	return key
+}
+
+// lintValue checks the value of a parameter and returns the problems found
+func lintValue(section, key, value string) []string {
+	msgs := []string{}
+	if choices, ok := lintChoices[key]; ok {
+		if !system.IsInList(strings.ToLower(value), choices) {
+			msgs = append(msgs, fmt.Sprintf("wrong value '%s' for '%s', supported are '%s'", value, key, strings.Join(choices, "', '")))
+		}
+		return msgs
+	}
+	switch section {
+	case INISectionCPU:
+		if key == "force_latency" && !lintNumber.MatchString(value) {
+			msgs = append(msgs, fmt.Sprintf("value '%s' for '%s' is not a number", value, key))
+		}
+	case INISectionMEM:
+		val, err := strconv.Atoi(value)
+		if err != nil || val < 0 {
+			msgs = append(msgs, fmt.Sprintf("value '%s' for '%s' is not a positive number", value, key))
+		} else if key == "VSZ_TMPFS_PERCENT" && val > 100 {
+			msgs = append(msgs, fmt.Sprintf("value '%s' for '%s' exceeds 100 percent", value, key))
+		}
+	case INISectionLogin:
+		if value != "infinity" && !lintNumber.MatchString(value) {
+			msgs = append(msgs, fmt.Sprintf("wrong value '%s' for '%s', expected a number or 'infinity'", value, key))
+		}
+	case INISectionPagecache, INISectionBlock:
+		if key != "IO_SCHEDULER" && !lintNumber.MatchString(value) {
+			msgs = append(msgs, fmt.Sprintf("value '%s' for '%s' is not a number", value, key))
+		}
+	case INISectionFS:
+		for _, opt := range strings.Split(value, ",") {
+			if !lintKeyName.MatchString(strings.TrimLeft(strings.TrimSpace(opt), "+-")) {
+				msgs = append(msgs, fmt.Sprintf("wrong xfs option '%s'", strings.TrimSpace(opt)))
+			}
+		}
+	case INISectionLimits:
+		msgs = append(msgs, lintLimits(value)...)
+	case INISectionService:
+		msgs = append(msgs, lintServiceStates(key, value)...)
+	}
+	return msgs
+}
+
+// lintLimits checks the limits entries 'domain type item value'
+func lintLimits(value string) []string {
+	msgs := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		lim := strings.Fields(entry)
+		if len(lim) != 4 {
+			msgs = append(msgs, fmt.Sprintf("wrong limits entry '%s', expected 'domain type item value'", strings.TrimSpace(entry)))
+			continue
+		}
+		if lim[1] != "soft" && lim[1] != "hard" && lim[1] != "-" {
+			msgs = append(msgs, fmt.Sprintf("wrong type '%s' in limits entry '%s', supported are 'soft', 'hard' and '-'", lim[1], strings.TrimSpace(entry)))
+		}
+		if !system.IsInList(lim[2], lintLimitItems) {
+			msgs = append(msgs, fmt.Sprintf("unknown item '%s' in limits entry '%s'", lim[2], strings.TrimSpace(entry)))
+		}
+		if lim[3] != "unlimited" && lim[3] != "infinity" && !lintNumber.MatchString(lim[3]) {
+			msgs = append(msgs, fmt.Sprintf("wrong value '%s' in limits entry '%s'", lim[3], strings.TrimSpace(entry)))
+		}
+	}
+	return msgs
+}
+
+// lintServiceStates checks the service states 'start', 'stop', 'enable'
+// and 'disable'
+func lintServiceStates(service, value string) []string {
+	msgs := []string{}
+	ssState := ""
+	edState := ""
+	for _, state := range strings.Split(value, ",") {
+		sval := strings.ToLower(strings.TrimSpace(state))
+		switch sval {
+		case "start", "stop":
+			if ssState != "" {
+				msgs = append(msgs, fmt.Sprintf("multiple start/stop entries for '%s', only '%s' is used", service, ssState))
+				continue
+			}
+			ssState = sval
+			if strings.TrimSuffix(service, ".socket") == "uuidd" && sval != "start" {
+				msgs = append(msgs, fmt.Sprintf("only 'start' is supported for '%s'", service))
+			}
+		case "enable", "disable":
+			if edState != "" {
+				msgs = append(msgs, fmt.Sprintf("multiple enable/disable entries for '%s', only '%s' is used", service, edState))
+				continue
+			}
+			edState = sval
+		default:
+			msgs = append(msgs, fmt.Sprintf("wrong service state '%s' for '%s', supported are 'start', 'stop', 'enable' and 'disable'", sval, service))
+		}
+	}
+	return msgs
+}
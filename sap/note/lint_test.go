@@ -0,0 +1,122 @@
+package note
+
+import (
+	"path"
+	"testing"
+)
+
+var lintContent = `# lint test
+[version]
+VERSION=1
+DATE=01.01.2026
+DESCRIPTION=lint test
+REFERENCES=none
+
+[sysctl]
+vm.swappiness <= 10
+kernel.shmmni = 32768
+kernel.shmmni = 4096
+
+[vm]
+THP=sometimes
+KSM=1
+MAGIC=1
+
+[cpu]
+energy_perf_bias < performance
+force_latency=low
+
+[mem]
+VSZ_TMPFS_PERCENT=120
+
+[block:os=15-*:arch=sparc]
+IO_SCHEDULER=none
+
+[service]
+uuidd.socket=stop
+sysstat.service=start, restart
+
+[limits]
+LIMITS=@sapsys soft nofile 1048576, @sapsys weak nofile 1048576, @sapsys hard nothing many
+
+[grub]
+quiet
+numa_balancing=disable
+
+[rpm]
+glibc 2.22-51.6
+glibc
+
+[unknown]
+this is skipped
+
+[sys:os]
+kernel.mm.ksm.run = 1
+
+[sysctl:csp=azure]
+kernel.shmmni = 4096
+vm/dirty_ratio = 10
+
+[severity]
+kernel.numa_balancing = critical
+grub:numa_balancing = urgent
+vm.swappiness critical
+`
+
+func TestLintNote(t *testing.T) {
+	expected := []LintFinding{
+		{Line: 11, Section: "sysctl", Severity: LintError, Message: "parameter 'kernel.shmmni' already defined in line 10"},
+		{Line: 14, Section: "vm", Severity: LintError, Message: "wrong value 'sometimes' for 'THP', supported are 'always', 'madvise', 'never'"},
+		{Line: 16, Section: "vm", Severity: LintError, Message: "unknown parameter 'MAGIC', supported are 'THP', 'KSM'"},
+		{Line: 19, Section: "cpu", Severity: LintError, Message: "operator '<' for parameter 'energy_perf_bias' is only supported in the sections [sysctl] and [sys]"},
+		{Line: 20, Section: "cpu", Severity: LintError, Message: "value 'low' for 'force_latency' is not a number"},
+		{Line: 23, Section: "mem", Severity: LintError, Message: "value '120' for 'VSZ_TMPFS_PERCENT' exceeds 100 percent"},
+		{Line: 25, Section: "block", Severity: LintError, Message: "unsupported value 'sparc' of section tag 'arch', supported are 'x86_64', 'ppc64le', 'aarch64', 's390x'"},
+		{Line: 29, Section: "service", Severity: LintError, Message: "only 'start' is supported for 'uuidd.socket'"},
+		{Line: 30, Section: "service", Severity: LintError, Message: "wrong service state 'restart' for 'sysstat.service', supported are 'start', 'stop', 'enable' and 'disable'"},
+		{Line: 33, Section: "limits", Severity: LintError, Message: "wrong type 'weak' in limits entry '@sapsys weak nofile 1048576', supported are 'soft', 'hard' and '-'"},
+		{Line: 33, Section: "limits", Severity: LintError, Message: "unknown item 'nothing' in limits entry '@sapsys hard nothing many'"},
+		{Line: 33, Section: "limits", Severity: LintError, Message: "wrong value 'many' in limits entry '@sapsys hard nothing many'"},
+		{Line: 41, Section: "rpm", Severity: LintError, Message: "wrong syntax 'glibc', expected 'package package-version'"},
+		{Line: 43, Section: "unknown", Severity: LintError, Message: "unknown section 'unknown', the section will be skipped"},
+		{Line: 46, Section: "sys", Severity: LintError, Message: "wrong syntax of section tag 'os', the section will be skipped"},
+		{Line: 51, Section: "sysctl", Severity: LintError, Message: "unsupported parameter syntax 'vm/dirty_ratio = 10', the parameter name must not contain '/'"},
+		{Line: 55, Section: "severity", Severity: LintError, Message: "wrong severity 'urgent' for 'grub:numa_balancing', supported are 'critical', 'recommended', 'informational'"},
+		{Line: 56, Section: "severity", Severity: LintError, Message: "wrong syntax 'vm.swappiness critical', expected 'parameter = severity'"},
+	}
+	findings := LintNote(lintContent)
+	if len(findings) != len(expected) {
+		t.Errorf("expected %d findings, got %d", len(expected), len(findings))
+	}
+	for i, finding := range findings {
+		if i >= len(expected) || finding != expected[i] {
+			t.Errorf("unexpected finding '%s'", finding)
+		}
+	}
+
+	// version section
+	findings = LintNote("[sysctl]\nvm.swappiness=10\n")
+	if len(findings) != 1 || findings[0].Message != "missing section [version]" || findings[0].String() != "file: error: missing section [version]" {
+		t.Errorf("got '%+v'", findings)
+	}
+	findings = LintNote("vm.swappiness=10\n[version]\nVERSION=1\nDESCRIPTION=test\n")
+	if len(findings) != 2 || findings[0].Message != "missing mandatory field(s) DATE, REFERENCES in section [version]" || findings[1].String() != "line 1: error: line 'vm.swappiness=10' is not part of a section" {
+		t.Errorf("got '%+v'", findings)
+	}
+
+	// shipped Note definition files
+	for _, noteID := range []string{"1410736", "1984787", "2382421"} {
+		findings, err := LintNoteFile(path.Join(OSNotesInGOPATH, noteID))
+		if err != nil {
+			t.Error(err)
+		}
+		for _, finding := range findings {
+			if finding.Severity == LintError {
+				t.Errorf("unexpected finding in Note %s: '%s'", noteID, finding)
+			}
+		}
+	}
+	if _, err := LintNoteFile("/not_avail"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
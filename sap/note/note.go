@@ -19,6 +19,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // SaptuneParameterStateDir defines the directory where to store the
@@ -135,6 +136,25 @@ func GetNoteHeadData(obj Note) (desc, vers, date string, refs []string) {
 	return
 }
 
+// GetNoteDependencies returns the Note IDs declared in the fields REQUIRES,
+// CONFLICTS and SUPERSEDES of the version section of the Note definition
+func GetNoteDependencies(obj Note) (requires, conflicts, supersedes []string) {
+	requires = []string{}
+	conflicts = []string{}
+	supersedes = []string{}
+	value := reflect.ValueOf(obj)
+	if value.Kind() != reflect.Struct || !value.FieldByName("ConfFilePath").IsValid() {
+		return
+	}
+	objConfFile := value.FieldByName("ConfFilePath").String()
+	if objConfFile != "" {
+		requires = txtparser.GetINIFileVersionSectionList(objConfFile, "requires")
+		conflicts = txtparser.GetINIFileVersionSectionList(objConfFile, "conflicts")
+		supersedes = txtparser.GetINIFileVersionSectionList(objConfFile, "supersedes")
+	}
+	return
+}
+
 // FieldComparison records the actual value versus expected value for
 // a note field. The field name has to be the actual name in Go struct.
 type FieldComparison struct {
@@ -225,7 +245,7 @@ func CompareNoteFields(actualNote, expectedNote Note) (allMatch bool, comparison
 					// if this should change in the future use
 					// !strings.Contains(key.String(), "grub")
 					// instead of !isInternalGrub(key.String())
-					if actualValue.(string) != "all:none" && !isInternalGrub(key.String()) && !(system.IsXFSOption.MatchString(key.String()) && actualValue.(string) == "NA") && actualValue.(string) != "PNA" && key.String() != "VSZ_TMPFS_PERCENT" {
+					if affectsCompliance(key.String(), actualValue.(string)) {
 						allMatch = false
 					}
 				}
@@ -245,6 +265,67 @@ func CompareNoteFields(actualNote, expectedNote Note) (allMatch bool, comparison
 	return
 }
 
+// affectsCompliance checks, if a mismatching parameter value influences
+// the compliance of a Note
+func affectsCompliance(key, actualValue string) bool {
+	return actualValue != "all:none" && !isInternalGrub(key) && !(system.IsXFSOption.MatchString(key) && actualValue == "NA") && actualValue != "PNA" && key != "VSZ_TMPFS_PERCENT"
+}
+
+// ComparisonsConform evaluates the compliance of the parameter comparison
+// results of a Note again, e.g. after a part of the parameters is filtered
+// out. The same rules as in CompareNoteFields are used
+func ComparisonsConform(comparisons map[string]FieldComparison) bool {
+	allMatch := true
+	grubAvail := false
+	for _, comparison := range comparisons {
+		if comparison.ReflectFieldName != "SysctlParams" {
+			continue
+		}
+		if strings.Contains(comparison.ReflectMapKey, "grub") {
+			grubAvail = true
+		}
+		if actVal, ok := comparison.ActualValue.(string); ok && !comparison.MatchExpectation && affectsCompliance(comparison.ReflectMapKey, actVal) {
+			allMatch = false
+		}
+	}
+	if allMatch && grubAvail {
+		allMatch = chkGrubCompliance(comparisons, allMatch)
+	}
+	return allMatch
+}
+
+// compliance categories of a parameter used by the verify summary
+const (
+	ComplianceCompliant     = "compliant"
+	ComplianceNonCompliant  = "non-compliant"
+	ComplianceNotApplicable = "not applicable"
+	ComplianceUntouched     = "untouched"
+	ComplianceInformational = "informational"
+)
+
+// ParamCompliance returns the compliance category of a parameter comparison.
+// Parameters not supported by the system are not applicable, parameters
+// left untouched by an override are untouched and deviations, which do not
+// influence the compliance of the Note, are informational.
+// An expired override makes the parameter non-compliant
+func ParamCompliance(comparison FieldComparison, expired bool) string {
+	actVal, _ := comparison.ActualValue.(string)
+	expVal, _ := comparison.ExpectedValue.(string)
+	switch {
+	case actVal == "all:none" || actVal == "PNA":
+		return ComplianceNotApplicable
+	case expVal == "":
+		return ComplianceUntouched
+	case expired:
+		return ComplianceNonCompliant
+	case comparison.MatchExpectation:
+		return ComplianceCompliant
+	case !affectsCompliance(comparison.ReflectMapKey, actVal):
+		return ComplianceInformational
+	}
+	return ComplianceNonCompliant
+}
+
 // isInternalGrub - checks, if a grub setting found in the note definition
 // is a saptune integrated grub parameter or a customer specific parameter
 func isInternalGrub(val string) bool {
@@ -346,3 +427,20 @@ func cmpFieldValue(fNo int, fieldName string, actNote, expNote reflect.Value) Fi
 	}
 	return fieldComparison
 }
+
+// ExpiredOverrides returns the sorted list of the parameters, which are
+// overridden by the override file of the Note and whose override has
+// passed the expiry date of the '@expires' metadata
+func ExpiredOverrides(metas map[string]txtparser.OverrideMeta, comparisons map[string]FieldComparison, now time.Time) []string {
+	expired := []string{}
+	for _, comparison := range comparisons {
+		if comparison.ReflectFieldName != "OverrideParams" {
+			continue
+		}
+		if meta, ok := txtparser.LookupOverrideMeta(metas, comparison.ReflectMapKey); ok && meta.IsExpired(now) {
+			expired = append(expired, comparison.ReflectMapKey)
+		}
+	}
+	sort.Strings(expired)
+	return expired
+}
@@ -2,10 +2,12 @@ package note
 
 import (
 	"encoding/json"
+	"github.com/SUSE/saptune/txtparser"
 	"os"
 	"path"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var OSNotesInGOPATH = path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/ospackage/usr/share/saptune/notes")
@@ -407,3 +409,71 @@ func TestCompareJSValu(t *testing.T) {
 		t.Fatalf("compare '%+v' and '%+v', return '%s' and '%s', match: '%+v'\n", v1i, v2i, r1, r2, match)
 	}
 }
+
+func TestExpiredOverrides(t *testing.T) {
+	metas := map[string]txtparser.OverrideMeta{
+		"vm.swappiness":  {Reason: "test", Expires: "2026-01-31"},
+		"kernel.shmmni":  {Reason: "test", Expires: "2099-01-31"},
+		"kernel.sem":     {Reason: "test"},
+		"vm.dirty_ratio": {Expires: "2026-01-31"},
+	}
+	comparisons := map[string]FieldComparison{
+		"SysctlParams[vm.swappiness]":   {ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.swappiness"},
+		"OverrideParams[vm.swappiness]": {ReflectFieldName: "OverrideParams", ReflectMapKey: "vm.swappiness"},
+		"OverrideParams[kernel.shmmni]": {ReflectFieldName: "OverrideParams", ReflectMapKey: "kernel.shmmni"},
+		"OverrideParams[kernel.sem]":    {ReflectFieldName: "OverrideParams", ReflectMapKey: "kernel.sem"},
+		"SysctlParams[vm.dirty_ratio]":  {ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.dirty_ratio"},
+	}
+	expired := ExpiredOverrides(metas, comparisons, time.Date(2026, 6, 1, 0, 0, 0, 0, time.Local))
+	if !reflect.DeepEqual(expired, []string{"vm.swappiness"}) {
+		t.Errorf("got '%+v'", expired)
+	}
+}
+
+func TestComparisonsConform(t *testing.T) {
+	comparisons := map[string]FieldComparison{
+		"SysctlParams[vm.swappiness]": {ReflectFieldName: "SysctlParams", ReflectMapKey: "vm.swappiness", ActualValue: "60", ExpectedValue: "10", MatchExpectation: false},
+		"SysctlParams[kernel.shmmni]": {ReflectFieldName: "SysctlParams", ReflectMapKey: "kernel.shmmni", ActualValue: "32768", ExpectedValue: "32768", MatchExpectation: true},
+		"SysctlParams[force_latency]": {ReflectFieldName: "SysctlParams", ReflectMapKey: "force_latency", ActualValue: "all:none", ExpectedValue: "70", MatchExpectation: false},
+		"OverrideParams[kernel.sem]":  {ReflectFieldName: "OverrideParams", ReflectMapKey: "kernel.sem", ActualValue: "", ExpectedValue: "1", MatchExpectation: false},
+	}
+	if ComparisonsConform(comparisons) {
+		t.Error("expected non-compliance because of vm.swappiness")
+	}
+	delete(comparisons, "SysctlParams[vm.swappiness]")
+	if !ComparisonsConform(comparisons) {
+		t.Error("expected compliance, not supported parameters and overrides do not count")
+	}
+	// grub parameter with compliant alternative
+	comparisons["SysctlParams[grub:numa_balancing]"] = FieldComparison{ReflectFieldName: "SysctlParams", ReflectMapKey: "grub:numa_balancing", ActualValue: "NA", ExpectedValue: "disable", MatchExpectation: false}
+	comparisons["SysctlParams[kernel.numa_balancing]"] = FieldComparison{ReflectFieldName: "SysctlParams", ReflectMapKey: "kernel.numa_balancing", ActualValue: "0", ExpectedValue: "0", MatchExpectation: true}
+	if !ComparisonsConform(comparisons) {
+		t.Error("expected compliance, kernel.numa_balancing is compliant")
+	}
+	comparisons["SysctlParams[kernel.numa_balancing]"] = FieldComparison{ReflectFieldName: "SysctlParams", ReflectMapKey: "kernel.numa_balancing", ActualValue: "1", ExpectedValue: "0", MatchExpectation: false}
+	if ComparisonsConform(comparisons) {
+		t.Error("expected non-compliance because of kernel.numa_balancing")
+	}
+}
+
+func TestParamCompliance(t *testing.T) {
+	tests := []struct {
+		comparison FieldComparison
+		expired    bool
+		compliance string
+	}{
+		{FieldComparison{ReflectMapKey: "vm.swappiness", ActualValue: "10", ExpectedValue: "10", MatchExpectation: true}, false, ComplianceCompliant},
+		{FieldComparison{ReflectMapKey: "vm.swappiness", ActualValue: "10", ExpectedValue: "10", MatchExpectation: true}, true, ComplianceNonCompliant},
+		{FieldComparison{ReflectMapKey: "vm.swappiness", ActualValue: "60", ExpectedValue: "10", MatchExpectation: false}, false, ComplianceNonCompliant},
+		{FieldComparison{ReflectMapKey: "force_latency", ActualValue: "all:none", ExpectedValue: "70", MatchExpectation: false}, false, ComplianceNotApplicable},
+		{FieldComparison{ReflectMapKey: "vm.dirty_ratio", ActualValue: "PNA", ExpectedValue: "10", MatchExpectation: false}, false, ComplianceNotApplicable},
+		{FieldComparison{ReflectMapKey: "kernel.sem", ActualValue: "1 2 3 4", ExpectedValue: "", MatchExpectation: true}, false, ComplianceUntouched},
+		{FieldComparison{ReflectMapKey: "grub:numa_balancing", ActualValue: "NA", ExpectedValue: "disable", MatchExpectation: false}, false, ComplianceInformational},
+		{FieldComparison{ReflectMapKey: "VSZ_TMPFS_PERCENT", ActualValue: "50", ExpectedValue: "75", MatchExpectation: false}, false, ComplianceInformational},
+	}
+	for _, tt := range tests {
+		if compliance := ParamCompliance(tt.comparison, tt.expired); compliance != tt.compliance {
+			t.Errorf("'%s': got '%s', expected '%s'", tt.comparison.ReflectMapKey, compliance, tt.compliance)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package note
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScaffoldSection is a section of a generated Note definition file
+type ScaffoldSection struct {
+	Header string   // section definition line like '[sysctl]'
+	Lines  []string // parameter and comment lines of the section
+}
+
+// scaffoldSysSections are the sections supported by ScaffoldFromSystem
+var scaffoldSysSections = []string{INISectionSysctl, INISectionSys, INISectionVM, INISectionBlock}
+
+// ScaffoldFromSystem reads the current system values of the parameters
+// selected by 'spec' and returns them as Note sections.
+// 'spec' is a comma separated list of parameter name patterns (shell
+// glob syntax). A pattern can be prefixed by a section name
+// ('sysctl:vm.*,net.core.*,vm:THP'), which is valid for all following
+// patterns. Without a section prefix 'sysctl' is used.
+func ScaffoldFromSystem(spec string) ([]ScaffoldSection, error) {
+	patterns := make(map[string][]string)
+	order := []string{}
+	section := INISectionSysctl
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if fields := strings.SplitN(entry, ":", 2); len(fields) == 2 {
+			section = fields[0]
+			entry = fields[1]
+		}
+		if !system.IsInList(section, scaffoldSysSections) {
+			return nil, fmt.Errorf("section '%s' is not supported, supported are '%s'", section, strings.Join(scaffoldSysSections, "', '"))
+		}
+		if entry == "" {
+			return nil, fmt.Errorf("missing parameter pattern for section '%s' in '%s'", section, spec)
+		}
+		if _, err := path.Match(entry, ""); err != nil {
+			return nil, fmt.Errorf("wrong parameter pattern '%s' - %v", entry, err)
+		}
+		if _, ok := patterns[section]; !ok {
+			order = append(order, section)
+		}
+		patterns[section] = append(patterns[section], entry)
+	}
+
+	sections := []ScaffoldSection{}
+	for _, section := range order {
+		lines := []string{}
+		switch section {
+		case INISectionSysctl:
+			lines = scaffoldSysctl(patterns[section])
+		case INISectionSys:
+			lines = scaffoldSys(patterns[section])
+		case INISectionVM:
+			for _, key := range matchPatterns(lintKeys[INISectionVM], patterns[section]) {
+				val, _ := GetVMVal(key)
+				lines = append(lines, fmt.Sprintf("%s=%s", key, val))
+			}
+		case INISectionBlock:
+			lines = scaffoldBlock(patterns[section])
+		}
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("no parameter of section '%s' matches '%s'", section, strings.Join(patterns[section], ","))
+		}
+		sections = append(sections, ScaffoldSection{Header: fmt.Sprintf("[%s]", section), Lines: lines})
+	}
+	return sections, nil
+}
+
+// scaffoldSysctl returns the current values of the matching sysctl
+// parameters. Parameters with multi-line values are skipped
+func scaffoldSysctl(patterns []string) []string {
+	lines := []string{}
+	for _, key := range matchPatterns(system.GetSysctlKeys(), patterns) {
+		val, err := system.GetSysctlString(key)
+		if err != nil || strings.Contains(val, "\n") {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s = %s", key, strings.Join(strings.Fields(val), " ")))
+	}
+	return lines
+}
+
+// scaffoldSys returns the current values of the matching /sys parameters.
+// The patterns are resolved as path below /sys
+func scaffoldSys(patterns []string) []string {
+	lines := []string{}
+	for _, pattern := range patterns {
+		files, _ := filepath.Glob(path.Join("/sys", strings.Replace(pattern, ".", "/", -1)))
+		for _, file := range files {
+			key := strings.Replace(strings.TrimPrefix(file, "/sys/"), "/", ".", -1)
+			val, _ := GetSysVal(key)
+			if val == "PNA" || strings.Contains(val, "\n") {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s = %s", key, val))
+		}
+	}
+	return lines
+}
+
+// scaffoldBlock returns the current values of the matching block device
+// parameters. As the [block] section sets the values for all block devices,
+// the most common value of all block devices is used
+func scaffoldBlock(patterns []string) []string {
+	lines := []string{}
+	keys := matchPatterns(lintKeys[INISectionBlock], patterns)
+	if len(keys) == 0 {
+		return lines
+	}
+	bdevs := system.CollectBlockDeviceInfo()
+	bdevConf, err := system.GetBlockDeviceInfo()
+	if err != nil || len(bdevs) == 0 {
+		system.WarningLog("no block device information available")
+		return lines
+	}
+	for _, key := range keys {
+		count := make(map[string]int)
+		for _, bdev := range bdevs {
+			if val := bdevConf.BlockAttributes[bdev][key]; val != "" && val != "PNA" {
+				count[val]++
+			}
+		}
+		if len(count) == 0 {
+			continue
+		}
+		values := make([]string, 0, len(count))
+		for val := range count {
+			values = append(values, val)
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if count[values[i]] != count[values[j]] {
+				return count[values[i]] > count[values[j]]
+			}
+			return values[i] < values[j]
+		})
+		if len(values) > 1 {
+			system.NoticeLog("the block devices use different values for '%s' (%s), using the most common value '%s'", key, strings.Join(values, ", "), values[0])
+			lines = append(lines, fmt.Sprintf("# values found on the block devices: %s", strings.Join(values, ", ")))
+		}
+		lines = append(lines, fmt.Sprintf("%s=%s", key, values[0]))
+	}
+	return lines
+}
+
+// ScaffoldFromNote returns the sections of an existing Note definition file
+// without the [version] section. If 'sections' is not empty, only the
+// named sections are returned. Section tags and comments are kept
+func ScaffoldFromNote(fileName string, sections []string) ([]ScaffoldSection, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	result := []ScaffoldSection{}
+	var current *ScaffoldSection
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, " \t")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			name := strings.Split(strings.Trim(trimmed, "[]"), ":")[0]
+			current = nil
+			if name == INISectionVersion || (len(sections) != 0 && !system.IsInList(name, sections)) {
+				continue
+			}
+			result = append(result, ScaffoldSection{Header: trimmed})
+			current = &result[len(result)-1]
+			continue
+		}
+		if current != nil && trimmed != "" {
+			current.Lines = append(current.Lines, line)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no matching section found in '%s'", fileName)
+	}
+	return result, nil
+}
+
+// ScaffoldNote returns the content of a new Note definition file with a
+// valid [version] section followed by the given sections
+func ScaffoldNote(noteID, description string, sections []ScaffoldSection) string {
+	content := []string{
+		fmt.Sprintf("# %s - %s", noteID, description),
+		"",
+		"[version]",
+		"VERSION=1",
+		fmt.Sprintf("DATE=%s", time.Now().Format("02.01.2006")),
+		fmt.Sprintf("DESCRIPTION=%s", description),
+		"REFERENCES=",
+	}
+	for _, section := range sections {
+		content = append(content, "", section.Header)
+		content = append(content, section.Lines...)
+	}
+	return strings.Join(content, "\n") + "\n"
+}
+
+// matchPatterns returns the names matching at least one of the patterns
+func matchPatterns(names, patterns []string) []string {
+	matches := []string{}
+	for _, name := range names {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				matches = append(matches, name)
+				break
+			}
+		}
+	}
+	return matches
+}
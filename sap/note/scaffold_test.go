@@ -0,0 +1,80 @@
+package note
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestScaffoldFromSystem(t *testing.T) {
+	sections, err := ScaffoldFromSystem("sysctl:vm.swappiness,kernel.shmmn?,vm:THP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 2 || sections[0].Header != "[sysctl]" || sections[1].Header != "[vm]" {
+		t.Fatalf("unexpected sections '%+v'", sections)
+	}
+	if len(sections[0].Lines) != 2 || !strings.HasPrefix(sections[0].Lines[0], "kernel.shmmni = ") || !strings.HasPrefix(sections[0].Lines[1], "vm.swappiness = ") {
+		t.Errorf("unexpected sysctl lines '%+v'", sections[0].Lines)
+	}
+	if len(sections[1].Lines) != 1 || !strings.HasPrefix(sections[1].Lines[0], "THP=") {
+		t.Errorf("unexpected vm lines '%+v'", sections[1].Lines)
+	}
+
+	// read-only sysctl entries can not be tuned
+	sections, err = ScaffoldFromSystem("sysctl:kernel.random.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range sections[0].Lines {
+		key := strings.TrimSpace(strings.Split(line, "=")[0])
+		if info, err := os.Stat(path.Join("/proc/sys", strings.Replace(key, ".", "/", -1))); err != nil || info.Mode().Perm()&0200 == 0 {
+			t.Errorf("read-only sysctl entry '%s' in the scaffold", line)
+		}
+	}
+
+	for _, spec := range []string{"grub:quiet", "sysctl:", "vm.[", "vm.not_avail_*", "fs.file-nr"} {
+		if _, err := ScaffoldFromSystem(spec); err == nil {
+			t.Errorf("expected an error for '%s'", spec)
+		}
+	}
+}
+
+func TestScaffoldFromNote(t *testing.T) {
+	fileName := path.Join(OSNotesInGOPATH, "1984787")
+	sections, err := ScaffoldFromNote(fileName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, section := range sections {
+		if section.Header == "[version]" {
+			t.Error("section [version] should not be copied")
+		}
+	}
+	sections, err = ScaffoldFromNote(fileName, []string{"sysctl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, section := range sections {
+		if !strings.HasPrefix(section.Header, "[sysctl") {
+			t.Errorf("unexpected section '%s'", section.Header)
+		}
+	}
+	if _, err := ScaffoldFromNote(fileName, []string{"unknown"}); err == nil {
+		t.Error("expected an error for a missing section")
+	}
+	if _, err := ScaffoldFromNote("/not_avail", nil); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	content := ScaffoldNote("scaffold", "scaffold test", append(sections, ScaffoldSection{Header: "[vm]", Lines: []string{"THP=never"}}))
+	if !strings.HasPrefix(content, "# scaffold - scaffold test\n\n[version]\nVERSION=1\n") || !strings.HasSuffix(content, "\n[vm]\nTHP=never\n") {
+		t.Errorf("unexpected content '%s'", content)
+	}
+	for _, finding := range LintNote(content) {
+		if finding.Severity == LintError {
+			t.Errorf("unexpected finding '%s'", finding)
+		}
+	}
+}
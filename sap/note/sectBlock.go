@@ -11,6 +11,17 @@ import (
 
 // section [block]
 
+// BlockDeviceOfParam returns the block device of a parameter of the section
+// [block] (e.g. 'sda' for 'IO_SCHEDULER_sda') or an empty string
+func BlockDeviceOfParam(key string) string {
+	for _, blkParam := range lintKeys[INISectionBlock] {
+		if strings.HasPrefix(key, blkParam+"_") {
+			return strings.TrimPrefix(key, blkParam+"_")
+		}
+	}
+	return ""
+}
+
 // GetBlkVal initialise the block device structure with the current
 // system settings
 func GetBlkVal(key string, cur *param.BlockDeviceQueue) (string, string, error) {
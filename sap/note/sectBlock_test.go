@@ -122,3 +122,11 @@ func TestChkMaxHWsector(t *testing.T) {
 		t.Errorf("expected info as 'limited', but got '%s' - '%+v' - '%+v'\n", info, ival, sval)
 	}
 }
+
+func TestBlockDeviceOfParam(t *testing.T) {
+	for key, bdev := range map[string]string{"IO_SCHEDULER_sda": "sda", "NRREQ_dm-0": "dm-0", "READ_AHEAD_KB_nvme0n1": "nvme0n1", "MAX_SECTORS_KB_vdb": "vdb", "vm.swappiness": "", "IO_SCHEDULER": ""} {
+		if val := BlockDeviceOfParam(key); val != bdev {
+			t.Errorf("'%s': expected '%s', got '%s'", key, bdev, val)
+		}
+	}
+}
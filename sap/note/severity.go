@@ -0,0 +1,70 @@
+package note
+
+import "fmt"
+
+// the parameters of a Note can be classified in the section [severity]
+// of the Note definition file, e.g.
+// [severity]
+// kernel.numa_balancing = critical
+// READ_AHEAD_KB = informational
+// The parameter names are the names shown in the verify table, block
+// device parameters are used without the device name. Parameters without
+// a severity are 'recommended'
+
+// severity of a Note parameter
+const (
+	SeverityCritical      = "critical"
+	SeverityRecommended   = "recommended"
+	SeverityInformational = "informational"
+)
+
+// Severities are the supported severities, ordered from the highest to the
+// lowest
+var Severities = []string{SeverityCritical, SeverityRecommended, SeverityInformational}
+
+// IsSeverity checks, if the severity is supported
+func IsSeverity(severity string) bool {
+	return severityRank(severity) >= 0
+}
+
+// severityRank returns the position of the severity in Severities or -1
+func severityRank(severity string) int {
+	for rank, sev := range Severities {
+		if sev == severity {
+			return rank
+		}
+	}
+	return -1
+}
+
+// SeverityReaches checks, if the severity is at least as high as the
+// given threshold
+func SeverityReaches(severity, threshold string) bool {
+	return IsSeverity(severity) && severityRank(severity) <= severityRank(threshold)
+}
+
+// paramSeverity returns the severity of a parameter from the severities of
+// the section [severity] or an empty string, if the parameter is not
+// classified. Block device parameters are found by their name without the
+// device name
+func paramSeverity(severities map[string]string, key string) string {
+	severity, ok := severities[key]
+	if !ok {
+		if bdev := BlockDeviceOfParam(key); bdev != "" {
+			severity, ok = severities[key[:len(key)-len(bdev)-1]]
+		}
+	}
+	if !ok || !IsSeverity(severity) {
+		return ""
+	}
+	return severity
+}
+
+// ParamSeverity returns the severity of a parameter from the comparison
+// results of a Note. Parameters without severity are 'recommended'
+func ParamSeverity(comparisons map[string]FieldComparison, key string) string {
+	if sev, ok := comparisons[fmt.Sprintf("%s[%s]", "Severities", key)].ExpectedValue.(string); ok && sev != "" {
+		return sev
+	}
+	return SeverityRecommended
+}
@@ -0,0 +1,49 @@
+package note
+
+import (
+	"github.com/SUSE/saptune/txtparser"
+	"testing"
+)
+
+func TestSeverityReaches(t *testing.T) {
+	tests := []struct {
+		severity  string
+		threshold string
+		reaches   bool
+	}{
+		{SeverityCritical, SeverityCritical, true},
+		{SeverityRecommended, SeverityCritical, false},
+		{SeverityCritical, SeverityRecommended, true},
+		{SeverityRecommended, SeverityRecommended, true},
+		{SeverityInformational, SeverityRecommended, false},
+		{SeverityInformational, SeverityInformational, true},
+		{"urgent", SeverityInformational, false},
+	}
+	for _, tt := range tests {
+		if SeverityReaches(tt.severity, tt.threshold) != tt.reaches {
+			t.Errorf("'%s' - '%s': expected '%v'", tt.severity, tt.threshold, tt.reaches)
+		}
+	}
+	if IsSeverity("urgent") || !IsSeverity(SeverityInformational) {
+		t.Error("wrong result of IsSeverity")
+	}
+}
+
+func TestParamSeverity(t *testing.T) {
+	ini := txtparser.ParseINI("[sysctl]\nkernel.numa_balancing = 0\n\n[severity]\nkernel.numa_balancing = critical\ngrub:numa_balancing = critical\nREAD_AHEAD_KB = informational\nvm.swappiness = urgent\n")
+	for key, exp := range map[string]string{"kernel.numa_balancing": SeverityCritical, "grub:numa_balancing": SeverityCritical, "READ_AHEAD_KB_sda": SeverityInformational, "NRREQ_sda": "", "vm.swappiness": "", "vm.dirty_ratio": ""} {
+		if sev := paramSeverity(ini.Severities, key); sev != exp {
+			t.Errorf("'%s': got '%s', expected '%s'", key, sev, exp)
+		}
+	}
+
+	comparisons := map[string]FieldComparison{
+		"Severities[kernel.numa_balancing]": {ReflectFieldName: "Severities", ReflectMapKey: "kernel.numa_balancing", ActualValue: SeverityCritical, ExpectedValue: SeverityCritical, MatchExpectation: true},
+	}
+	if sev := ParamSeverity(comparisons, "kernel.numa_balancing"); sev != SeverityCritical {
+		t.Errorf("got '%s', expected '%s'", sev, SeverityCritical)
+	}
+	if sev := ParamSeverity(comparisons, "vm.swappiness"); sev != SeverityRecommended {
+		t.Errorf("got '%s', expected '%s'", sev, SeverityRecommended)
+	}
+}
@@ -0,0 +1,245 @@
+package note
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"math"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// section values can contain template expressions, which are calculated
+// from facts of the running system, e.g.
+// vm.min_free_kbytes = {{ mem_total_kb * 0.01 | max 1048576 }}
+// The expression supports the operators '+', '-', '*', '/', '%' and
+// parentheses. The result can be passed to functions by '|'.
+
+// regTemplate matches a template expression inside a parameter value
+var regTemplate = regexp.MustCompile(`\{\{(.*?)\}\}`)
+
+// templateFactNames are the supported facts of the running system
+var templateFactNames = []string{"mem_total_kb", "mem_total_mb", "mem_total_bytes", "swap_total_kb", "cpu_count", "numa_nodes", "page_size", "os_version", "os_major"}
+
+// templateFuncs are the supported functions of the template pipeline.
+// 'max N' limits the value to at most N, 'min N' to at least N
+var templateFuncs = map[string]func(val float64, arg []float64) float64{
+	"max":   func(val float64, arg []float64) float64 { return math.Min(val, arg[0]) },
+	"min":   func(val float64, arg []float64) float64 { return math.Max(val, arg[0]) },
+	"round": func(val float64, arg []float64) float64 { return math.Round(val) },
+	"floor": func(val float64, arg []float64) float64 { return math.Floor(val) },
+	"ceil":  func(val float64, arg []float64) float64 { return math.Ceil(val) },
+}
+
+// templateFuncArgs are the number of arguments of the template functions
+var templateFuncArgs = map[string]int{"max": 1, "min": 1, "round": 0, "floor": 0, "ceil": 0}
+
+// templateFacts returns the facts of the running system, which can be
+// used in template expressions
+var templateFacts = func() map[string]string {
+	mem := system.ParseMeminfo()
+	osVers := system.GetOsVers()
+	return map[string]string{
+		"mem_total_kb":    strconv.FormatUint(mem[system.MemMainTotalKey], 10),
+		"mem_total_mb":    strconv.FormatUint(mem[system.MemMainTotalKey]/1024, 10),
+		"mem_total_bytes": strconv.FormatUint(mem[system.MemMainTotalKey]*1024, 10),
+		"swap_total_kb":   strconv.FormatUint(mem[system.MemSwapTotalKey], 10),
+		"cpu_count":       strconv.Itoa(runtime.NumCPU()),
+		"numa_nodes":      strconv.Itoa(system.GetNumaNodes()),
+		"page_size":       strconv.Itoa(os.Getpagesize()),
+		"os_version":      osVers,
+		"os_major":        strings.Split(osVers, "-")[0],
+	}
+}
+
+// IsTemplate checks, if a parameter value contains a template expression
+func IsTemplate(value string) bool {
+	return regTemplate.MatchString(value)
+}
+
+// ResolveTemplate replaces all template expressions of a parameter value
+// by the values calculated from the facts of the running system
+func ResolveTemplate(value string) (string, error) {
+	return resolveTemplate(value, templateFacts())
+}
+
+// CheckTemplate checks the syntax of the template expressions of a
+// parameter value without the need of the facts of the running system
+func CheckTemplate(value string) error {
+	facts := make(map[string]string)
+	for _, name := range templateFactNames {
+		facts[name] = "1"
+	}
+	facts["os_version"] = "15-SP6"
+	_, err := resolveTemplate(value, facts)
+	return err
+}
+
+// resolveTemplate replaces all template expressions of a parameter value
+// by the values calculated from the given facts
+func resolveTemplate(value string, facts map[string]string) (string, error) {
+	var tmplErr error
+	result := regTemplate.ReplaceAllStringFunc(value, func(tmpl string) string {
+		expr := strings.TrimSpace(regTemplate.FindStringSubmatch(tmpl)[1])
+		val, err := evalTemplate(expr, facts)
+		if err != nil && tmplErr == nil {
+			tmplErr = fmt.Errorf("wrong template expression '%s' - %v", tmpl, err)
+		}
+		return val
+	})
+	if tmplErr != nil {
+		return value, tmplErr
+	}
+	return result, nil
+}
+
+// evalTemplate evaluates a single template expression including the
+// function pipeline. Numerical results are truncated to an integer, use
+// 'round' or 'ceil' for a different rounding
+func evalTemplate(expr string, facts map[string]string) (string, error) {
+	stages := strings.Split(expr, "|")
+	first := strings.TrimSpace(stages[0])
+	if val, ok := facts[first]; ok && len(stages) == 1 {
+		// single fact, may be a string like 'os_version'
+		return val, nil
+	}
+	val, err := evalTemplateExpr(first, facts)
+	if err != nil {
+		return "", err
+	}
+	for _, stage := range stages[1:] {
+		fields := strings.Fields(stage)
+		if len(fields) == 0 {
+			return "", fmt.Errorf("missing function after '|'")
+		}
+		fn, ok := templateFuncs[fields[0]]
+		if !ok {
+			return "", fmt.Errorf("unknown function '%s'", fields[0])
+		}
+		args := []float64{}
+		if argExpr := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(stage), fields[0])); argExpr != "" {
+			arg, err := evalTemplateExpr(argExpr, facts)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, arg)
+		}
+		if len(args) != templateFuncArgs[fields[0]] {
+			return "", fmt.Errorf("function '%s' needs %d argument(s)", fields[0], templateFuncArgs[fields[0]])
+		}
+		val = fn(val, args)
+	}
+	return strconv.FormatInt(int64(val), 10), nil
+}
+
+// templateParser is a recursive descent parser for the arithmetic
+// expressions of the templates
+type templateParser struct {
+	tokens []string
+	pos    int
+	facts  map[string]string
+}
+
+// regTemplateToken splits an expression into numbers, names, operators and
+// parentheses
+var regTemplateToken = regexp.MustCompile(`\s*([0-9]+(?:\.[0-9]+)?|[A-Za-z_][A-Za-z0-9_]*|[-+*/%()]|\S)`)
+
+// evalTemplateExpr evaluates an arithmetic expression
+func evalTemplateExpr(expr string, facts map[string]string) (float64, error) {
+	tp := &templateParser{facts: facts}
+	for _, match := range regTemplateToken.FindAllStringSubmatch(expr, -1) {
+		tp.tokens = append(tp.tokens, match[1])
+	}
+	if len(tp.tokens) == 0 {
+		return 0, fmt.Errorf("empty expression")
+	}
+	val, err := tp.parseSum()
+	if err == nil && tp.pos < len(tp.tokens) {
+		err = fmt.Errorf("unexpected '%s'", tp.tokens[tp.pos])
+	}
+	return val, err
+}
+
+// next returns the next token without consuming it
+func (tp *templateParser) next() string {
+	if tp.pos < len(tp.tokens) {
+		return tp.tokens[tp.pos]
+	}
+	return ""
+}
+
+// parseSum parses 'term (('+'|'-') term)*'
+func (tp *templateParser) parseSum() (float64, error) {
+	val, err := tp.parseProduct()
+	for err == nil && (tp.next() == "+" || tp.next() == "-") {
+		op := tp.next()
+		tp.pos++
+		var rval float64
+		if rval, err = tp.parseProduct(); err == nil {
+			if op == "+" {
+				val = val + rval
+			} else {
+				val = val - rval
+			}
+		}
+	}
+	return val, err
+}
+
+// parseProduct parses 'unary (('*'|'/'|'%') unary)*'
+func (tp *templateParser) parseProduct() (float64, error) {
+	val, err := tp.parseUnary()
+	for err == nil && (tp.next() == "*" || tp.next() == "/" || tp.next() == "%") {
+		op := tp.next()
+		tp.pos++
+		var rval float64
+		if rval, err = tp.parseUnary(); err != nil {
+			break
+		}
+		switch {
+		case op == "*":
+			val = val * rval
+		case rval == 0:
+			err = fmt.Errorf("division by zero")
+		case op == "/":
+			val = val / rval
+		default:
+			val = math.Mod(val, rval)
+		}
+	}
+	return val, err
+}
+
+// parseUnary parses '-' unary | number | fact | '(' sum ')'
+func (tp *templateParser) parseUnary() (float64, error) {
+	token := tp.next()
+	tp.pos++
+	switch {
+	case token == "":
+		return 0, fmt.Errorf("unexpected end of expression")
+	case token == "-":
+		val, err := tp.parseUnary()
+		return -val, err
+	case token == "(":
+		val, err := tp.parseSum()
+		if err == nil && tp.next() != ")" {
+			err = fmt.Errorf("missing ')'")
+		}
+		tp.pos++
+		return val, err
+	}
+	if val, err := strconv.ParseFloat(token, 64); err == nil {
+		return val, nil
+	}
+	fact, ok := tp.facts[token]
+	if !ok {
+		return 0, fmt.Errorf("unknown fact or operator '%s', supported facts are '%s'", token, strings.Join(templateFactNames, "', '"))
+	}
+	val, err := strconv.ParseFloat(fact, 64)
+	if err != nil {
+		return 0, fmt.Errorf("fact '%s' with value '%s' is not a number", token, fact)
+	}
+	return val, nil
+}
@@ -0,0 +1,103 @@
+package note
+
+import (
+	"testing"
+)
+
+var tstTemplateFacts = map[string]string{
+	"mem_total_kb":    "8388608",
+	"mem_total_mb":    "8192",
+	"mem_total_bytes": "8589934592",
+	"swap_total_kb":   "2097152",
+	"cpu_count":       "4",
+	"numa_nodes":      "2",
+	"page_size":       "4096",
+	"os_version":      "15-SP6",
+	"os_major":        "15",
+}
+
+func TestIsTemplate(t *testing.T) {
+	if !IsTemplate("{{ mem_total_kb }}") {
+		t.Error("expected a template")
+	}
+	if IsTemplate("1024") || IsTemplate("{ mem_total_kb }") {
+		t.Error("expected no template")
+	}
+}
+
+func TestResolveTemplate(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"{{ mem_total_kb * 0.01 | max 1048576 }}", "83886"},
+		{"{{ mem_total_kb * 0.5 | max 1048576 }}", "1048576"},
+		{"{{ cpu_count | min 8 }}", "8"},
+		{"{{ mem_total_bytes }}", "8589934592"},
+		{"{{ os_version }}", "15-SP6"},
+		{"{{ (mem_total_mb + 1024) / numa_nodes }}", "4608"},
+		{"{{ -page_size % 1000 }}", "-96"},
+		{"{{ 10 / 4 | round }}", "3"},
+		{"{{ 10 / 4 | ceil }}", "3"},
+		{"{{ 10 / 4 | floor }}", "2"},
+		{"{{ cpu_count }} {{ numa_nodes }}", "4 2"},
+		{"1024", "1024"},
+	}
+	for _, test := range tests {
+		val, err := resolveTemplate(test.value, tstTemplateFacts)
+		if err != nil {
+			t.Errorf("'%s': unexpected error '%v'", test.value, err)
+		}
+		if val != test.expected {
+			t.Errorf("'%s': got '%s', expected '%s'", test.value, val, test.expected)
+		}
+	}
+
+	wrong := []string{
+		"{{ unknown_fact }}",
+		"{{ mem_total_kb | unknown }}",
+		"{{ mem_total_kb | max }}",
+		"{{ mem_total_kb | round 1 }}",
+		"{{ mem_total_kb | }}",
+		"{{ mem_total_kb / 0 }}",
+		"{{ (mem_total_kb + 1 }}",
+		"{{ mem_total_kb 1 }}",
+		"{{ os_version * 2 }}",
+		"{{ }}",
+	}
+	for _, value := range wrong {
+		if val, err := resolveTemplate(value, tstTemplateFacts); err == nil {
+			t.Errorf("'%s': expected an error, got '%s'", value, val)
+		}
+	}
+}
+
+func TestCheckTemplate(t *testing.T) {
+	if err := CheckTemplate("{{ mem_total_kb * 0.01 | max 1048576 }}"); err != nil {
+		t.Errorf("unexpected error '%v'", err)
+	}
+	if err := CheckTemplate("{{ mem_total_kb * }}"); err == nil {
+		t.Error("expected an error")
+	}
+
+	findings := LintNote("[version]\nVERSION=1\nDATE=01.01.2026\nDESCRIPTION=template\nREFERENCES=none\n\n[sysctl]\nvm.min_free_kbytes = {{ mem_total_kb * 0.01 | max 1048576 }}\nvm.swappiness = {{ swappiness }}\n")
+	errs := 0
+	for _, finding := range findings {
+		if finding.Severity == LintError {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("expected exactly one error, got '%+v'", findings)
+	}
+}
+
+func TestResolveTemplateFacts(t *testing.T) {
+	oldFacts := templateFacts
+	defer func() { templateFacts = oldFacts }()
+	templateFacts = func() map[string]string { return tstTemplateFacts }
+	val, err := ResolveTemplate("{{ numa_nodes * 2 }}")
+	if err != nil || val != "4" {
+		t.Errorf("got '%s', '%v'", val, err)
+	}
+}
@@ -0,0 +1,188 @@
+package solution
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Composition describes a solution, which is built from other solutions.
+// INCLUDES and EXCLUDE are defined in the [version] section of the
+// solution file
+type Composition struct {
+	Includes []string // solutions included by the solution
+	Exclude  []string // notes removed from the expanded note list
+	Sections []string // architecture sections defined in the solution file
+}
+
+// NoteOrigin is a note of an expanded solution together with the solution,
+// which lists the note directly
+type NoteOrigin struct {
+	NoteID string
+	Origin string
+}
+
+// solutionOrigins contains per architecture the expanded note lists of all
+// composed solutions with the origin of each note
+var solutionOrigins = make(map[string]map[string][]NoteOrigin)
+
+// GetNoteOrigins returns the expanded note list of a composed solution with
+// the origin of each note. For solutions without INCLUDES or EXCLUDE
+// nil is returned
+func GetNoteOrigins(solName, arch string) []NoteOrigin {
+	return solutionOrigins[arch][solName]
+}
+
+// getCompositions reads the INCLUDES and EXCLUDE entries of the [version]
+// section from all solution files found in the given directories
+func getCompositions(solsDirs ...string) map[string]Composition {
+	comps := make(map[string]Composition)
+	for _, solsDir := range solsDirs {
+		if solsDir == "" {
+			continue
+		}
+		entries, err := ioutil.ReadDir(solsDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sol") {
+				continue
+			}
+			content, err := txtparser.ParseINIFile(fmt.Sprintf("%s%s", solsDir, entry.Name()), false)
+			if err != nil {
+				continue
+			}
+			comp := Composition{}
+			for _, param := range content.KeyValue["version"] {
+				switch param.Key {
+				case "INCLUDES":
+					comp.Includes = strings.Fields(param.Value)
+				case "EXCLUDE":
+					comp.Exclude = strings.Fields(param.Value)
+				}
+			}
+			if len(comp.Includes) == 0 && len(comp.Exclude) == 0 {
+				continue
+			}
+			for section := range content.KeyValue {
+				if _, ok := archSections[section]; ok {
+					comp.Sections = append(comp.Sections, section)
+				}
+			}
+			comps[strings.TrimSuffix(entry.Name(), ".sol")] = comp
+		}
+	}
+	return comps
+}
+
+// expandSolutions resolves the INCLUDES and EXCLUDE entries of the composed
+// solutions for all architectures. Solutions with circular or unknown
+// includes are skipped.
+func expandSolutions(sols map[string]map[string]Solution, comps map[string]Composition) map[string]map[string]Solution {
+	solutionOrigins = make(map[string]map[string][]NoteOrigin)
+	warned := make(map[string]bool)
+	for arch, sol := range sols {
+		section := ArchSection(arch)
+		for solName, comp := range comps {
+			if _, ok := sol[solName]; !ok && system.IsInList(section, comp.Sections) {
+				// solution only consists of included solutions
+				sol[solName] = Solution{}
+			}
+		}
+		origins := make(map[string][]NoteOrigin)
+		for _, solName := range sortedSolutionNames(comps) {
+			if _, ok := sol[solName]; !ok {
+				continue
+			}
+			solOrigins, err := resolveSolution(solName, sol, comps, []string{})
+			if err != nil {
+				// as the function most of the time is called
+				// before the logging is initialized use
+				// Fprintf instead to give customers a hint.
+				if !warned[solName] {
+					fmt.Fprintf(os.Stderr, "Warning: skip solution '%s' - %v\n", solName, err)
+					warned[solName] = true
+				}
+				continue
+			}
+			origins[solName] = solOrigins
+		}
+		// replace the solutions after resolving all of them, so that
+		// the raw note lists are used for the includes
+		for solName := range comps {
+			solOrigins, ok := origins[solName]
+			if !ok {
+				delete(sol, solName)
+				continue
+			}
+			notes := Solution{}
+			for _, origin := range solOrigins {
+				notes = append(notes, origin.NoteID)
+			}
+			sol[solName] = notes
+		}
+		solutionOrigins[arch] = origins
+	}
+	return sols
+}
+
+// resolveSolution returns the expanded note list of the solution.
+// The notes of the included solutions come first in the order of INCLUDES,
+// followed by the notes listed by the solution itself. Duplicate notes are
+// removed, the notes from EXCLUDE are dropped at last.
+func resolveSolution(solName string, sol map[string]Solution, comps map[string]Composition, path []string) ([]NoteOrigin, error) {
+	if system.IsInList(solName, path) {
+		return nil, fmt.Errorf("circular solution include '%s'", strings.Join(append(path, solName), " -> "))
+	}
+	notes, ok := sol[solName]
+	if !ok {
+		return nil, fmt.Errorf("included solution '%s' not available", solName)
+	}
+	origins := []NoteOrigin{}
+	seen := make(map[string]bool)
+	addNote := func(noteID, origin string) {
+		if noteID == "" || seen[noteID] {
+			return
+		}
+		seen[noteID] = true
+		origins = append(origins, NoteOrigin{NoteID: noteID, Origin: origin})
+	}
+	comp := comps[solName]
+	for _, include := range comp.Includes {
+		incOrigins, err := resolveSolution(include, sol, comps, append(path, solName))
+		if err != nil {
+			return nil, err
+		}
+		for _, origin := range incOrigins {
+			addNote(origin.NoteID, origin.Origin)
+		}
+	}
+	for _, noteID := range notes {
+		addNote(noteID, solName)
+	}
+	if len(comp.Exclude) == 0 {
+		return origins, nil
+	}
+	filtered := []NoteOrigin{}
+	for _, origin := range origins {
+		if !system.IsInList(origin.NoteID, comp.Exclude) {
+			filtered = append(filtered, origin)
+		}
+	}
+	return filtered, nil
+}
+
+// sortedSolutionNames returns the names of the solutions sorted
+func sortedSolutionNames(comps map[string]Composition) []string {
+	names := make([]string, 0, len(comps))
+	for name := range comps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
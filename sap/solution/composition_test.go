@@ -0,0 +1,76 @@
+package solution
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestExpandSolutions(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "saptune-sols")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	solFiles := map[string]string{
+		"BASE1.sol":   "[ArchX86]\nN1 N2 N3\n\n[ArchPPC64LE]\nN1 N2 N3\n",
+		"BASE2.sol":   "[ArchX86]\nN3 N4\n\n[ArchPPC64LE]\nN3 N4\n",
+		"COMBI.sol":   "[version]\nINCLUDES=BASE1 BASE2\nEXCLUDE=N2\n\n[ArchX86]\nN9 N4\n\n[ArchPPC64LE]\nN9 N4\n",
+		"ONLYINC.sol": "[version]\nINCLUDES=COMBI\n\n[ArchX86]\n\n[ArchPPC64LE]\n",
+		"CYC1.sol":    "[version]\nINCLUDES=CYC2\n\n[ArchX86]\nN1\n\n[ArchPPC64LE]\nN1\n",
+		"CYC2.sol":    "[version]\nINCLUDES=CYC1\n\n[ArchX86]\nN2\n\n[ArchPPC64LE]\nN2\n",
+		"MISSING.sol": "[version]\nINCLUDES=NOSUCHSOL\n\n[ArchX86]\nN1\n\n[ArchPPC64LE]\nN1\n",
+	}
+	for name, content := range solFiles {
+		if err := ioutil.WriteFile(path.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sols := GetSolutionDefintion(tmpDir+"/", "", "")
+	arch := runtime.GOARCH
+	if got := strings.Join(sols[arch]["COMBI"], " "); got != "N1 N3 N4 N9" {
+		t.Errorf("got: '%s', expected: 'N1 N3 N4 N9'\n", got)
+	}
+	if got := strings.Join(sols[arch]["ONLYINC"], " "); got != "N1 N3 N4 N9" {
+		t.Errorf("got: '%s', expected: 'N1 N3 N4 N9'\n", got)
+	}
+	// flat solutions stay unchanged
+	if got := strings.Join(sols[arch]["BASE1"], " "); got != "N1 N2 N3" {
+		t.Errorf("got: '%s', expected: 'N1 N2 N3'\n", got)
+	}
+	for _, sol := range []string{"CYC1", "CYC2", "MISSING"} {
+		if _, ok := sols[arch][sol]; ok {
+			t.Errorf("solution '%s' with wrong includes not skipped", sol)
+		}
+	}
+	exp := []NoteOrigin{{"N1", "BASE1"}, {"N3", "BASE1"}, {"N4", "BASE2"}, {"N9", "COMBI"}}
+	if origins := GetNoteOrigins("COMBI", arch); !reflect.DeepEqual(origins, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", origins, exp)
+	}
+	if origins := GetNoteOrigins("BASE1", arch); origins != nil {
+		t.Errorf("got: '%+v', expected no origins for a flat solution\n", origins)
+	}
+}
+
+func TestShippedCompositions(t *testing.T) {
+	solsDir := path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/ospackage/usr/share/saptune/sols_15") + "/"
+	sols := GetSolutionDefintion(solsDir, "", "")
+	// former flat note lists of the composed solutions
+	flat := "941735 1771258 1980196 2578899 2684254 2382421 2534844 2993054 1656250"
+	for _, arch := range []string{ArchX86, ArchPPC64LE, ArchARM64} {
+		for _, sol := range []string{"NETWEAVER+HANA", "S4HANA-APP+DB"} {
+			if got := strings.Join(sols[arch][sol], " "); got != flat {
+				t.Errorf("%s on %s - got: '%s', expected: '%s'\n", sol, arch, got, flat)
+			}
+		}
+	}
+	for _, sol := range []string{"NETWEAVER+HANA", "S4HANA-APP+DB"} {
+		if _, ok := sols[ArchS390X][sol]; ok {
+			t.Errorf("solution '%s' unexpected available on %s", sol, ArchS390X)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package solution
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CustomiseSolution changes the Note list of the architecture section
+// 'section' (e.g. 'ArchX86') in the content of a solution override file.
+// Notes in 'add' are appended to the Note list, Notes in 'remove' are
+// removed. Returns the new content and the Notes really added or removed.
+func CustomiseSolution(content, section string, add, remove []string) (string, []string, []string, error) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	header := fmt.Sprintf("[%s]", section)
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == header {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return "", nil, nil, fmt.Errorf("section '%s' not found in the solution definition", header)
+	}
+	// collect the Note list of the section, which may span several lines
+	end := start + 1
+	notes := []string{}
+	listLine := -1
+	contLines := make(map[int]bool)
+	for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), "[") {
+		trimmed := strings.TrimSpace(lines[end])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			notes = append(notes, strings.Fields(trimmed)...)
+			if listLine == -1 {
+				listLine = end
+			} else {
+				contLines[end] = true
+			}
+		}
+		end++
+	}
+
+	added := []string{}
+	removed := []string{}
+	for _, noteID := range remove {
+		for i := 0; i < len(notes); i++ {
+			if notes[i] == noteID {
+				notes = append(notes[:i], notes[i+1:]...)
+				removed = append(removed, noteID)
+				i--
+			}
+		}
+	}
+	for _, noteID := range add {
+		found := false
+		for _, note := range notes {
+			if note == noteID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			notes = append(notes, noteID)
+			added = append(added, noteID)
+		}
+	}
+	if len(notes) == 0 {
+		return "", nil, nil, fmt.Errorf("the Note list of section '%s' must not be empty", header)
+	}
+
+	if listLine == -1 {
+		lines = append(lines[:start+1], append([]string{strings.Join(notes, " ")}, lines[start+1:]...)...)
+	} else {
+		lines[listLine] = strings.Join(notes, " ")
+	}
+	// remove the continuation lines of the former Note list
+	result := []string{}
+	for i, line := range lines {
+		if contLines[i] {
+			continue
+		}
+		result = append(result, line)
+	}
+	return strings.Join(result, "\n") + "\n", added, removed, nil
+}
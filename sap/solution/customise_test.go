@@ -0,0 +1,34 @@
+package solution
+
+import (
+	"testing"
+)
+
+func TestCustomiseSolution(t *testing.T) {
+	content := "[version]\n# SAP-NOTE=HANA CATEGORY=SOLUTION VERSION=1\n\n[ArchX86]\n941735 1771258\n1980196\n\n[ArchPPC64LE]\n941735 1771258 1980196\n"
+	expected := "[version]\n# SAP-NOTE=HANA CATEGORY=SOLUTION VERSION=1\n\n[ArchX86]\n941735 1980196 2382421\n\n[ArchPPC64LE]\n941735 1771258 1980196\n"
+	newContent, added, removed, err := CustomiseSolution(content, "ArchX86", []string{"2382421", "941735"}, []string{"1771258", "4711"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newContent != expected {
+		t.Errorf("got '%s', expected '%s'", newContent, expected)
+	}
+	if len(added) != 1 || added[0] != "2382421" || len(removed) != 1 || removed[0] != "1771258" {
+		t.Errorf("got added '%v' and removed '%v'", added, removed)
+	}
+
+	// nothing to change
+	newContent, added, removed, err = CustomiseSolution(expected, "ArchX86", []string{"941735"}, nil)
+	if err != nil || newContent != expected || len(added) != 0 || len(removed) != 0 {
+		t.Errorf("got '%s', '%v', '%v', '%v'", newContent, added, removed, err)
+	}
+
+	// errors
+	if _, _, _, err := CustomiseSolution(content, "ArchS390X", []string{"941735"}, nil); err == nil {
+		t.Error("expected an error for a missing section")
+	}
+	if _, _, _, err := CustomiseSolution(content, "ArchPPC64LE", nil, []string{"941735", "1771258", "1980196"}); err == nil {
+		t.Error("expected an error for an empty Note list")
+	}
+}
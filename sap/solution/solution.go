@@ -25,8 +25,21 @@ const (
 	ArchPPC64LE            = "ppc64le"    // ArchPPC64LE is the GOARCH for 64-bit PowerPC little endian platform.
 	ArchX86PC              = "amd64_PC"   // ArchX86 is the GOARCH value for x86 platform. PC indicates PageCache is available
 	ArchPPC64LEPC          = "ppc64le_PC" // ArchPPC64LE is the GOARCH for 64-bit PowerPC little endian platform. PC indicates PageCache is available
+	ArchARM64              = "arm64"      // ArchARM64 is the GOARCH value for 64-bit ARM platform.
+	ArchS390X              = "s390x"      // ArchS390X is the GOARCH value for IBM Z platform.
+	ArchARM64PC            = "arm64_PC"   // ArchARM64 is the GOARCH value for 64-bit ARM platform. PC indicates PageCache is available
+	ArchS390XPC            = "s390x_PC"   // ArchS390X is the GOARCH value for IBM Z platform. PC indicates PageCache is available
 )
 
+// archSections maps the architecture sections of the solution files to the
+// related GOARCH values
+var archSections = map[string]string{
+	"ArchX86":     ArchX86,
+	"ArchPPC64LE": ArchPPC64LE,
+	"ArchARM64":   ArchARM64,
+	"ArchS390X":   ArchS390X,
+}
+
 // Solution is identified by set of note numbers.
 type Solution []string
 
@@ -66,7 +79,7 @@ func GetSolutionDefintion(solsDir, extraDir, noteDir string) map[string]map[stri
 		if param.Section == "reminder" || param.Section == "version" {
 			continue
 		}
-		if param.Section != "ArchX86" && param.Section != "ArchPPC64LE" {
+		if _, ok := archSections[param.Section]; !ok {
 			// as the function most of the time is called
 			// before the logging is initialized use
 			// Fprintf instead to give customers a hint.
@@ -100,7 +113,8 @@ func GetSolutionDefintion(solsDir, extraDir, noteDir string) map[string]map[stri
 		}
 	}
 	sols = storeSols(arch, pcarch, sol, sols)
-	return sols
+	// resolve solutions including other solutions
+	return expandSolutions(sols, getCompositions(solsDir, extraDir))
 }
 
 // GetOtherSolution reads override, custom or deprecated solution definition
@@ -122,7 +136,7 @@ func GetOtherSolution(solsDir, noteFiles, extraFiles string) map[string]map[stri
 		if param.Section == "reminder" || param.Section == "version" {
 			continue
 		}
-		if param.Section != "ArchX86" && param.Section != "ArchPPC64LE" {
+		if _, ok := archSections[param.Section]; !ok {
 			// as the function most of the time is called
 			// before the logging is initialized use
 			// Fprintf instead to give customers a hint.
@@ -194,17 +208,25 @@ func checkSolutionNotes(param txtparser.INIEntry, fileName, noteFiles, extraFile
 // setSolutionArch sets arch and pcarch variables regarding the current
 // architecture read from the solution file
 func setSolutionArch(curArch string) (arch, pcarch string) {
-	switch curArch {
-	case "ArchPPC64LE":
-		arch = "ppc64le"
-		pcarch = "ppc64le_PC"
-	case "ArchX86":
-		arch = "amd64"
-		pcarch = "amd64_PC"
+	if goarch, ok := archSections[curArch]; ok {
+		arch = goarch
+		pcarch = goarch + "_PC"
 	}
 	return
 }
 
+// ArchSection returns the name of the solution file section related to the
+// architecture (GOARCH value with or without '_PC' suffix)
+func ArchSection(arch string) string {
+	arch = strings.TrimSuffix(arch, "_PC")
+	for section, goarch := range archSections {
+		if goarch == arch {
+			return section
+		}
+	}
+	return ""
+}
+
 // storeSols stores the collected solutions in the solution map
 // related to the last current architecture read from the solution file
 func storeSols(arch, pcarch string, sol map[string]Solution, sols map[string]map[string]Solution) map[string]map[string]Solution {
@@ -2,6 +2,7 @@ package solution
 
 import (
 	"github.com/SUSE/saptune/system"
+	"io/ioutil"
 	"os"
 	"path"
 	"reflect"
@@ -200,3 +201,26 @@ func TestRefresh(t *testing.T) {
 		t.Errorf("got: %+v, expected: %+v\n", AllSolutions, allSols)
 	}
 }
+
+func TestArchSections(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "saptune-arch-sols")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	content := "[ArchX86]\nN1 N2\n\n[ArchPPC64LE]\nN1 N2\n\n[ArchARM64]\nN1 N3\n\n[ArchS390X]\nN4\n"
+	if err := ioutil.WriteFile(path.Join(tmpDir, "ARCHSOL.sol"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sols := GetSolutionDefintion(tmpDir+"/", "", "")
+	for arch, exp := range map[string]string{ArchX86: "N1 N2", ArchPPC64LE: "N1 N2", ArchARM64: "N1 N3", ArchS390X: "N4"} {
+		if got := strings.Join(sols[arch]["ARCHSOL"], " "); got != exp {
+			t.Errorf("arch '%s' - got: '%s', expected: '%s'\n", arch, got, exp)
+		}
+	}
+	for arch, exp := range map[string]string{"amd64": "ArchX86", "ppc64le_PC": "ArchPPC64LE", "arm64": "ArchARM64", "s390x_PC": "ArchS390X", "mips": ""} {
+		if section := ArchSection(arch); section != exp {
+			t.Errorf("arch '%s' - got: '%s', expected: '%s'\n", arch, section, exp)
+		}
+	}
+}
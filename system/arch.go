@@ -0,0 +1,73 @@
+package system
+
+import (
+	"io/ioutil"
+	"runtime"
+	"strings"
+)
+
+// supported system architectures (GOARCH values)
+const (
+	ArchAMD64   = "amd64"
+	ArchPPC64LE = "ppc64le"
+	ArchARM64   = "arm64"
+	ArchS390X   = "s390x"
+)
+
+// systemArch is the architecture of the running system. A variable to be
+// able to test the architecture specific handling
+var systemArch = runtime.GOARCH
+
+// sysinfoFile contains the machine information of IBM Z systems
+var sysinfoFile = "/proc/sysinfo"
+
+// GetArch returns the architecture of the running system (GOARCH value)
+func GetArch() string {
+	return systemArch
+}
+
+// ArchUname maps a GOARCH value to the related 'uname -i' output
+func ArchUname(arch string) string {
+	switch arch {
+	case ArchAMD64:
+		return "x86_64"
+	case ArchARM64:
+		return "aarch64"
+	}
+	return arch
+}
+
+// archSupportsPerfBias returns true, if the architecture supports Intel's
+// performance bias setting
+func archSupportsPerfBias() bool {
+	return systemArch == ArchAMD64
+}
+
+// archSupportsCPUIdle returns true, if the latency settings of the cpuidle
+// states are relevant for the architecture.
+// POWER and IBM Z do not use cpuidle states for latency tuning
+func archSupportsCPUIdle() bool {
+	return systemArch != ArchPPC64LE && systemArch != ArchS390X
+}
+
+// isIBMArch returns true for IBM POWER and IBM Z systems
+func isIBMArch() bool {
+	return systemArch == ArchPPC64LE || systemArch == ArchS390X
+}
+
+// getSysinfoType returns the machine type from the sysinfo file of IBM Z
+// systems (line 'Type: 8561')
+func getSysinfoType(file string) string {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		InfoLog("failed to read %s - %v", file, err)
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.SplitN(line, ":", 2)
+		if len(fields) == 2 && strings.TrimSpace(fields[0]) == "Type" {
+			return strings.TrimSpace(fields[1])
+		}
+	}
+	return ""
+}
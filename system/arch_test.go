@@ -0,0 +1,96 @@
+package system
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestArchUname(t *testing.T) {
+	for arch, exp := range map[string]string{"amd64": "x86_64", "arm64": "aarch64", "ppc64le": "ppc64le", "s390x": "s390x"} {
+		if uname := ArchUname(arch); uname != exp {
+			t.Errorf("arch '%s' - got: '%s', expected: '%s'\n", arch, uname, exp)
+		}
+	}
+}
+
+func TestArchSpecificCPUHandling(t *testing.T) {
+	oldArch := systemArch
+	oldCPUDir := cpuDir
+	defer func() {
+		systemArch = oldArch
+		cpuDir = oldCPUDir
+	}()
+	// fixture sysfs tree with cpuidle states
+	tmpDir, err := ioutil.TempDir("", "saptune-cpu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	for _, state := range []string{"state0", "state1"} {
+		if err := os.MkdirAll(path.Join(tmpDir, "cpu0", "cpuidle", state), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cpuDir = tmpDir
+
+	for _, arch := range []string{ArchPPC64LE, ArchS390X} {
+		systemArch = arch
+		if lat, states, _ := GetFLInfo(); lat != "all:none" || states != "all:none" {
+			t.Errorf("arch '%s' - got: '%s', '%s', expected: 'all:none'\n", arch, lat, states)
+		}
+		if canSetForceLatency("70", "") {
+			t.Errorf("arch '%s' - force latency reported as settable\n", arch)
+		}
+	}
+	for _, arch := range []string{ArchARM64, ArchS390X, ArchPPC64LE} {
+		systemArch = arch
+		if pb := GetPerfBias(); pb != "all:none" {
+			t.Errorf("arch '%s' - got: '%s', expected: 'all:none'\n", arch, pb)
+		}
+		if supportsPerfBias() {
+			t.Errorf("arch '%s' - perf bias reported as supported\n", arch)
+		}
+	}
+	systemArch = ArchARM64
+	if lat, _, _ := GetFLInfo(); lat == "all:none" {
+		t.Errorf("arch '%s' - cpuidle states not found in '%s'\n", ArchARM64, tmpDir)
+	}
+	if sel := GetSolutionSelector(); sel != "arm64" && sel != "arm64_PC" {
+		t.Errorf("got: '%s', expected: 'arm64' or 'arm64_PC'\n", sel)
+	}
+}
+
+func TestGetSysinfoType(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "sysinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString("Manufacturer:         IBM\nType:                 8561\nModel:                703              T01\n"); err != nil {
+		t.Fatal(err)
+	}
+	tmpFile.Close()
+	if typ := getSysinfoType(tmpFile.Name()); typ != "8561" {
+		t.Errorf("got: '%s', expected: '8561'\n", typ)
+	}
+	if typ := getSysinfoType("/file_does_not_exist"); typ != "" {
+		t.Errorf("got: '%s', expected an empty string\n", typ)
+	}
+
+	oldArch := systemArch
+	oldSysinfo := sysinfoFile
+	defer func() {
+		systemArch = oldArch
+		sysinfoFile = oldSysinfo
+	}()
+	systemArch = ArchS390X
+	sysinfoFile = tmpFile.Name()
+	if vendor, _ := GetHWIdentity("vendor"); vendor != "IBM" {
+		t.Errorf("got: '%s', expected: 'IBM'\n", vendor)
+	}
+	if model, _ := GetHWIdentity("model"); model != "8561" {
+		t.Errorf("got: '%s', expected: '8561'\n", model)
+	}
+}
@@ -2,7 +2,6 @@ package system
 
 import (
 	"os"
-	"regexp"
 	"strings"
 )
 
@@ -46,75 +45,106 @@ func GetFlagVal(flag string) string {
 	return saptFlags[flag]
 }
 
-// ParseCliArgs parses the command line to identify special flags and the
+// GetFlagVals returns all values of a saptune commandline flag, which may
+// be used more than once in the command line
+func GetFlagVals(flag string) []string {
+	if saptFlags[flag] == "" {
+		return []string{}
+	}
+	return strings.Split(saptFlags[flag], "\n")
+}
+
+// cliToken is a single command line argument classified by the command tree
+type cliToken struct {
+	// arg is the argument as given on the command line
+	arg string
+	// flag is the known flag, nil for positional arguments and unknown
+	// flags
+	flag *CmdFlag
+	// value is the value of a value flag
+	value string
+	// isFlag marks flags, known or unknown
+	isFlag bool
+	// argPos is the number of positional arguments before the token
+	argPos int
+}
+
+// tokenizeCliArgs classifies the command line arguments (without the
+// program name) as positional arguments, known and unknown flags
+func tokenizeCliArgs(args []string) []cliToken {
+	tokens := []cliToken{}
+	argPos := 0
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			tokens = append(tokens, cliToken{arg: arg, argPos: argPos})
+			argPos++
+			continue
+		}
+		token := cliToken{arg: arg, isFlag: true, argPos: argPos}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"), "=")
+		flag := LookupFlag(name)
+		switch {
+		case flag == nil:
+			// unknown flag
+		case flag.Value == "" && !hasValue:
+			token.flag = flag
+		case flag.Value != "" && hasValue:
+			token.flag = flag
+			token.value = value
+		case flag.Value != "" && flag.Separate:
+			// flag with value in the next argument (--note NoteID)
+			token.flag = flag
+			if i+1 < len(args) {
+				i++
+				token.value = args[i]
+			}
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// ParseCliArgs parses the command line to identify the flags and the
 // 'normal' arguments
 // returns a map of Flags (set/not set or value) and a slice containing the
 // remaining arguments
-// possible Flags - force, dryrun, help, version, format, colorscheme
-// on command line - --force, --dry-run or --dryrun, --help, --version, --color-scheme, --format
-// Some Flags (like 'format') can have a value (--format=json or --format=csv)
+// The supported flags are defined in CliFlags. Switches (like --force) are
+// set to 'true', flags with value (like --format=json or --note NoteID) are
+// set to their value. Unknown flags are collected in 'notSupported'
 func ParseCliArgs() ([]string, map[string]string) {
 	stArgs := []string{os.Args[0]}
-	// supported flags
-	stFlags := map[string]string{"force": "false", "dryrun": "false", "help": "false", "version": "false", "show-non-compliant": "false", "format": "", "colorscheme": "", "non-compliance-check": "false", "notSupported": ""}
-	for _, arg := range os.Args[1:] {
-		if strings.HasPrefix(arg, "--") || strings.HasPrefix(arg, "-") {
-			// argument is a flag
-			handleFlags(arg, stFlags)
-			continue
+	stFlags := map[string]string{"notSupported": ""}
+	for _, flag := range CliFlags {
+		if flag.Value == "" {
+			stFlags[flag.Name] = "false"
+		} else {
+			stFlags[flag.Name] = ""
+		}
+	}
+	for _, token := range tokenizeCliArgs(os.Args[1:]) {
+		switch {
+		case !token.isFlag:
+			stArgs = append(stArgs, token.arg)
+		case token.flag == nil:
+			setUnsupportedFlag(token.arg, stFlags)
+		case token.flag.Value == "":
+			stFlags[token.flag.Name] = "true"
+		default:
+			setFlagValue(token.flag, token.value, stFlags)
 		}
-		// other args
-		stArgs = append(stArgs, arg)
 	}
 	return stArgs, stFlags
 }
 
-// handleFlags checks for valid flags in the CLI arg list
-func handleFlags(arg string, flags map[string]string) {
-	var valueFlag = regexp.MustCompile(`(-[\w-]+)=(.*)`)
-	matches := valueFlag.FindStringSubmatch(arg)
-	if len(matches) == 3 {
-		// flag with value
-		handleValueFlags(arg, matches, flags)
+// setFlagValue sets the value of a value flag. The values of flags, which
+// may be used more than once, are separated by a newline
+func setFlagValue(flag *CmdFlag, val string, flags map[string]string) {
+	if flag.Multi && flags[flag.Name] != "" {
+		flags[flag.Name] = flags[flag.Name] + "\n" + val
 		return
 	}
-	handleSimpleFlags(arg, flags)
-}
-
-// handleValueFlags checks for valid flags with value in the CLI arg list
-func handleValueFlags(arg string, matches []string, flags map[string]string) {
-	if strings.Contains(arg, "--format") {
-		// --format=json
-		flags["format"] = matches[2]
-	}
-	if strings.Contains(arg, "-colorscheme") {
-		// --colorscheme=zebra
-		flags["colorscheme"] = matches[2]
-	}
-	if _, ok := flags[strings.TrimLeft(matches[1], "-")]; !ok {
-		setUnsupportedFlag(matches[1], flags)
-	}
-}
-
-// handleSimpleFlags checks for valid flags in the CLI arg list
-func handleSimpleFlags(arg string, flags map[string]string) {
-	// simple flags
-	switch arg {
-	case "--force", "-force":
-		flags["force"] = "true"
-	case "--dry-run", "-dry-run", "--dryrun", "-dryrun":
-		flags["dryrun"] = "true"
-	case "--help", "-help", "-h":
-		flags["help"] = "true"
-	case "--version", "-version":
-		flags["version"] = "true"
-	case "--show-non-compliant", "-show-non-compliant":
-		flags["show-non-compliant"] = "true"
-	case "--non-compliance-check", "-non-compliance-check":
-		flags["non-compliance-check"] = "true"
-	default:
-		setUnsupportedFlag(arg, flags)
-	}
+	flags[flag.Name] = val
 }
 
 // setUnsupportedFlag sets or appends a value to the unsupported Flag
@@ -127,186 +157,92 @@ func setUnsupportedFlag(val string, flags map[string]string) {
 	}
 }
 
-// ChkCliSyntax checks, if command line parameter are in the right order
-// only checking the right position of the 'options' aka 'flags'
+// ChkCliSyntax checks the command line against the command tree
 // saptune globOpt realm realmOpt cmd cmdOpt param
+// The global option --format has to be the first argument, all other flags
+// have to be supported by the given realm or command and have to follow it.
+// Wrong or missing commands and arguments without any command flag set are
+// left to the 'old' default checks (in main and/or actions)
 func ChkCliSyntax() bool {
-	ret := true
-	cmdLinePos := map[string]int{"globOpt": 1, "realm": 1, "realmOpt": 2, "cmd": 2, "cmdOpt": 3}
-
-	// check some universal syntax and the global option
-	// manipulating cmdLinePos values
-	if !chkGlobalSyntax(cmdLinePos) {
+	// check minimum of arguments without flags (saptune + realm)
+	if len(saptArgs) < 2 || IsFlagSet("notSupported") {
 		return false
 	}
-	// check for realm options
-	// manipulating cmdLinePos values
-	if !chkRealmOpts(cmdLinePos) {
+	// check global option
+	// saptune -format=FORMAT
+	if IsFlagSet("format") && (!strings.Contains(os.Args[1], "--format") || !isValidFormat(GetFlagVal("format"))) {
 		return false
 	}
-	// check for command options
-	if !chkCmdOpts(cmdLinePos) {
+	// saptune --wait=TIMEOUT
+	if _, ok := parseLockWait(GetFlagVal("wait")); !ok {
 		return false
 	}
-	return ret
-}
-
-// chkGlobalSyntax checks some universal syntax and the global option
-func chkGlobalSyntax(cmdLinePos map[string]int) bool {
-	stArgs := os.Args
-	ret := true
-	// check minimum of arguments without flags (saptune + realm)
-	if len(saptArgs) < 2 {
-		// too few arguments
-		ret = false
-	}
-	if IsFlagSet("notSupported") {
-		// unknown flag in command line found
-		ret = false
-	}
-	if IsFlagSet("force") && IsFlagSet("dryrun") {
-		// both together are not supported
-		// even that this case is handled correctly
-		ret = false
-	}
-	if IsFlagSet("colorscheme") && IsFlagSet("show-non-compliant") && len(stArgs) < cmdLinePos["cmdOpt"]+1 {
-		// too few options for both flags set
-		ret = false
+	node, depth := FindCommand(CliArgs(1))
+	if node != nil && node.FlagRequired && !anyFlagSet(node.Flags) && !IsFlagSet("help") {
+		// saptune configure [--from FILE|--export]
+		return false
 	}
-
-	// check global option
-	// saptune -format=FORMAT
-	if IsFlagSet("format") {
-		if !strings.Contains(stArgs[1], "--format") {
-			ret = false
-		} else {
-			cmdLinePos["realm"] = cmdLinePos["realm"] + 1
-			cmdLinePos["realmOpt"] = cmdLinePos["realmOpt"] + 1
-			cmdLinePos["cmd"] = cmdLinePos["cmd"] + 1
-			cmdLinePos["cmdOpt"] = cmdLinePos["cmdOpt"] + 1
+	cmdFlagSet := false
+	lastIndex := -1
+	for _, token := range tokenizeCliArgs(os.Args[1:]) {
+		if token.flag == nil || token.flag.Global {
+			continue
 		}
-	}
-	return ret
-}
-
-// chkRealmOpts checks for realm options
-// at the moment only 'saptune status' has an option (--non-compliance-check)
-func chkRealmOpts(cmdLinePos map[string]int) bool {
-	stArgs := os.Args
-	ret := true
-	if IsFlagSet("non-compliance-check") {
-		// check for valid realm
-		if !(stArgs[cmdLinePos["realm"]] == "status" || stArgs[cmdLinePos["realm"]] == "service" || stArgs[cmdLinePos["realm"]] == "daemon") {
-			ret = false
+		cmdFlagSet = true
+		if node == nil || !node.hasFlag(token.flag.Name) {
+			// flag not supported by the realm or command
+			return false
+		}
+		if token.argPos < depth || (node.FlagsFirst && token.argPos != depth) {
+			// flag at wrong place in arg list
+			return false
 		}
-		if stArgs[cmdLinePos["realm"]] == "status" {
-			// realm option set
-			// check minimum of values items in cmd line
-			// (saptune + realm + option)
-			if len(stArgs) < cmdLinePos["realmOpt"]+1 {
-				// too few arguments
-				ret = false
-			} else if stArgs[cmdLinePos["realmOpt"]] != "--non-compliance-check" {
-				ret = false
-			} else {
-				cmdLinePos["cmd"] = cmdLinePos["cmd"] + 1
-				cmdLinePos["cmdOpt"] = cmdLinePos["cmdOpt"] + 1
+		if index := flagIndex(node.Ordered, token.flag.Name); index >= 0 {
+			if index < lastIndex {
+				// flags in wrong order
+				return false
 			}
+			lastIndex = index
 		}
 	}
-	return ret
-}
-
-// chkCmdOpts checks for command options
-func chkCmdOpts(cmdLinePos map[string]int) bool {
-	ret := true
-	// check minimum of arguments for command options
-	// saptune realm cmd
-	if len(saptArgs) < 3 && (IsFlagSet("force") || IsFlagSet("dryrun") || IsFlagSet("colorscheme") || IsFlagSet("show-non-compliant")) {
-		// too few arguments for the active flags
-		return false
-	}
-	if len(os.Args) < cmdLinePos["cmdOpt"]+1 || (!IsFlagSet("force") && !IsFlagSet("dryrun") && !IsFlagSet("colorscheme") && !IsFlagSet("show-non-compliant") && !IsFlagSet("non-compliance-check")) {
-		// no command options set or too few options
-		// and/or non of the flags set, which need further checks
-		// so let the 'old' default checks (in main and/or actions) set
-		// the appropriate result
+	if !cmdFlagSet {
 		return true
 	}
-	// saptune staging release [--force|--dry-run] [NOTE...|SOLUTION...|all]
-	if !chkStagingReleaseSyntax(cmdLinePos) {
-		ret = false
-	}
-	// saptune note verify [--colorscheme=<color scheme>] [--show-non-compliant] [NOTEID]
-	if !chkNoteVerifySyntax(cmdLinePos) {
-		ret = false
-	}
-	// saptune (service) status  [--non-compliance-check]
-	if !chkServiceStatusSyntax(cmdLinePos) {
-		ret = false
-	}
-	return ret
-}
-
-// chkStagingReleaseSyntax checks the syntax of 'saptune staging release'
-// command line regarding command line options
-// saptune staging release [--force|--dry-run] [NOTE...|SOLUTION...|all]
-func chkStagingReleaseSyntax(cmdLinePos map[string]int) bool {
-	stArgs := os.Args
-	ret := true
-	if IsFlagSet("dryrun") || IsFlagSet("force") {
-		if !(stArgs[cmdLinePos["realm"]] == "staging" && stArgs[cmdLinePos["cmd"]] == "release") {
-			ret = false
+	if exclusive := 0; len(node.Exclusive) > 0 {
+		for _, flag := range node.Exclusive {
+			if IsFlagSet(flag) {
+				exclusive++
+			}
 		}
-		if stArgs[cmdLinePos["cmdOpt"]] != "--dry-run" && stArgs[cmdLinePos["cmdOpt"]] != "--force" {
-			ret = false
+		if exclusive > 1 {
+			// e.g. --force and --dry-run together are not supported
+			return false
 		}
 	}
-	return ret
+	return chkCmdArgs(node, len(saptArgs)-1-depth)
 }
 
-// chkNoteVerifySyntax checks the syntax of 'saptune note verify' command line
-// regarding command line options
-// saptune note verify [--colorscheme=<color scheme>] [--show-non-compliant] [NOTEID]
-func chkNoteVerifySyntax(cmdLinePos map[string]int) bool {
-	stArgs := os.Args
-	ret := true
-	if IsFlagSet("colorscheme") || IsFlagSet("show-non-compliant") {
-		if !(stArgs[cmdLinePos["realm"]] == "note" && stArgs[cmdLinePos["cmd"]] == "verify") {
-			ret = false
+// chkCmdArgs checks the number of positional arguments of a command
+func chkCmdArgs(node *CmdNode, count int) bool {
+	minArgs := 0
+	maxArgs := len(node.Args)
+	for _, arg := range node.Args {
+		if !arg.Optional {
+			minArgs++
 		}
-	}
-	if IsFlagSet("colorscheme") && IsFlagSet("show-non-compliant") {
-		// both flags set, check order
-		if !(strings.HasPrefix(stArgs[cmdLinePos["cmdOpt"]], "--colorscheme=") && stArgs[cmdLinePos["cmdOpt"]+1] == "--show-non-compliant") {
-			ret = false
+		if arg.Multi {
+			maxArgs = count
 		}
-	} else if IsFlagSet("colorscheme") && !strings.HasPrefix(stArgs[cmdLinePos["cmdOpt"]], "--colorscheme=") {
-		// flag at wrong place in arg list
-		ret = false
-	} else if IsFlagSet("show-non-compliant") && stArgs[cmdLinePos["cmdOpt"]] != "--show-non-compliant" {
-		// flag at wrong place in arg list
-		ret = false
 	}
-	return ret
+	return count >= minArgs && count <= maxArgs
 }
 
-// chkServiceStatusSyntax checks the syntax of 'saptune service status' or
-// 'saptune daemon status' command line regarding command line options
-// saptune service status [--non-compliance-check]
-// saptune status [--non-compliance-check] is checked earlier (as 'realm')
-func chkServiceStatusSyntax(cmdLinePos map[string]int) bool {
-	stArgs := os.Args
-	ret := true
-	if IsFlagSet("non-compliance-check") {
-		// saptune service status --non-compliance-check
-		// saptune daemon status --non-compliance-check
-		if !(stArgs[cmdLinePos["realm"]] == "service" && stArgs[cmdLinePos["cmd"]] == "status") && !(stArgs[cmdLinePos["realm"]] == "daemon" && stArgs[cmdLinePos["cmd"]] == "status") {
-			ret = false
-		}
-		if stArgs[cmdLinePos["cmdOpt"]] != "--non-compliance-check" {
-			ret = false
+// anyFlagSet checks, if at least one of the given flags is set
+func anyFlagSet(flags []string) bool {
+	for _, flag := range flags {
+		if IsFlagSet(flag) {
+			return true
 		}
 	}
-	return ret
+	return false
 }
@@ -3,6 +3,7 @@ package system
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestCliArg(t *testing.T) {
@@ -411,6 +412,20 @@ func TestChkCliSyntax(t *testing.T) {
 		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
 	}
 
+	// {"saptune", "--wait=5m", "note", "apply", "1680803"} -> ok
+	os.Args = []string{"saptune", "--wait=5m", "note", "apply", "1680803"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() || LockWait() != 5*time.Minute {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "note", "apply", "--wait=soon", "1680803"} -> wrong
+	os.Args = []string{"saptune", "note", "apply", "--wait=soon", "1680803"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
 	// {"saptune", "--show-non-compliant", "note", "verify"} -> wrong
 	os.Args = []string{"saptune", "--show-non-compliant", "note", "verify"}
 	saptArgs, saptFlags = ParseCliArgs()
@@ -425,6 +440,171 @@ func TestChkCliSyntax(t *testing.T) {
 		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
 	}
 
+	// {"saptune", "service", "takeover", "--neutralize-conflicts"} -> ok
+	os.Args = []string{"saptune", "service", "takeover", "--neutralize-conflicts"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "service", "start", "--neutralize-conflicts"} -> wrong
+	os.Args = []string{"saptune", "service", "start", "--neutralize-conflicts"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "--neutralize-conflicts", "service", "takeover"} -> wrong
+	os.Args = []string{"saptune", "--neutralize-conflicts", "service", "takeover"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "parameter", "revert", "vm.swappiness", "--note", "1410736"} -> ok
+	os.Args = []string{"saptune", "parameter", "revert", "vm.swappiness", "--note", "1410736"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+	if GetFlagVal("note") != "1410736" || CliArg(3) != "vm.swappiness" || CliArg(4) != "" {
+		t.Errorf("Test failed, got note flag '%s' and parameter '%s'", GetFlagVal("note"), CliArg(3))
+	}
+
+	// {"saptune", "parameter", "revert", "vm.swappiness", "--note=1410736"} -> ok
+	os.Args = []string{"saptune", "parameter", "revert", "vm.swappiness", "--note=1410736"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+	if GetFlagVal("note") != "1410736" {
+		t.Errorf("Test failed, got note flag '%s'", GetFlagVal("note"))
+	}
+
+	// {"saptune", "parameter", "show", "vm.swappiness", "--note", "1410736"} -> wrong
+	os.Args = []string{"saptune", "parameter", "show", "vm.swappiness", "--note", "1410736"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "configure", "--from", "/tmp/profile.yaml"} -> ok
+	os.Args = []string{"saptune", "configure", "--from", "/tmp/profile.yaml"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+	if GetFlagVal("from") != "/tmp/profile.yaml" || CliArg(2) != "" {
+		t.Errorf("Test failed, got from flag '%s' and argument '%s'", GetFlagVal("from"), CliArg(2))
+	}
+
+	// {"saptune", "configure", "--from=/tmp/profile.yaml"} -> ok
+	os.Args = []string{"saptune", "configure", "--from=/tmp/profile.yaml"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+	if GetFlagVal("from") != "/tmp/profile.yaml" {
+		t.Errorf("Test failed, got from flag '%s'", GetFlagVal("from"))
+	}
+
+	// {"saptune", "configure", "--export"} -> ok
+	os.Args = []string{"saptune", "configure", "--export"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "configure"} -> wrong
+	os.Args = []string{"saptune", "configure"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "configure", "--export", "--from", "/tmp/profile.yaml"} -> wrong
+	os.Args = []string{"saptune", "configure", "--export", "--from", "/tmp/profile.yaml"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "config", "import", "--force", "/tmp/bundle.tgz"} -> ok
+	os.Args = []string{"saptune", "config", "import", "--force", "/tmp/bundle.tgz"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "config", "export", "--dry-run", "/tmp/bundle.tgz"} -> wrong
+	os.Args = []string{"saptune", "config", "export", "--dry-run", "/tmp/bundle.tgz"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "note", "list", "--export"} -> wrong
+	os.Args = []string{"saptune", "note", "list", "--export"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "note", "create", "HANA-DB", "--from-system", "sysctl:vm.*,net.core.*", "--from-note=1980196"} -> ok
+	os.Args = []string{"saptune", "note", "create", "HANA-DB", "--from-system", "sysctl:vm.*,net.core.*", "--from-note=1980196"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+	if GetFlagVal("from-system") != "sysctl:vm.*,net.core.*" || GetFlagVal("from-note") != "1980196" || CliArg(3) != "HANA-DB" {
+		t.Errorf("Test failed, got from-system flag '%s', from-note flag '%s' and argument '%s'", GetFlagVal("from-system"), GetFlagVal("from-note"), CliArg(3))
+	}
+
+	// {"saptune", "note", "create", "--from-note", "1980196"} -> wrong
+	os.Args = []string{"saptune", "note", "create", "--from-note", "1980196"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "note", "customise", "1980196", "--set", "vm.swappiness=10", "--set=KSM=0", "--untouched", "IO_SCHEDULER_sda"} -> ok
+	os.Args = []string{"saptune", "note", "customise", "1980196", "--set", "vm.swappiness=10", "--set=KSM=0", "--untouched", "IO_SCHEDULER_sda"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+	if vals := GetFlagVals("set"); len(vals) != 2 || vals[0] != "vm.swappiness=10" || vals[1] != "KSM=0" || GetFlagVal("untouched") != "IO_SCHEDULER_sda" || len(GetFlagVals("unset")) != 0 {
+		t.Errorf("Test failed, got set flag '%v' and untouched flag '%s'", vals, GetFlagVal("untouched"))
+	}
+
+	// {"saptune", "solution", "customise", "HANA", "--set", "vm.swappiness=10"} -> wrong
+	os.Args = []string{"saptune", "solution", "customise", "HANA", "--set", "vm.swappiness=10"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "solution", "customise", "HANA", "--add-note", "1410736", "--remove-note", "1656250"} -> ok
+	os.Args = []string{"saptune", "solution", "customise", "HANA", "--add-note", "1410736", "--remove-note", "1656250"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "note", "customise", "--unset", "KSM"} -> wrong
+	os.Args = []string{"saptune", "note", "customise", "--unset", "KSM"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "note", "edit", "HANA-DB", "--from-system=vm.*"} -> wrong
+	os.Args = []string{"saptune", "note", "edit", "HANA-DB", "--from-system=vm.*"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
 	// reset CLI flags and args
 	saptArgs = []string{}
 	saptFlags = map[string]string{}
@@ -0,0 +1,490 @@
+package system
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CmdFlag describes a saptune command line flag
+type CmdFlag struct {
+	// Name is the key of the flag used by IsFlagSet and GetFlagVal
+	Name string
+	// Spellings are the names of the flag on the command line without the
+	// leading '-' or '--'. The first one is used in help and completion
+	Spellings []string
+	// Value is the placeholder of the flag value, empty for switches
+	Value string
+	// Separate flags may get their value as next command line argument
+	// (--note NoteID) instead of --note=NoteID
+	Separate bool
+	// Multi flags may be used more than once (--set A=1 --set B=2), the
+	// values are collected in the order of the command line
+	Multi bool
+	// Global flags are allowed for all commands
+	Global bool
+	// Complete is the kind of values offered by the shell completion
+	// (e.g. 'notes', 'files' or 'formats')
+	Complete string
+	// Help is the description of the flag
+	Help string
+}
+
+// CmdArg describes a positional argument of a saptune command
+type CmdArg struct {
+	// Name is the placeholder used in help and man page
+	Name     string
+	Optional bool
+	// Multi arguments may be repeated, only allowed for the last argument
+	Multi bool
+	// Values are the fixed values offered by the shell completion
+	Values []string
+	// Complete is the kind of values offered by the shell completion
+	Complete string
+}
+
+// CmdNode is a realm or a command of the saptune command tree
+type CmdNode struct {
+	Name    string
+	Aliases []string
+	Help    string
+	Args    []CmdArg
+	// Flags are the names of the flags supported by the command
+	Flags []string
+	// FlagsFirst requires the flags directly after the command, before
+	// the positional arguments
+	FlagsFirst bool
+	// Ordered flags have to be used in this order
+	Ordered []string
+	// Exclusive flags can not be used together
+	Exclusive []string
+	// FlagRequired requires at least one of the flags
+	FlagRequired bool
+	Deprecated   bool
+	// ReadOnly commands neither change the system nor the saptune
+	// configuration, so they share the saptune lock with other read-only
+	// commands
+	ReadOnly bool
+	// Hidden commands are used internally (e.g. by saptune.service) and
+	// are not offered in help and completion
+	Hidden   bool
+	Commands []*CmdNode
+}
+
+// CliFlags are all flags known by saptune
+var CliFlags = []*CmdFlag{
+	{Name: "format", Spellings: []string{"format"}, Value: "FORMAT", Global: true, Complete: "formats", Help: "output format (json, csv, yaml, markdown or html)"},
+	{Name: "help", Spellings: []string{"help", "h"}, Global: true, Help: "print the usage"},
+	{Name: "version", Spellings: []string{"version"}, Global: true, Help: "print the saptune version"},
+	{Name: "wait", Spellings: []string{"wait"}, Value: "TIMEOUT", Global: true, Help: "wait up to TIMEOUT (seconds or a duration like 5m) for a running saptune instead of failing"},
+	{Name: "force", Spellings: []string{"force"}, Help: "do not ask for confirmation"},
+	{Name: "dryrun", Spellings: []string{"dry-run", "dryrun"}, Help: "only show, what would be done"},
+	{Name: "colorscheme", Spellings: []string{"colorscheme"}, Value: "<color scheme>", Complete: "colorschemes", Help: "color scheme of the verify table"},
+	{Name: "show-non-compliant", Spellings: []string{"show-non-compliant"}, Help: "show only the non-compliant parameters"},
+	{Name: "non-compliance-check", Spellings: []string{"non-compliance-check"}, Help: "exit with 3, if the system is not compliant"},
+	{Name: "neutralize-conflicts", Spellings: []string{"neutralize-conflicts"}, Help: "neutralize the conflicting settings of other tuning tools"},
+	{Name: "note", Spellings: []string{"note"}, Value: "NoteID", Separate: true, Complete: "notes", Help: "Note tuning the parameter(s)"},
+	{Name: "from", Spellings: []string{"from"}, Value: "ProfileFile", Separate: true, Complete: "files", Help: "profile file to converge the host to"},
+	{Name: "from-system", Spellings: []string{"from-system"}, Value: "SPEC", Separate: true, Help: "take the current values of the system (e.g. sysctl:vm.*)"},
+	{Name: "from-note", Spellings: []string{"from-note"}, Value: "NoteID[:SECTION,...]", Separate: true, Complete: "notes", Help: "copy the (sections of the) Note"},
+	{Name: "set", Spellings: []string{"set"}, Value: "PARAMETER=VALUE", Separate: true, Multi: true, Help: "set the parameter to the value"},
+	{Name: "untouched", Spellings: []string{"untouched"}, Value: "PARAMETER", Separate: true, Multi: true, Help: "leave the parameter untouched"},
+	{Name: "unset", Spellings: []string{"unset"}, Value: "PARAMETER", Separate: true, Multi: true, Help: "remove the customisation of the parameter"},
+	{Name: "add-note", Spellings: []string{"add-note"}, Value: "NoteID", Separate: true, Multi: true, Complete: "notes", Help: "add the Note to the Solution"},
+	{Name: "remove-note", Spellings: []string{"remove-note"}, Value: "NoteID", Separate: true, Multi: true, Complete: "notes", Help: "remove the Note from the Solution"},
+	{Name: "export", Spellings: []string{"export"}, Help: "export the current configuration as profile"},
+	{Name: "section", Spellings: []string{"section"}, Value: "SECTION[,...]", Separate: true, Complete: "sections", Help: "verify only the parameters of the sections"},
+	{Name: "param", Spellings: []string{"param"}, Value: "PATTERN[,...]", Separate: true, Help: "verify only the parameters matching the patterns (e.g. 'vm.*')"},
+	{Name: "device", Spellings: []string{"device"}, Value: "PATTERN[,...]", Separate: true, Help: "verify only the block device parameters of the matching devices (e.g. 'sd*')"},
+	{Name: "summary", Spellings: []string{"summary"}, Help: "print the compliance summary instead of the verify table"},
+	{Name: "compliant", Spellings: []string{"compliant"}, Value: "yes|no|na|info", Complete: "compliance", Help: "verify only the parameters with this compliance"},
+	{Name: "fail-on", Spellings: []string{"fail-on"}, Value: "critical|recommended|informational", Complete: "severities", Help: "fail only for non-compliant parameters of this or a higher severity"},
+}
+
+// arguments and flags used by several commands
+var (
+	verifyFlags    = []string{"colorscheme", "show-non-compliant", "note", "section", "param", "device", "compliant", "summary", "fail-on"}
+	argNoteID      = CmdArg{Name: "NoteID", Complete: "notes"}
+	argOptNoteID   = CmdArg{Name: "NoteID", Optional: true, Complete: "notes"}
+	argSolution    = CmdArg{Name: "SolutionName", Complete: "solutions"}
+	argOptSolution = CmdArg{Name: "SolutionName", Optional: true, Complete: "solutions"}
+	argOptBaseline = CmdArg{Name: "BaselineName", Optional: true, Complete: "baselines"}
+	argStaging     = CmdArg{Name: "NoteID|SolutionID|all", Optional: true, Multi: true, Values: []string{"all"}, Complete: "staging"}
+)
+
+// CmdTree is the saptune command tree: realm -> command -> arguments and
+// flags. It drives the command line syntax check, the command specific
+// help and the generated shell completion and man page synopsis
+var CmdTree = []*CmdNode{
+	{Name: "daemon", Help: "control the saptune daemon", Deprecated: true, Commands: []*CmdNode{
+		{Name: "start", Help: "start saptune.service"},
+		{Name: "status", Help: "show the status of saptune", Flags: []string{"non-compliance-check"}, FlagsFirst: true, ReadOnly: true},
+		{Name: "stop", Help: "stop saptune.service"},
+	}},
+	{Name: "service", Help: "control saptune.service", Commands: []*CmdNode{
+		{Name: "start", Help: "start saptune.service"},
+		{Name: "stop", Help: "stop saptune.service"},
+		{Name: "restart", Help: "restart saptune.service"},
+		{Name: "takeover", Help: "stop and disable conflicting services, enable and start saptune.service", Flags: []string{"neutralize-conflicts"}, FlagsFirst: true},
+		{Name: "enable", Help: "enable saptune.service"},
+		{Name: "disable", Help: "disable saptune.service"},
+		{Name: "enablestart", Help: "enable and start saptune.service"},
+		{Name: "disablestop", Help: "disable and stop saptune.service"},
+		{Name: "status", Help: "show the status of saptune", Flags: []string{"non-compliance-check"}, FlagsFirst: true, ReadOnly: true},
+		{Name: "apply", Hidden: true},
+		{Name: "revert", Hidden: true},
+		{Name: "reload", Hidden: true},
+		{Name: "driftcheck", Hidden: true},
+	}},
+	{Name: "note", Help: "tune the system according to SAP and SUSE Notes", Commands: []*CmdNode{
+		{Name: "list", Help: "list all available Notes", ReadOnly: true},
+		{Name: "revertall", Help: "revert all applied Notes"},
+		{Name: "enabled", Help: "print the enabled Notes", ReadOnly: true},
+		{Name: "applied", Help: "print the applied Notes", ReadOnly: true},
+		{Name: "apply", Help: "apply the Note", Args: []CmdArg{argNoteID}},
+		{Name: "simulate", Help: "show the changes the Note would apply", Args: []CmdArg{argNoteID}, ReadOnly: true},
+		{Name: "customise", Aliases: []string{"customize"}, Help: "customise the Note", Args: []CmdArg{argNoteID}, Flags: []string{"set", "untouched", "unset"}},
+		{Name: "create", Help: "create a new Note", Args: []CmdArg{argNoteID}, Flags: []string{"from-system", "from-note"}},
+		{Name: "edit", Help: "edit the Note", Args: []CmdArg{argNoteID}},
+		{Name: "revert", Help: "revert the Note", Args: []CmdArg{argNoteID}},
+		{Name: "show", Help: "print the content of the Note", Args: []CmdArg{argNoteID}, ReadOnly: true},
+		{Name: "delete", Help: "delete the Note", Args: []CmdArg{argNoteID}},
+		{Name: "verify", Help: "verify the tuning of all or a single Note", Args: []CmdArg{argOptNoteID}, Flags: verifyFlags, FlagsFirst: true, Ordered: []string{"colorscheme", "show-non-compliant"}, ReadOnly: true},
+		{Name: "rename", Help: "rename the Note", Args: []CmdArg{argNoteID, {Name: "newNoteID"}}},
+		{Name: "lint", Help: "check the syntax of all or a single Note definition", Args: []CmdArg{{Name: "NoteID|NoteFile", Optional: true, Complete: "notes"}}, ReadOnly: true},
+	}},
+	{Name: "solution", Help: "tune the system for all Notes of a SAP solution", Commands: []*CmdNode{
+		{Name: "list", Help: "list all available Solutions", ReadOnly: true},
+		{Name: "enabled", Help: "print the enabled Solution", ReadOnly: true},
+		{Name: "applied", Help: "print the applied Solution", ReadOnly: true},
+		{Name: "verify", Help: "verify the tuning of all or a single Solution", Args: []CmdArg{argOptSolution}, Flags: verifyFlags, FlagsFirst: true, Ordered: []string{"colorscheme", "show-non-compliant"}, ReadOnly: true},
+		{Name: "apply", Help: "apply the Solution", Args: []CmdArg{argSolution}},
+		{Name: "simulate", Help: "show the changes the Solution would apply", Args: []CmdArg{argSolution}, ReadOnly: true},
+		{Name: "customise", Aliases: []string{"customize"}, Help: "customise the Solution", Args: []CmdArg{argSolution}, Flags: []string{"add-note", "remove-note"}},
+		{Name: "create", Help: "create a new Solution", Args: []CmdArg{argSolution}},
+		{Name: "edit", Help: "edit the Solution", Args: []CmdArg{argSolution}},
+		{Name: "revert", Help: "revert the Solution", Args: []CmdArg{argSolution}},
+		{Name: "show", Help: "print the content of the Solution", Args: []CmdArg{argSolution}, ReadOnly: true},
+		{Name: "delete", Help: "delete the Solution", Args: []CmdArg{argSolution}},
+		{Name: "rename", Help: "rename the Solution", Args: []CmdArg{argSolution, {Name: "newSolutionName"}}},
+	}},
+	{Name: "staging", Help: "control the staging of Note and Solution updates", Commands: []*CmdNode{
+		{Name: "status", Help: "show the staging status", ReadOnly: true},
+		{Name: "enable", Help: "enable staging"},
+		{Name: "disable", Help: "disable staging"},
+		{Name: "is-enabled", Help: "check, if staging is enabled", ReadOnly: true},
+		{Name: "list", Help: "list the staged Notes and Solutions", ReadOnly: true},
+		{Name: "diff", Help: "show the differences of the staged Notes and Solutions", Args: []CmdArg{argStaging}, ReadOnly: true},
+		{Name: "analysis", Help: "analyse the release of the staged Notes and Solutions", Args: []CmdArg{argStaging}, ReadOnly: true},
+		{Name: "release", Help: "release the staged Notes and Solutions", Args: []CmdArg{argStaging}, Flags: []string{"force", "dryrun"}, Exclusive: []string{"force", "dryrun"}, FlagsFirst: true},
+	}},
+	{Name: "parameter", Help: "inspect and revert single parameters", Commands: []*CmdNode{
+		{Name: "list", Help: "list the parameters tuned by the applied Notes", ReadOnly: true},
+		{Name: "show", Help: "show the Notes tuning the parameter", Args: []CmdArg{{Name: "Parameter"}}, ReadOnly: true},
+		{Name: "revert", Help: "revert the parameter tuned by the Note", Args: []CmdArg{{Name: "Parameter"}}, Flags: []string{"note"}, FlagRequired: true},
+	}},
+	{Name: "revert", Help: "revert all parameters", Commands: []*CmdNode{
+		{Name: "all", Help: "revert all Notes and Solutions"},
+	}},
+	{Name: "configure", Help: "converge the host to a profile file or export the current configuration", Flags: []string{"from", "export"}, Exclusive: []string{"from", "export"}, FlagRequired: true},
+	{Name: "config", Help: "export or import the saptune configuration", Commands: []*CmdNode{
+		{Name: "export", Help: "export the saptune configuration", Args: []CmdArg{{Name: "BundleFile", Complete: "files"}}, ReadOnly: true},
+		{Name: "import", Help: "import the saptune configuration", Args: []CmdArg{{Name: "BundleFile", Complete: "files"}}, Flags: []string{"force", "dryrun"}, Exclusive: []string{"force", "dryrun"}, FlagsFirst: true},
+	}},
+	{Name: "lock", Help: "handle the saptune lock file", Commands: []*CmdNode{
+		{Name: "remove", Help: "remove the pending lock file"},
+	}},
+	{Name: "check", Help: "call the external script '/usr/sbin/saptune_check'"},
+	{Name: "status", Help: "print the current saptune status", Flags: []string{"non-compliance-check"}, FlagsFirst: true, ReadOnly: true},
+	{Name: "verify", Help: "verify the tuning of all enabled Notes and Solutions", Flags: verifyFlags, FlagsFirst: true, Ordered: []string{"colorscheme", "show-non-compliant"}, ReadOnly: true},
+	{Name: "baseline", Help: "record and check a known-good state of the tuned parameters", Commands: []*CmdNode{
+		{Name: "save", Help: "save the current values of the parameters of the enabled Notes", Args: []CmdArg{argOptBaseline}},
+		{Name: "check", Help: "report the parameters changed since the baseline was saved", Args: []CmdArg{argOptBaseline}, ReadOnly: true},
+	}},
+	{Name: "compare", Help: "compare the verify snapshots of two hosts", Args: []CmdArg{{Name: "SnapshotA", Complete: "files"}, {Name: "SnapshotB", Complete: "files"}}},
+	{Name: "generate", Help: "generate shell completion or the man page synopsis", Commands: []*CmdNode{
+		{Name: "completion", Help: "print the shell completion script", Args: []CmdArg{{Name: "bash|zsh|fish", Values: []string{"bash", "zsh", "fish"}}}},
+		{Name: "synopsis", Help: "print the SYNOPSIS section of the man page saptune(8)"},
+	}},
+	{Name: "version", Help: "print the current saptune version"},
+	{Name: "help", Help: "print the usage"},
+}
+
+// LookupFlag returns the flag of a command line spelling (e.g. 'dry-run'
+// for '--dry-run') or nil, if the flag is unknown
+func LookupFlag(spelling string) *CmdFlag {
+	for _, flag := range CliFlags {
+		for _, name := range flag.Spellings {
+			if name == spelling {
+				return flag
+			}
+		}
+	}
+	return nil
+}
+
+// cliFlag returns the flag with the key 'name'
+func cliFlag(name string) *CmdFlag {
+	for _, flag := range CliFlags {
+		if flag.Name == name {
+			return flag
+		}
+	}
+	return nil
+}
+
+// matches checks, if 'word' is the name or an alias of the node
+func (node *CmdNode) matches(word string) bool {
+	if node.Name == word {
+		return true
+	}
+	for _, alias := range node.Aliases {
+		if alias == word {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFlag checks, if the node supports the flag 'name'
+func (node *CmdNode) hasFlag(name string) bool {
+	return flagIndex(node.Flags, name) >= 0
+}
+
+// flagIndex returns the position of the flag 'name' in 'flags' or -1
+func flagIndex(flags []string, name string) int {
+	for i, flag := range flags {
+		if flag == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindCommand walks the command tree along the positional command line
+// arguments (without the program name) and returns the deepest matching
+// node together with its depth (1 for a realm, 2 for a command).
+// nil and 0 are returned, if the realm is unknown
+func FindCommand(args []string) (*CmdNode, int) {
+	var found *CmdNode
+	depth := 0
+	nodes := CmdTree
+	for _, arg := range args {
+		var next *CmdNode
+		for _, node := range nodes {
+			if node.matches(arg) {
+				next = node
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		found = next
+		depth++
+		nodes = next.Commands
+	}
+	return found, depth
+}
+
+// CmdLockMode returns the mode of the saptune lock needed by the command
+// (e.g. 'note verify 1680803'). Only read-only commands share the lock
+func CmdLockMode(args []string) int {
+	if node, _ := FindCommand(args); node != nil && node.ReadOnly {
+		return LockShared
+	}
+	return LockExclusive
+}
+
+// VisibleCommands returns the commands of a node, which are not hidden
+func (node *CmdNode) VisibleCommands() []*CmdNode {
+	cmds := []*CmdNode{}
+	for _, cmd := range node.Commands {
+		if !cmd.Hidden {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+// FlagUsage returns the usage of a flag, e.g. '--note NoteID'
+func (flag *CmdFlag) FlagUsage() string {
+	switch {
+	case flag.Value == "":
+		return "--" + flag.Spellings[0]
+	case flag.Separate:
+		return "--" + flag.Spellings[0] + " " + flag.Value
+	}
+	return "--" + flag.Spellings[0] + "=" + flag.Value
+}
+
+// ArgsUsage returns the usage of the flags and arguments of a node, e.g.
+// '[--colorscheme=<color scheme>] [--show-non-compliant] [NoteID]'
+func (node *CmdNode) ArgsUsage() string {
+	flagParts := []string{}
+	done := map[string]bool{}
+	for _, name := range node.Flags {
+		if done[name] {
+			continue
+		}
+		flag := cliFlag(name)
+		usage := flag.FlagUsage()
+		if flagIndex(node.Exclusive, name) >= 0 {
+			alternatives := []string{}
+			for _, exName := range node.Exclusive {
+				alternatives = append(alternatives, cliFlag(exName).FlagUsage())
+				done[exName] = true
+			}
+			usage = strings.Join(alternatives, "|")
+		}
+		if flag.Multi {
+			usage = "[" + usage + "]..."
+		} else if !node.FlagRequired || len(node.Flags) > 1 {
+			usage = "[" + usage + "]"
+		}
+		flagParts = append(flagParts, usage)
+	}
+	argParts := []string{}
+	for _, arg := range node.Args {
+		usage := arg.Name
+		if arg.Optional {
+			usage = "[" + usage + "]"
+		}
+		if arg.Multi {
+			usage = usage + "..."
+		}
+		argParts = append(argParts, usage)
+	}
+	parts := flagParts
+	if !node.FlagsFirst {
+		parts = append(argParts, flagParts...)
+	} else {
+		parts = append(parts, argParts...)
+	}
+	return strings.Join(parts, " ")
+}
+
+// CmdUsage returns the command specific help of the node found by the
+// positional command line arguments (without the program name).
+// An empty string is returned, if the realm is unknown
+func CmdUsage(args []string) string {
+	node, depth := FindCommand(args)
+	if node == nil {
+		return ""
+	}
+	cmdLine := "saptune " + strings.Join(args[:depth], " ")
+	usage := &strings.Builder{}
+	if cmds := node.VisibleCommands(); len(cmds) > 0 {
+		fmt.Fprintf(usage, "Usage: %s COMMAND\n%s\n", cmdLine, upperFirst(node.Help))
+		if node.Deprecated {
+			fmt.Fprintf(usage, "ATTENTION: deprecated\n")
+		}
+		fmt.Fprintf(usage, "Commands:\n")
+		width := 0
+		for _, cmd := range cmds {
+			if len(cmd.Name) > width {
+				width = len(cmd.Name)
+			}
+		}
+		for _, cmd := range cmds {
+			fmt.Fprintf(usage, "  %-*s  %s\n", width, cmd.Name, cmd.Help)
+		}
+		return usage.String()
+	}
+	fmt.Fprintf(usage, "Usage: %s", cmdLine)
+	if argsUsage := node.ArgsUsage(); argsUsage != "" {
+		fmt.Fprintf(usage, " %s", argsUsage)
+	}
+	fmt.Fprintf(usage, "\n%s\n", upperFirst(node.Help))
+	if len(node.Flags) > 0 {
+		fmt.Fprintf(usage, "Options:\n")
+		width := 0
+		for _, name := range node.Flags {
+			if l := len(cliFlag(name).FlagUsage()); l > width {
+				width = l
+			}
+		}
+		for _, name := range node.Flags {
+			flag := cliFlag(name)
+			fmt.Fprintf(usage, "  %-*s  %s\n", width, flag.FlagUsage(), flag.Help)
+		}
+	}
+	return usage.String()
+}
+
+// UsageLines returns the usage of the visible commands of a realm without
+// the leading 'saptune REALM'. Commands with the same flags and arguments
+// are grouped, e.g. '[ list | enabled | applied ]'.
+// For a realm without commands the usage of its flags and arguments is
+// returned
+func (node *CmdNode) UsageLines() []string {
+	cmds := node.VisibleCommands()
+	if len(cmds) == 0 {
+		return []string{node.ArgsUsage()}
+	}
+	order := []string{}
+	groups := map[string][]string{}
+	for _, cmd := range cmds {
+		usage := cmd.ArgsUsage()
+		if _, ok := groups[usage]; !ok {
+			order = append(order, usage)
+		}
+		groups[usage] = append(groups[usage], cmd.Name)
+	}
+	lines := []string{}
+	for _, usage := range order {
+		line := groups[usage][0]
+		if len(groups[usage]) > 1 {
+			line = "[ " + strings.Join(groups[usage], " | ") + " ]"
+		}
+		if usage != "" {
+			line = line + " " + usage
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// MainUsage returns the usage of saptune printed by 'saptune help'
+func MainUsage() string {
+	usage := &strings.Builder{}
+	fmt.Fprintf(usage, "saptune: Comprehensive system optimisation management for SAP solutions.\n")
+	for _, realm := range CmdTree {
+		if realm.Hidden {
+			continue
+		}
+		deprecated := ""
+		if realm.Deprecated {
+			deprecated = "  ATTENTION: deprecated"
+		}
+		fmt.Fprintf(usage, "%s:\n", upperFirst(realm.Help))
+		for _, line := range realm.UsageLines() {
+			cmdLine := "saptune " + realm.Name
+			if line != "" {
+				cmdLine = cmdLine + " " + line
+			}
+			fmt.Fprintf(usage, "  %s%s\n", cmdLine, deprecated)
+		}
+	}
+	fmt.Fprintf(usage, "Global options:\n")
+	width := 0
+	for _, flag := range CliFlags {
+		if l := len(flag.FlagUsage()); flag.Global && l > width {
+			width = l
+		}
+	}
+	for _, flag := range CliFlags {
+		if flag.Global {
+			fmt.Fprintf(usage, "  %-*s  %s\n", width, flag.FlagUsage(), flag.Help)
+		}
+	}
+	fmt.Fprintf(usage, "Print the usage of a realm or command:\n  saptune REALM [COMMAND] --help\n")
+	return usage.String()
+}
+
+// upperFirst returns the string with an upper case first letter
+func upperFirst(str string) string {
+	if str == "" {
+		return str
+	}
+	return strings.ToUpper(str[:1]) + str[1:]
+}
@@ -0,0 +1,183 @@
+package system
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFindCommand(t *testing.T) {
+	tests := []struct {
+		args  []string
+		name  string
+		depth int
+	}{
+		{[]string{"note", "verify", "1680803"}, "verify", 2},
+		{[]string{"note", "customize", "1680803"}, "customise", 2},
+		{[]string{"staging"}, "staging", 1},
+		{[]string{"staging", "unknown"}, "staging", 1},
+		{[]string{"verify"}, "verify", 1},
+		{[]string{"status"}, "status", 1},
+		{[]string{"unknown", "verify"}, "", 0},
+		{[]string{}, "", 0},
+	}
+	for _, tt := range tests {
+		node, depth := FindCommand(tt.args)
+		name := ""
+		if node != nil {
+			name = node.Name
+		}
+		if name != tt.name || depth != tt.depth {
+			t.Errorf("'%v': got '%s', '%d', expected '%s', '%d'", tt.args, name, depth, tt.name, tt.depth)
+		}
+	}
+}
+
+func TestCmdLockMode(t *testing.T) {
+	for _, args := range [][]string{{"note", "verify", "1680803"}, {"solution", "list"}, {"status"}, {"staging", "diff", "all"}, {"baseline", "check"}} {
+		if mode := CmdLockMode(args); mode != LockShared {
+			t.Errorf("'%v': expected shared lock, got '%d'", args, mode)
+		}
+	}
+	for _, args := range [][]string{{"note", "apply", "1680803"}, {"note", "customise", "1680803"}, {"staging", "release"}, {"revert", "all"}, {"service", "apply"}, {"note"}, {"unknown"}} {
+		if mode := CmdLockMode(args); mode != LockExclusive {
+			t.Errorf("'%v': expected exclusive lock, got '%d'", args, mode)
+		}
+	}
+}
+
+func TestLookupFlag(t *testing.T) {
+	for spelling, name := range map[string]string{"dry-run": "dryrun", "dryrun": "dryrun", "h": "help", "format": "format", "unknown": ""} {
+		flag := LookupFlag(spelling)
+		if (flag == nil && name != "") || (flag != nil && flag.Name != name) {
+			t.Errorf("'%s': got '%+v', expected '%s'", spelling, flag, name)
+		}
+	}
+}
+
+func TestArgsUsage(t *testing.T) {
+	tests := []struct {
+		args  []string
+		usage string
+	}{
+		{[]string{"note", "verify"}, "[--colorscheme=<color scheme>] [--show-non-compliant] [--note NoteID] [--section SECTION[,...]] [--param PATTERN[,...]] [--device PATTERN[,...]] [--compliant=yes|no|na|info] [--summary] [--fail-on=critical|recommended|informational] [NoteID]"},
+		{[]string{"note", "customise"}, "NoteID [--set PARAMETER=VALUE]... [--untouched PARAMETER]... [--unset PARAMETER]..."},
+		{[]string{"staging", "release"}, "[--force|--dry-run] [NoteID|SolutionID|all]..."},
+		{[]string{"parameter", "revert"}, "Parameter --note NoteID"},
+		{[]string{"configure"}, "[--from ProfileFile|--export]"},
+		{[]string{"note", "list"}, ""},
+	}
+	for _, tt := range tests {
+		node, _ := FindCommand(tt.args)
+		if usage := node.ArgsUsage(); usage != tt.usage {
+			t.Errorf("'%v': got '%s', expected '%s'", tt.args, usage, tt.usage)
+		}
+	}
+}
+
+func TestCmdUsage(t *testing.T) {
+	usage := CmdUsage([]string{"note", "lint"})
+	if !strings.HasPrefix(usage, "Usage: saptune note lint [NoteID|NoteFile]\n") {
+		t.Errorf("wrong usage '%s'", usage)
+	}
+	usage = CmdUsage([]string{"note", "verify"})
+	if !strings.HasPrefix(usage, "Usage: saptune note verify [--colorscheme=<color scheme>] [--show-non-compliant] [--note NoteID]") {
+		t.Errorf("wrong usage '%s'", usage)
+	}
+	if !strings.Contains(usage, "Options:\n") || !strings.Contains(usage, "--show-non-compliant") {
+		t.Errorf("missing options in '%s'", usage)
+	}
+	usage = CmdUsage([]string{"daemon"})
+	if !strings.HasPrefix(usage, "Usage: saptune daemon COMMAND\n") || !strings.Contains(usage, "ATTENTION: deprecated\nCommands:\n") {
+		t.Errorf("wrong usage '%s'", usage)
+	}
+	// hidden commands are not listed
+	usage = CmdUsage([]string{"service"})
+	if strings.Contains(usage, "driftcheck") || !strings.Contains(usage, "takeover") {
+		t.Errorf("wrong commands in '%s'", usage)
+	}
+	if usage := CmdUsage([]string{"unknown"}); usage != "" {
+		t.Errorf("expected empty usage, got '%s'", usage)
+	}
+}
+
+func TestUsageLines(t *testing.T) {
+	note, _ := FindCommand([]string{"note"})
+	lines := note.UsageLines()
+	if len(lines) == 0 || lines[0] != "[ list | revertall | enabled | applied ]" {
+		t.Errorf("wrong usage lines '%v'", lines)
+	}
+	configure, _ := FindCommand([]string{"configure"})
+	if lines := configure.UsageLines(); len(lines) != 1 || lines[0] != "[--from ProfileFile|--export]" {
+		t.Errorf("wrong usage lines '%v'", lines)
+	}
+}
+
+func TestMainUsage(t *testing.T) {
+	usage := MainUsage()
+	for _, line := range []string{
+		"saptune: Comprehensive system optimisation management for SAP solutions.\n",
+		"  saptune daemon [ start | stop ]  ATTENTION: deprecated\n",
+		"Tune the system according to SAP and SUSE Notes:\n  saptune note [ list | revertall | enabled | applied ]\n",
+		"  saptune solution verify [--colorscheme=<color scheme>] [--show-non-compliant] [--note NoteID]",
+		"  saptune staging release [--force|--dry-run] [NoteID|SolutionID|all]...\n",
+		"  saptune parameter revert Parameter --note NoteID\n",
+		"  saptune version\n",
+		"Global options:\n",
+		"  --wait=TIMEOUT   wait up to TIMEOUT",
+		"  saptune REALM [COMMAND] --help\n",
+	} {
+		if !strings.Contains(usage, line) {
+			t.Errorf("missing '%s' in '%s'", line, usage)
+		}
+	}
+	// hidden commands and non global flags are not listed
+	for _, word := range []string{"driftcheck", "service apply", "--dry-run  "} {
+		if strings.Contains(usage, word) {
+			t.Errorf("unexpected '%s' in '%s'", word, usage)
+		}
+	}
+}
+
+func TestCmdTreeFlags(t *testing.T) {
+	// all flags used by the command tree need to be defined
+	var chkNodes func(nodes []*CmdNode)
+	chkNodes = func(nodes []*CmdNode) {
+		for _, node := range nodes {
+			for _, name := range append(node.Flags, node.Exclusive...) {
+				if cliFlag(name) == nil {
+					t.Errorf("node '%s': unknown flag '%s'", node.Name, name)
+				}
+			}
+			chkNodes(node.Commands)
+		}
+	}
+	chkNodes(CmdTree)
+
+	// help of a command with a required flag
+	os.Args = []string{"saptune", "configure", "--help"}
+	RereadArgs()
+	if !ChkCliSyntax() {
+		t.Error("'saptune configure --help' rejected")
+	}
+	os.Args = []string{"saptune", "configure"}
+	RereadArgs()
+	if ChkCliSyntax() {
+		t.Error("'saptune configure' without flag accepted")
+	}
+
+	// verify filters can be combined with the ordered flags
+	for args, valid := range map[string]bool{
+		"note verify --section block --show-non-compliant 1680803":                true,
+		"solution verify --colorscheme=full-green-zebra --compliant=no HANA":      true,
+		"note verify --show-non-compliant --colorscheme=full-green-zebra 1680803": false,
+		"note verify 1680803 --param vm.swappiness":                               false,
+		"note simulate --device sda 1680803":                                      false,
+	} {
+		os.Args = append([]string{"saptune"}, strings.Fields(args)...)
+		RereadArgs()
+		if ChkCliSyntax() != valid {
+			t.Errorf("'%s': expected syntax check '%v'", args, valid)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package system
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Conflict describes a component, which tunes the same parameters as saptune
+type Conflict struct {
+	Component string   // name of the competing component
+	Source    string   // service or configuration file of the component
+	Params    []string // saptune parameters affected by the component
+	Tunes     string   // tuning of a component without saptune parameters
+	Service   string   // systemd service to disable to neutralise the conflict
+}
+
+// conflictService describes a known service competing with saptune.
+// Parameters ending with '*' are prefixes (e.g. block device parameters).
+// Services without parameters tune the system beside the saptune parameters
+// in a way interfering with the tuning of saptune, described by 'tunes'
+type conflictService struct {
+	service   string
+	component string
+	params    []string
+	tunes     string
+}
+
+// conflictServices are the known services, which tune parameters saptune
+// manages too
+var conflictServices = []conflictService{
+	{"irqbalance.service", "irqbalance", nil, "IRQ affinity"},
+	{"ksmtuned.service", "ksmtuned", []string{"KSM"}, ""},
+}
+
+// sysctlFileOwners maps patterns in the name of sysctl configuration files
+// to the component, which most likely has installed the file.
+// The first matching pattern wins, all other files belong to systemd-sysctl
+var sysctlFileOwners = []struct {
+	pattern   string
+	component string
+}{
+	{"cloud", "cloud-init"},
+	{"azure", "Azure Linux Agent"},
+	{"waagent", "Azure Linux Agent"},
+	{"amazon", "AWS image tools"},
+	{"aws", "AWS image tools"},
+	{"ec2", "AWS image tools"},
+	{"google", "Google guest environment"},
+	{"gce", "Google guest environment"},
+}
+
+// tunedProfileDirs are the locations of the tuned profiles, the first one
+// wins
+var tunedProfileDirs = []string{"/etc/tuned", "/usr/lib/tuned"}
+
+// tunedPluginParams maps the options of the tuned plugins to the related
+// saptune parameter names
+var tunedPluginParams = map[string]map[string]string{
+	"vm":   {"transparent_hugepages": "THP", "transparent_hugepage": "THP"},
+	"cpu":  {"governor": "governor", "energy_perf_bias": "energy_perf_bias", "force_latency": "force_latency"},
+	"disk": {"elevator": "IO_SCHEDULER_*", "readahead": "READ_AHEAD_KB_*"},
+}
+
+// ConflictInventory checks the system for components, which tune the same
+// parameters as saptune. 'managed' contains the parameters of the enabled
+// Notes with their section names as values.
+func ConflictInventory(managed map[string]string) []Conflict {
+	conflicts := []Conflict{}
+	if len(managed) == 0 {
+		return conflicts
+	}
+	conflicts = append(conflicts, serviceConflicts(managed)...)
+	conflicts = append(conflicts, tunedConflicts(managed)...)
+	conflicts = append(conflicts, sysctlConflicts(managed, getAllSysctlFiles())...)
+	return conflicts
+}
+
+// serviceConflicts checks the known competing services
+func serviceConflicts(managed map[string]string) []Conflict {
+	conflicts := []Conflict{}
+	if len(services) == 0 {
+		services = GetAvailServices()
+	}
+	for _, cserv := range conflictServices {
+		if _, ok := services[cserv.service]; !ok {
+			continue
+		}
+		params := matchManagedParams(cserv.params, managed)
+		if len(params) == 0 && cserv.tunes == "" {
+			continue
+		}
+		active, _ := SystemctlIsRunning(cserv.service)
+		enabled, _ := SystemctlIsEnabled(cserv.service)
+		if !active && !enabled {
+			continue
+		}
+		conflicts = append(conflicts, Conflict{Component: cserv.component, Source: cserv.service, Params: params, Tunes: cserv.tunes, Service: cserv.service})
+	}
+	return conflicts
+}
+
+// tunedConflicts checks the plugins of the active tuned profile, if tuned
+// is running or enabled
+func tunedConflicts(managed map[string]string) []Conflict {
+	conflicts := []Conflict{}
+	active, _ := SystemctlIsRunning("tuned.service")
+	enabled, _ := SystemctlIsEnabled("tuned.service")
+	if !active && !enabled {
+		return conflicts
+	}
+	profile := GetTunedProfile()
+	if profile == "" {
+		return conflicts
+	}
+	for _, dir := range tunedProfileDirs {
+		conf := path.Join(dir, profile, "tuned.conf")
+		if _, err := os.Stat(conf); err != nil {
+			continue
+		}
+		for plugin, keys := range tunedPluginKeys(conf) {
+			params := matchManagedParams(keys, managed)
+			if len(params) == 0 {
+				continue
+			}
+			conflicts = append(conflicts, Conflict{Component: fmt.Sprintf("tuned plugin '%s' (profile '%s')", plugin, profile), Source: conf, Params: params, Service: "tuned.service"})
+		}
+		break
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Component < conflicts[j].Component })
+	return conflicts
+}
+
+// tunedPluginKeys returns per plugin section of a tuned profile the names of
+// the related saptune parameters
+func tunedPluginKeys(conf string) map[string][]string {
+	pluginKeys := make(map[string][]string)
+	content, err := ioutil.ReadFile(conf)
+	if err != nil {
+		return pluginKeys
+	}
+	section := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		fields := strings.SplitN(line, "=", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(fields[0])
+		if section == "sysctl" {
+			pluginKeys[section] = append(pluginKeys[section], key)
+			continue
+		}
+		if param, ok := tunedPluginParams[section][key]; ok {
+			pluginKeys[section] = append(pluginKeys[section], param)
+		}
+	}
+	return pluginKeys
+}
+
+// sysctlConflicts checks, if sysctl parameters of the enabled Notes are
+// additionally set in the given sysctl configuration files
+func sysctlConflicts(managed map[string]string, files []string) []Conflict {
+	conflicts := []Conflict{}
+	seen := make(map[string]bool)
+	for _, file := range files {
+		// /etc/sysctl.d/99-sysctl.conf is normally a link to
+		// /etc/sysctl.conf, so report each file only once
+		if realFile, err := filepath.EvalSymlinks(file); err == nil {
+			if seen[realFile] {
+				continue
+			}
+			seen[realFile] = true
+		}
+		entries, err := parseSysctlConfFile(file)
+		if err != nil {
+			continue
+		}
+		params := []string{}
+		for key := range entries {
+			if managed[key] == "sysctl" {
+				params = append(params, key)
+			}
+		}
+		if len(params) == 0 {
+			continue
+		}
+		sort.Strings(params)
+		conflicts = append(conflicts, Conflict{Component: sysctlFileOwner(file), Source: file, Params: params})
+	}
+	return conflicts
+}
+
+// sysctlFileOwner returns the component, which most likely has installed
+// the sysctl configuration file
+func sysctlFileOwner(file string) string {
+	name := strings.ToLower(path.Base(file))
+	for _, owner := range sysctlFileOwners {
+		if strings.Contains(name, owner.pattern) {
+			return owner.component
+		}
+	}
+	return "systemd-sysctl"
+}
+
+// matchManagedParams returns the sorted list of the managed parameters,
+// which match the given parameter names or prefixes
+func matchManagedParams(params []string, managed map[string]string) []string {
+	matches := []string{}
+	for key := range managed {
+		for _, param := range params {
+			if key == param || (strings.HasSuffix(param, "*") && strings.HasPrefix(key, strings.TrimSuffix(param, "*"))) {
+				matches = append(matches, key)
+				break
+			}
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
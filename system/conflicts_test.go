@@ -0,0 +1,113 @@
+package system
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func TestConflictInventory(t *testing.T) {
+	if len(ConflictInventory(map[string]string{})) != 0 {
+		t.Error("expected no conflicts without managed parameters")
+	}
+}
+
+// conflictServiceManager reports the given active states of the units
+type conflictServiceManager struct {
+	ExecServiceManager
+	states map[string]string
+}
+
+func (sm conflictServiceManager) ActiveState(unit string) (string, error) {
+	return sm.states[unit], nil
+}
+
+func (sm conflictServiceManager) UnitFileState(unit string) (string, error) {
+	return "disabled", nil
+}
+
+func TestServiceConflicts(t *testing.T) {
+	oldSM := serviceManager
+	oldServices := services
+	defer func() { serviceManager, services = oldSM, oldServices }()
+	services = map[string]string{"irqbalance.service": "irqbalance.service", "ksmtuned.service": "ksmtuned.service"}
+	serviceManager = conflictServiceManager{states: map[string]string{"irqbalance.service": "active", "ksmtuned.service": "active"}}
+
+	// irqbalance does not tune saptune parameters, but is reported
+	exp := []Conflict{
+		{Component: "irqbalance", Source: "irqbalance.service", Params: []string{}, Tunes: "IRQ affinity", Service: "irqbalance.service"},
+		{Component: "ksmtuned", Source: "ksmtuned.service", Params: []string{"KSM"}, Service: "ksmtuned.service"},
+	}
+	if conflicts := serviceConflicts(map[string]string{"KSM": "vm", "vm.swappiness": "sysctl"}); !reflect.DeepEqual(conflicts, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'", conflicts, exp)
+	}
+	if conflicts := serviceConflicts(map[string]string{"vm.swappiness": "sysctl"}); !reflect.DeepEqual(conflicts, exp[:1]) {
+		t.Errorf("got: '%+v', expected: '%+v'", conflicts, exp[:1])
+	}
+	serviceManager = conflictServiceManager{states: map[string]string{"irqbalance.service": "inactive", "ksmtuned.service": "inactive"}}
+	if conflicts := serviceConflicts(map[string]string{"KSM": "vm"}); len(conflicts) != 0 {
+		t.Errorf("got: '%+v', expected no conflicts of inactive services", conflicts)
+	}
+}
+
+func TestSysctlConflicts(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "saptune-conflicts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	cloudFile := path.Join(tmpDir, "99-cloudimg-ipv6.conf")
+	otherFile := path.Join(tmpDir, "50-local.conf")
+	linkFile := path.Join(tmpDir, "99-sysctl.conf")
+	if err := ioutil.WriteFile(cloudFile, []byte("vm.swappiness = 10\nnet.ipv6.conf.all.use_tempaddr = 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(otherFile, []byte("# comment\nvm.max_map_count = 1000\nkernel.shmmni=4096\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(otherFile, linkFile); err != nil {
+		t.Fatal(err)
+	}
+	managed := map[string]string{"vm.swappiness": "sysctl", "vm.max_map_count": "sysctl", "kernel.shmmni": "sysctl", "KSM": "vm"}
+	conflicts := sysctlConflicts(managed, []string{cloudFile, otherFile, linkFile})
+	exp := []Conflict{
+		{Component: "cloud-init", Source: cloudFile, Params: []string{"vm.swappiness"}},
+		{Component: "systemd-sysctl", Source: otherFile, Params: []string{"kernel.shmmni", "vm.max_map_count"}},
+	}
+	if !reflect.DeepEqual(conflicts, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", conflicts, exp)
+	}
+}
+
+func TestSysctlFileOwner(t *testing.T) {
+	for file, exp := range map[string]string{"/etc/sysctl.d/60-azure.conf": "Azure Linux Agent", "/etc/sysctl.d/90-GCE.conf": "Google guest environment", "/usr/lib/sysctl.d/50-default.conf": "systemd-sysctl", "/etc/sysctl.conf": "systemd-sysctl"} {
+		if owner := sysctlFileOwner(file); owner != exp {
+			t.Errorf("file '%s' - got: '%s', expected: '%s'\n", file, owner, exp)
+		}
+	}
+}
+
+func TestTunedPluginKeys(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "tuned.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile.Name())
+	content := "[main]\ninclude=throughput-performance\n[cpu]\ngovernor=performance\nmin_perf_pct=100\n[vm]\ntransparent_hugepages=never\n[sysctl]\nvm.swappiness=10\n[disk]\nelevator=noop\n"
+	if _, err := tmpFile.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	tmpFile.Close()
+	exp := map[string][]string{"cpu": {"governor"}, "vm": {"THP"}, "sysctl": {"vm.swappiness"}, "disk": {"IO_SCHEDULER_*"}}
+	keys := tunedPluginKeys(tmpFile.Name())
+	if !reflect.DeepEqual(keys, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", keys, exp)
+	}
+	managed := map[string]string{"IO_SCHEDULER_sda": "block", "IO_SCHEDULER_sdb": "block", "NRREQ_sda": "block", "THP": "vm"}
+	params := matchManagedParams(keys["disk"], managed)
+	if !reflect.DeepEqual(params, []string{"IO_SCHEDULER_sda", "IO_SCHEDULER_sdb"}) {
+		t.Errorf("got: '%+v', expected: '[IO_SCHEDULER_sda IO_SCHEDULER_sdb]'\n", params)
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"os/exec"
 	"path"
 	"regexp"
-	"runtime"
 	"strconv"
 	"strings"
 )
@@ -38,6 +37,10 @@ func GetPerfBias() string {
 	cmdName := cpupowerCmd
 	cmdArgs := []string{"-c", "all", "info", "-b"}
 
+	if !archSupportsPerfBias() {
+		// perf bias is an Intel specific setting
+		return "all:none"
+	}
 	if !CmdIsAvailable(cmdName) {
 		WarningLog("command '%s' not found", cmdName)
 		return "all:none"
@@ -157,6 +160,9 @@ func supportsPerfBias() bool {
 	cmdName := cpupowerCmd
 	cmdArgs := []string{"info", "-b"}
 
+	if !archSupportsPerfBias() {
+		return false
+	}
 	if !CmdIsAvailable(cmdName) {
 		WarningLog("command '%s' not found", cmdName)
 		return false
@@ -288,8 +294,8 @@ func GetFLInfo() (string, string, bool) {
 
 	// read /sys/devices/system/cpu
 	dirCont, err := ioutil.ReadDir(cpuDir)
-	if runtime.GOARCH != "ppc64le" && err == nil {
-		// latency settings are only relevant for Intel-based systems
+	if archSupportsCPUIdle() && err == nil {
+		// latency settings are not relevant for POWER and IBM Z
 		for _, entry := range dirCont {
 			// cpu0 ... cpuXY
 			if isCPU.MatchString(entry.Name()) {
@@ -412,7 +418,7 @@ func canSetForceLatency(value, info string) bool {
 		WarningLog("latency settings are not supported on '%s'\n", CSPAzureLong)
 		setLatency = false
 	}
-	if value == "all:none" || info == "notSupportedX86" || info == "notSupportedIBM" {
+	if value == "all:none" || info == "notSupportedX86" || info == "notSupportedIBM" || !archSupportsCPUIdle() {
 		WarningLog("latency settings not supported by the system")
 		setLatency = false
 	}
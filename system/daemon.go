@@ -11,15 +11,17 @@ import (
 var systemddvCmd = "/usr/bin/systemd-detect-virt"
 var systemctlCmd = "/usr/bin/systemctl"
 var tunedAdmCmd = "/usr/sbin/tuned-adm"
+var loginctlCmd = "/usr/bin/loginctl"
 var actTunedProfile = "/etc/tuned/active_profile"
 
+// regTunedProfile matches the profile name in the output of 'tuned-adm active'
+var regTunedProfile = regexp.MustCompile(`Current active profile: ([\w-]+)`)
+
 // SystemctlEnable call systemctl enable on thing.
 func SystemctlEnable(thing string) error {
-	out, err := exec.Command(systemctlCmd, "enable", thing).CombinedOutput()
-	if err != nil {
-		return ErrorLog("%v - Failed to call systemctl enable on %s - %s", err, thing, string(out))
+	if err := GetServiceManager().UnitAction("enable", thing); err != nil {
+		return ErrorLog("%v - Failed to call systemctl enable on %s", err, thing)
 	}
-	DebugLog("SystemctlEnable - /usr/bin/systemctl enable '%s' : '%+v %s'", thing, err, string(out))
 	return nil
 }
 
@@ -35,42 +37,33 @@ func SystemctlStatus(thing string) error {
 
 // SystemctlDisable call systemctl disable on thing.
 func SystemctlDisable(thing string) error {
-	out, err := exec.Command(systemctlCmd, "disable", thing).CombinedOutput()
-	if err != nil {
-		return ErrorLog("%v - Failed to call systemctl disable on %s - %s", err, thing, string(out))
+	if err := GetServiceManager().UnitAction("disable", thing); err != nil {
+		return ErrorLog("%v - Failed to call systemctl disable on %s", err, thing)
 	}
-	DebugLog("SystemctlDisable - /usr/bin/systemctl disable '%s' : '%+v %s'", thing, err, string(out))
 	return nil
 }
 
 // SystemctlRestart call systemctl restart on thing.
 func SystemctlRestart(thing string) error {
-	running, err := IsSystemRunning()
-	if err != nil {
-		return ErrorLog("%v - Failed to call systemctl restart on %s", err, thing)
-	}
-	if running {
-		out, err := exec.Command(systemctlCmd, "restart", thing).CombinedOutput()
-		if err != nil {
-			return ErrorLog("%v - Failed to call systemctl restart on %s - %s", err, thing, string(out))
-		}
-		DebugLog("SystemctlRestart - /usr/bin/systemctl restart '%s' : '%+v %s'", thing, err, string(out))
-	}
-	return nil
+	return unitActionIfRunning("restart", thing)
 }
 
 // SystemctlReloadTryRestart call systemctl reload on thing.
 func SystemctlReloadTryRestart(thing string) error {
+	return unitActionIfRunning("reload-or-try-restart", thing)
+}
+
+// unitActionIfRunning runs the action for the unit, but only if the system
+// is running to prevent 'Transaction is destructive' messages
+func unitActionIfRunning(action, thing string) error {
 	running, err := IsSystemRunning()
 	if err != nil {
-		return ErrorLog("%v - Failed to call systemctl reload-or-try-restart on %s", err, thing)
+		return ErrorLog("%v - Failed to call systemctl %s on %s", err, action, thing)
 	}
 	if running {
-		out, err := exec.Command(systemctlCmd, "reload-or-try-restart", thing).CombinedOutput()
-		if err != nil {
-			return ErrorLog("%v - Failed to call systemctl reload-or-try-restart on %s - %s", err, thing, string(out))
+		if err := GetServiceManager().UnitAction(action, thing); err != nil {
+			return ErrorLog("%v - Failed to call systemctl %s on %s", err, action, thing)
 		}
-		DebugLog("SystemctlReloadTryRestart - /usr/bin/systemctl reload-or-try-restart '%s' : '%+v %s'", thing, err, string(out))
 	}
 	return nil
 }
@@ -79,26 +72,11 @@ func SystemctlReloadTryRestart(thing string) error {
 // option can be '-r' (chroot), -c (container), -v (vm)
 // '-r' only returns 0 or 1 without any output
 func SystemdDetectVirt(opt string) (bool, string, error) {
-	var out []byte
-	var err error
-
-	virt := false
-	vtype := ""
-	if opt == "" {
-		out, err = exec.Command(systemddvCmd).CombinedOutput()
-	} else {
-		out, err = exec.Command(systemddvCmd, opt).CombinedOutput()
+	virt, vtype, err := GetServiceManager().Virtualization(opt)
+	if vtype == "" && err != nil && opt != "-r" {
+		return virt, vtype, ErrorLog("%v - Failed to call systemd-detect-virt %s", err, opt)
 	}
-	DebugLog("SystemdDetectVirt - /usr/bin/systemd-detect-virt %s : '%+v %s'", opt, err, string(out))
-	if err == nil {
-		// virtualized environment detected
-		virt = true
-	}
-	if len(out) == 0 && err != nil && opt != "-r" {
-		return virt, vtype, ErrorLog("%v - Failed to call systemd-detect-virt %s - %s", err, opt, string(out))
-	}
-	vtype = string(out)
-	return virt, strings.TrimSpace(vtype), err
+	return virt, vtype, err
 }
 
 // SystemctlResetFailed calls systemctl reset-failed.
@@ -108,45 +86,21 @@ func SystemctlResetFailed() error {
 		return ErrorLog("%v - Failed to call systemctl reset-failed", err)
 	}
 	if running {
-		out, err := exec.Command(systemctlCmd, "reset-failed").CombinedOutput()
-		if err != nil {
-			return ErrorLog("%v - Failed to call systemctl reset-failed - %s", err, string(out))
+		if err := GetServiceManager().ResetFailed(); err != nil {
+			return ErrorLog("%v - Failed to call systemctl reset-failed", err)
 		}
-		DebugLog("SystemctlResetFailed - /usr/bin/systemctl reset-failed : '%+v %s'", err, string(out))
 	}
 	return nil
 }
 
 // SystemctlStart call systemctl start on thing.
 func SystemctlStart(thing string) error {
-	running, err := IsSystemRunning()
-	if err != nil {
-		return ErrorLog("%v - Failed to call systemctl start on %s", err, thing)
-	}
-	if running {
-		out, err := exec.Command(systemctlCmd, "start", thing).CombinedOutput()
-		if err != nil {
-			return ErrorLog("%v - Failed to call systemctl start on %s - %s", err, thing, string(out))
-		}
-		DebugLog("SystemctlStart - /usr/bin/systemctl start '%s' : '%+v %s'", thing, err, string(out))
-	}
-	return nil
+	return unitActionIfRunning("start", thing)
 }
 
 // SystemctlStop call systemctl stop on thing.
 func SystemctlStop(thing string) error {
-	running, err := IsSystemRunning()
-	if err != nil {
-		return ErrorLog("%v - Failed to call systemctl stop on %s", err, thing)
-	}
-	if running {
-		out, err := exec.Command(systemctlCmd, "stop", thing).CombinedOutput()
-		if err != nil {
-			return ErrorLog("%v - Failed to call systemctl stop on %s - %s", err, thing, string(out))
-		}
-		DebugLog("SystemctlStop - /usr/bin/systemctl stop '%s' : '%+v %s'", thing, err, string(out))
-	}
-	return nil
+	return unitActionIfRunning("stop", thing)
 }
 
 // SystemctlEnableStart call systemctl enable and then systemctl start on thing.
@@ -171,25 +125,20 @@ func SystemctlDisableStop(thing string) error {
 // SystemctlIsEnabled return true only if systemctl suggests that the thing is
 // enabled.
 func SystemctlIsEnabled(thing string) (bool, error) {
-	match := false
-	out, err := exec.Command(systemctlCmd, "is-enabled", thing).CombinedOutput()
-	DebugLog("SystemctlIsEnabled - /usr/bin/systemctl is-enabled : '%+v %s'", err, string(out))
-	if err == nil {
-		match = true
-	}
-	if len(out) == 0 && err != nil {
-		return match, ErrorLog("%v - Failed to call systemctl is-enabled", err)
+	state, err := GetServiceManager().UnitFileState(thing)
+	DebugLog("SystemctlIsEnabled - '%s' is '%s' : '%+v'", thing, state, err)
+	if err != nil {
+		return false, ErrorLog("%v - Failed to call systemctl is-enabled", err)
 	}
-	return match, nil
+	return enabledUnitStates[state], nil
 }
 
 // SystemctlIsStarting return true only if systemctl suggests that the system is
 // starting.
 func SystemctlIsStarting() bool {
 	match := false
-	out, err := exec.Command(systemctlCmd, "is-system-running").CombinedOutput()
-	DebugLog("SystemctlIsStarting - /usr/bin/systemctl is-system-running : '%+v %s'", err, string(out))
-	if strings.TrimSpace(string(out)) == "starting" {
+	state, _ := GetServiceManager().SystemState()
+	if state == "starting" {
 		DebugLog("SystemctlIsStarting - system is in state 'starting'")
 		match = true
 	}
@@ -199,37 +148,32 @@ func SystemctlIsStarting() bool {
 // SystemctlIsRunning return true only if systemctl suggests that the thing is
 // running.
 func SystemctlIsRunning(thing string) (bool, error) {
-	match := false
-	out, err := exec.Command(systemctlCmd, "is-active", thing).CombinedOutput()
-	DebugLog("SystemctlIsRunning - /usr/bin/systemctl is-active : '%+v %s'", err, string(out))
-	if err == nil {
-		match = true
-	}
-	if len(out) == 0 && err != nil {
-		return match, ErrorLog("%v - Failed to call systemctl is-active", err)
+	state, err := GetServiceManager().ActiveState(thing)
+	DebugLog("SystemctlIsRunning - '%s' is '%s' : '%+v'", thing, state, err)
+	if err != nil {
+		return false, ErrorLog("%v - Failed to call systemctl is-active", err)
 	}
-	return match, nil
+	return state == "active" || state == "reloading", nil
 }
 
 // SystemctlIsActive returns the output of 'systemctl is-active'
 func SystemctlIsActive(thing string) (string, error) {
-	out, err := exec.Command(systemctlCmd, "is-active", thing).CombinedOutput()
-	DebugLog("SystemctlIsActive - /usr/bin/systemctl is-active : '%+v %s'", err, string(out))
-	if len(out) == 0 && err != nil {
+	state, err := GetServiceManager().ActiveState(thing)
+	DebugLog("SystemctlIsActive - '%s' is '%s' : '%+v'", thing, state, err)
+	if err != nil {
 		return "", ErrorLog("%v - Failed to call systemctl is-active", err)
 	}
-	return strings.TrimSpace(string(out)), err
+	if state != "active" && state != "reloading" {
+		err = fmt.Errorf("unit '%s' is %s", thing, state)
+	}
+	return state, err
 }
 
 // GetSystemState returns the output of 'systemctl is-system-running'
 func GetSystemState() (string, error) {
-	retval := ""
-	out, err := exec.Command(systemctlCmd, "is-system-running").CombinedOutput()
-	DebugLog("GetSystemState - /usr/bin/systemctl is-system-running : '%+v %s'", err, string(out))
-	if len(out) != 0 {
-		retval = strings.TrimSpace(string(out))
-	}
-	return retval, err
+	state, err := GetServiceManager().SystemState()
+	DebugLog("GetSystemState - '%s' : '%+v'", state, err)
+	return state, err
 }
 
 // IsSystemRunning returns true, if 'is-system-running' reports 'running'
@@ -238,9 +182,9 @@ func GetSystemState() (string, error) {
 // messages
 func IsSystemRunning() (bool, error) {
 	match := false
-	out, err := exec.Command(systemctlCmd, "is-system-running").CombinedOutput()
-	DebugLog("IsSystemRunning - /usr/bin/systemctl is-system-running : '%+v %s'", err, string(out))
-	for _, line := range strings.Split(string(out), "\n") {
+	state, err := GetServiceManager().SystemState()
+	DebugLog("IsSystemRunning - '%s' : '%+v'", state, err)
+	for _, line := range strings.Split(state, "\n") {
 		if strings.TrimSpace(line) == "starting" || strings.TrimSpace(line) == "running" || strings.TrimSpace(line) == "degraded" {
 			DebugLog("IsSystemRunning - system is degraded/starting/running, match true")
 			match = true
@@ -256,22 +200,13 @@ func IsSystemRunning() (bool, error) {
 // IsServiceAvailable checks, if a systemd service is available on the system
 func IsServiceAvailable(service string) bool {
 	match := false
-	cmdArgs := []string{"--no-pager", "list-unit-files", "-t", "service"}
-	cmdOut, err := exec.Command(systemctlCmd, cmdArgs...).CombinedOutput()
+	units, err := GetServiceManager().UnitFiles()
 	if err != nil {
-		_ = ErrorLog("Failed to call '%s %v' to get the available services - %v", systemctlCmd, strings.Join(cmdArgs, " "), err)
+		_ = ErrorLog("Failed to get the available services - %v", err)
 		return match
 	}
-	for _, line := range strings.Split(string(cmdOut), "\n") {
-		fields := strings.Fields(line)
-		if len(fields) == 0 {
-			continue
-		}
-		if strings.TrimSpace(fields[0]) == service {
-			match = true
-			break
-		}
-		if strings.TrimSpace(fields[0]) == fmt.Sprintf("%s.service", service) {
+	for _, unit := range units {
+		if unit == service || unit == fmt.Sprintf("%s.service", service) {
 			match = true
 			break
 		}
@@ -355,8 +290,8 @@ func TunedAdmOff() error {
 		// 'tuned-adm off' does not work without running tuned
 		return nil
 	}
-	if out, err := exec.Command(tunedAdmCmd, "off").CombinedOutput(); err != nil {
-		return ErrorLog("Failed to call tuned-adm to switch off the active profile - %v %s", err, string(out))
+	if err := GetServiceManager().TunedOff(); err != nil {
+		return ErrorLog("Failed to call tuned-adm to switch off the active profile - %v", err)
 	}
 	return nil
 }
@@ -365,8 +300,8 @@ func TunedAdmOff() error {
 // newer versions of tuned seems to be reliable with this command and they
 // changed the behaviour/handling of the file /etc/tuned/active_profile
 func TunedAdmProfile(profileName string) error {
-	if out, err := exec.Command(tunedAdmCmd, "profile", profileName).CombinedOutput(); err != nil {
-		return ErrorLog("Failed to call tuned-adm to active profile %s - %v %s", profileName, err, string(out))
+	if err := GetServiceManager().TunedSwitchProfile(profileName); err != nil {
+		return ErrorLog("Failed to call tuned-adm to active profile %s - %v", profileName, err)
 	}
 	return nil
 }
@@ -374,17 +309,12 @@ func TunedAdmProfile(profileName string) error {
 // GetTunedAdmProfile return the currently active tuned profile.
 // Return empty string if it cannot be determined.
 func GetTunedAdmProfile() string {
-	out, err := exec.Command(tunedAdmCmd, "active").CombinedOutput()
+	profile, err := GetServiceManager().TunedProfile()
 	if err != nil {
-		InfoLog("Failed to call tuned-adm to get the active profile - %v %s", err, string(out))
-		return ""
-	}
-	re := regexp.MustCompile(`Current active profile: ([\w-]+)`)
-	matches := re.FindStringSubmatch(string(out))
-	if len(matches) == 0 {
+		InfoLog("Failed to call tuned-adm to get the active profile - %v", err)
 		return ""
 	}
-	return matches[1]
+	return profile
 }
 
 // IsSapconfActive checks, if sapconf is active
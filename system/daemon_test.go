@@ -387,6 +387,8 @@ func TestGetTunedAdmProfile(t *testing.T) {
 }
 
 func TestDaemonErrorCases(t *testing.T) {
+	defer SetServiceManager(GetServiceManager())
+	SetServiceManager(ExecServiceManager{})
 	oldSystemctlCmd := systemctlCmd
 	systemctlCmd = "/usr/bin/false"
 	if err := SystemctlRestart("tstserv"); err == nil {
@@ -456,6 +458,8 @@ func TestDaemonErrorCases(t *testing.T) {
 }
 
 func TestSystemdDetectVirt(t *testing.T) {
+	defer SetServiceManager(GetServiceManager())
+	SetServiceManager(ExecServiceManager{})
 	opt := ""
 	oldSystemddvCmd := systemddvCmd
 	// test: virtualization found
@@ -0,0 +1,666 @@
+package system
+
+// minimal D-Bus client for the communication with systemd, logind and
+// tuned. Only method calls and the basic D-Bus types are supported.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dbusSystemBus is the default address of the D-Bus system bus
+var dbusSystemBus = "unix:path=/run/dbus/system_bus_socket"
+
+// dbusTimeout is the maximal time to wait for the reply of a method call
+var dbusTimeout = 30 * time.Second
+
+// D-Bus message types
+const (
+	dbusMethodCall   = 1
+	dbusMethodReturn = 2
+	dbusError        = 3
+	dbusSignal       = 4
+)
+
+// D-Bus header fields
+const (
+	dbusFieldPath        = 1
+	dbusFieldInterface   = 2
+	dbusFieldMember      = 3
+	dbusFieldErrorName   = 4
+	dbusFieldReplySerial = 5
+	dbusFieldDestination = 6
+	dbusFieldSender      = 7
+	dbusFieldSignature   = 8
+)
+
+// dbusVariant is a D-Bus value together with its signature
+type dbusVariant struct {
+	Sig   string
+	Value interface{}
+}
+
+// dbusErr is an error reply of a D-Bus method call
+type dbusErr struct {
+	Name string
+	Msg  string
+}
+
+func (e dbusErr) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Msg)
+}
+
+// dbusConnErr is an error of the connection to the message bus, e.g. the
+// bus was restarted. The connection is not usable anymore
+type dbusConnErr struct {
+	err error
+}
+
+func (e dbusConnErr) Error() string {
+	return fmt.Sprintf("D-Bus connection failed - %v", e.err)
+}
+
+// dbusMessage is a single D-Bus message
+type dbusMessage struct {
+	Type   byte
+	Flags  byte
+	Serial uint32
+	Fields map[byte]dbusVariant
+	Body   []interface{}
+}
+
+// field returns the string value of a header field
+func (msg dbusMessage) field(code byte) string {
+	if val, ok := msg.Fields[code]; ok {
+		if str, ok := val.Value.(string); ok {
+			return str
+		}
+	}
+	return ""
+}
+
+// dbusConn is a connection to a D-Bus message bus. A method call holds
+// the connection until its reply is read, so concurrent callers do not
+// get the replies of each other
+type dbusConn struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	serial uint32
+	name   string
+}
+
+// dbusDial connects to the D-Bus message bus 'address' (e.g.
+// 'unix:path=/run/dbus/system_bus_socket'), authenticates and registers
+// the connection
+func dbusDial(address string) (*dbusConn, error) {
+	if env := os.Getenv("DBUS_SYSTEM_BUS_ADDRESS"); env != "" && address == dbusSystemBus {
+		address = env
+	}
+	socket := ""
+	for _, addr := range strings.Split(address, ";") {
+		if strings.HasPrefix(addr, "unix:path=") {
+			socket = strings.Split(strings.TrimPrefix(addr, "unix:path="), ",")[0]
+			break
+		}
+	}
+	if socket == "" {
+		return nil, fmt.Errorf("unsupported D-Bus address '%s'", address)
+	}
+	conn, err := net.DialTimeout("unix", socket, dbusTimeout)
+	if err != nil {
+		return nil, err
+	}
+	dc := &dbusConn{conn: conn, reader: bufio.NewReader(conn)}
+	if err := dc.auth(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reply, err := dc.Call("org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "Hello", "")
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if len(reply) > 0 {
+		dc.name, _ = reply[0].(string)
+	}
+	return dc, nil
+}
+
+// auth authenticates the connection with the EXTERNAL mechanism
+func (dc *dbusConn) auth() error {
+	uid := hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+	if _, err := dc.conn.Write([]byte("\x00AUTH EXTERNAL " + uid + "\r\n")); err != nil {
+		return err
+	}
+	_ = dc.conn.SetReadDeadline(time.Now().Add(dbusTimeout))
+	line, err := dc.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "OK ") {
+		return fmt.Errorf("D-Bus authentication failed - '%s'", strings.TrimSpace(line))
+	}
+	_, err = dc.conn.Write([]byte("BEGIN\r\n"))
+	return err
+}
+
+// Close closes the connection
+func (dc *dbusConn) Close() error {
+	return dc.conn.Close()
+}
+
+// Call calls the method 'member' of the interface 'iface' of the object
+// 'path' at 'dest' and returns the body of the reply. 'sig' is the
+// signature of the arguments. Errors of the connection are reported as
+// dbusConnErr
+func (dc *dbusConn) Call(dest, path, iface, member, sig string, args ...interface{}) ([]interface{}, error) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.serial++
+	msg := dbusMessage{
+		Type:   dbusMethodCall,
+		Serial: dc.serial,
+		Fields: map[byte]dbusVariant{
+			dbusFieldPath:        {"o", path},
+			dbusFieldInterface:   {"s", iface},
+			dbusFieldMember:      {"s", member},
+			dbusFieldDestination: {"s", dest},
+		},
+		Body: args,
+	}
+	if sig != "" {
+		msg.Fields[dbusFieldSignature] = dbusVariant{"g", sig}
+	}
+	data, err := dbusMarshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dc.conn.Write(data); err != nil {
+		return nil, dbusConnErr{err}
+	}
+	_ = dc.conn.SetReadDeadline(time.Now().Add(dbusTimeout))
+	for {
+		reply, err := dbusReadMessage(dc.reader)
+		if err != nil {
+			// the message stream is out of sync or closed
+			return nil, dbusConnErr{err}
+		}
+		if reply.Type != dbusMethodReturn && reply.Type != dbusError {
+			// signals or method calls are not of interest
+			continue
+		}
+		if serial, ok := reply.Fields[dbusFieldReplySerial].Value.(uint32); !ok || serial != msg.Serial {
+			continue
+		}
+		if reply.Type == dbusError {
+			errMsg := ""
+			if len(reply.Body) > 0 {
+				errMsg, _ = reply.Body[0].(string)
+			}
+			return nil, dbusErr{Name: reply.field(dbusFieldErrorName), Msg: errMsg}
+		}
+		return reply.Body, nil
+	}
+}
+
+// GetProperty returns the value of the property 'prop' of the interface
+// 'iface' of the object 'path' at 'dest'
+func (dc *dbusConn) GetProperty(dest, path, iface, prop string) (interface{}, error) {
+	reply, err := dc.Call(dest, path, "org.freedesktop.DBus.Properties", "Get", "ss", iface, prop)
+	if err != nil {
+		return nil, err
+	}
+	return dbusPropertyValue(reply, prop)
+}
+
+// dbusPropertyValue returns the value of the property 'prop' from the reply
+// of a 'Get' call of the interface 'org.freedesktop.DBus.Properties'
+func dbusPropertyValue(reply []interface{}, prop string) (interface{}, error) {
+	if len(reply) != 1 {
+		return nil, fmt.Errorf("unexpected reply for property '%s'", prop)
+	}
+	variant, ok := reply[0].(dbusVariant)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply for property '%s'", prop)
+	}
+	return variant.Value, nil
+}
+
+// dbusMarshal converts a message into the D-Bus wire format (little endian)
+func dbusMarshal(msg dbusMessage) ([]byte, error) {
+	body := &dbusEncoder{}
+	sig := ""
+	if val, ok := msg.Fields[dbusFieldSignature]; ok {
+		sig, _ = val.Value.(string)
+	}
+	types, err := dbusSplitSig(sig)
+	if err != nil {
+		return nil, err
+	}
+	if len(types) != len(msg.Body) {
+		return nil, fmt.Errorf("signature '%s' does not match %d arguments", sig, len(msg.Body))
+	}
+	for i, typ := range types {
+		if err := body.encode(typ, msg.Body[i]); err != nil {
+			return nil, err
+		}
+	}
+	fields := []interface{}{}
+	for code := byte(dbusFieldPath); code <= dbusFieldSignature; code++ {
+		if val, ok := msg.Fields[code]; ok {
+			fields = append(fields, []interface{}{code, val})
+		}
+	}
+	head := &dbusEncoder{}
+	head.buf.Write([]byte{'l', msg.Type, msg.Flags, 1})
+	_ = head.encode("u", uint32(body.buf.Len()))
+	_ = head.encode("u", msg.Serial)
+	if err := head.encode("a(yv)", fields); err != nil {
+		return nil, err
+	}
+	head.align(8)
+	return append(head.buf.Bytes(), body.buf.Bytes()...), nil
+}
+
+// dbusReadMessage reads a single message from the wire
+func dbusReadMessage(reader io.Reader) (dbusMessage, error) {
+	msg := dbusMessage{}
+	fixed := make([]byte, 16)
+	if _, err := io.ReadFull(reader, fixed); err != nil {
+		return msg, err
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if fixed[0] == 'B' {
+		order = binary.BigEndian
+	}
+	bodyLen := order.Uint32(fixed[4:8])
+	fieldsLen := order.Uint32(fixed[12:16])
+	headLen := 16 + int(fieldsLen)
+	if headLen%8 != 0 {
+		headLen += 8 - headLen%8
+	}
+	if fieldsLen > 1<<26 || bodyLen > 1<<27 {
+		return msg, fmt.Errorf("D-Bus message too large")
+	}
+	data := make([]byte, headLen+int(bodyLen))
+	copy(data, fixed)
+	if _, err := io.ReadFull(reader, data[16:]); err != nil {
+		return msg, err
+	}
+	msg.Type = fixed[1]
+	msg.Flags = fixed[2]
+	msg.Serial = order.Uint32(fixed[8:12])
+	msg.Fields = make(map[byte]dbusVariant)
+	head := &dbusDecoder{data: data[:headLen], pos: 12, order: order}
+	fields, err := head.decode("a(yv)")
+	if err != nil {
+		return msg, err
+	}
+	fieldList, ok := fields.([]interface{})
+	if !ok {
+		return msg, fmt.Errorf("malformed D-Bus message header")
+	}
+	for _, field := range fieldList {
+		entry, ok := field.([]interface{})
+		if !ok || len(entry) != 2 {
+			return msg, fmt.Errorf("malformed D-Bus message header field")
+		}
+		code, codeOK := entry[0].(byte)
+		val, valOK := entry[1].(dbusVariant)
+		if !codeOK || !valOK {
+			return msg, fmt.Errorf("malformed D-Bus message header field")
+		}
+		msg.Fields[code] = val
+	}
+	types, err := dbusSplitSig(msg.field(dbusFieldSignature))
+	if err != nil {
+		return msg, err
+	}
+	body := &dbusDecoder{data: data[headLen:], order: order}
+	for _, typ := range types {
+		val, err := body.decode(typ)
+		if err != nil {
+			return msg, err
+		}
+		msg.Body = append(msg.Body, val)
+	}
+	return msg, nil
+}
+
+// dbusSplitSig splits a signature into its single complete types
+func dbusSplitSig(sig string) ([]string, error) {
+	types := []string{}
+	for sig != "" {
+		typ, err := dbusNextType(sig)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, typ)
+		sig = sig[len(typ):]
+	}
+	return types, nil
+}
+
+// dbusNextType returns the first single complete type of a signature
+func dbusNextType(sig string) (string, error) {
+	if sig == "" {
+		return "", fmt.Errorf("empty signature")
+	}
+	switch sig[0] {
+	case 'a':
+		elem, err := dbusNextType(sig[1:])
+		return "a" + elem, err
+	case '(', '{':
+		closing := map[byte]byte{'(': ')', '{': '}'}[sig[0]]
+		pos := 1
+		for pos < len(sig) && sig[pos] != closing {
+			elem, err := dbusNextType(sig[pos:])
+			if err != nil {
+				return "", err
+			}
+			pos += len(elem)
+		}
+		if pos >= len(sig) {
+			return "", fmt.Errorf("unbalanced signature '%s'", sig)
+		}
+		return sig[:pos+1], nil
+	case 'y', 'b', 'n', 'q', 'i', 'u', 'x', 't', 'd', 's', 'o', 'g', 'v', 'h':
+		return sig[:1], nil
+	}
+	return "", fmt.Errorf("unsupported signature '%s'", sig)
+}
+
+// dbusAlignment returns the alignment of a type
+func dbusAlignment(typ byte) int {
+	switch typ {
+	case 'y', 'g', 'v':
+		return 1
+	case 'n', 'q':
+		return 2
+	case 'x', 't', 'd', '(', '{':
+		return 8
+	}
+	return 4
+}
+
+// dbusEncoder writes values in the D-Bus wire format (little endian)
+type dbusEncoder struct {
+	buf bytes.Buffer
+}
+
+// align pads the buffer to a multiple of n
+func (enc *dbusEncoder) align(n int) {
+	for enc.buf.Len()%n != 0 {
+		enc.buf.WriteByte(0)
+	}
+}
+
+// number writes a fixed size number
+func (enc *dbusEncoder) number(size int, val uint64) {
+	enc.align(size)
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, val)
+	enc.buf.Write(data[:size])
+}
+
+// encode writes the value 'val' of the single complete type 'typ'
+func (enc *dbusEncoder) encode(typ string, val interface{}) error {
+	wrong := fmt.Errorf("value '%v' does not match type '%s'", val, typ)
+	switch typ[0] {
+	case 'y':
+		b, ok := val.(byte)
+		if !ok {
+			return wrong
+		}
+		enc.buf.WriteByte(b)
+	case 'b':
+		b, ok := val.(bool)
+		if !ok {
+			return wrong
+		}
+		enc.number(4, map[bool]uint64{false: 0, true: 1}[b])
+	case 'n':
+		n, ok := val.(int16)
+		if !ok {
+			return wrong
+		}
+		enc.number(2, uint64(uint16(n)))
+	case 'q':
+		q, ok := val.(uint16)
+		if !ok {
+			return wrong
+		}
+		enc.number(2, uint64(q))
+	case 'i':
+		i, ok := val.(int32)
+		if !ok {
+			return wrong
+		}
+		enc.number(4, uint64(uint32(i)))
+	case 'u':
+		u, ok := val.(uint32)
+		if !ok {
+			return wrong
+		}
+		enc.number(4, uint64(u))
+	case 'x':
+		x, ok := val.(int64)
+		if !ok {
+			return wrong
+		}
+		enc.number(8, uint64(x))
+	case 't':
+		t, ok := val.(uint64)
+		if !ok {
+			return wrong
+		}
+		enc.number(8, t)
+	case 'd':
+		d, ok := val.(float64)
+		if !ok {
+			return wrong
+		}
+		enc.number(8, math.Float64bits(d))
+	case 's', 'o':
+		s, ok := val.(string)
+		if !ok {
+			return wrong
+		}
+		enc.number(4, uint64(len(s)))
+		enc.buf.WriteString(s)
+		enc.buf.WriteByte(0)
+	case 'g':
+		s, ok := val.(string)
+		if !ok {
+			return wrong
+		}
+		enc.buf.WriteByte(byte(len(s)))
+		enc.buf.WriteString(s)
+		enc.buf.WriteByte(0)
+	case 'v':
+		v, ok := val.(dbusVariant)
+		if !ok {
+			return wrong
+		}
+		if _, err := dbusNextType(v.Sig); err != nil {
+			return err
+		}
+		_ = enc.encode("g", v.Sig)
+		return enc.encode(v.Sig, v.Value)
+	case 'a':
+		elems, ok := val.([]interface{})
+		if !ok {
+			return wrong
+		}
+		enc.number(4, 0)
+		lenPos := enc.buf.Len() - 4
+		enc.align(dbusAlignment(typ[1]))
+		start := enc.buf.Len()
+		for _, elem := range elems {
+			if err := enc.encode(typ[1:], elem); err != nil {
+				return err
+			}
+		}
+		binary.LittleEndian.PutUint32(enc.buf.Bytes()[lenPos:], uint32(enc.buf.Len()-start))
+	case '(', '{':
+		fields, ok := val.([]interface{})
+		if !ok {
+			return wrong
+		}
+		types, err := dbusSplitSig(typ[1 : len(typ)-1])
+		if err != nil {
+			return err
+		}
+		if len(types) != len(fields) {
+			return wrong
+		}
+		enc.align(8)
+		for i, fieldType := range types {
+			if err := enc.encode(fieldType, fields[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type '%s'", typ)
+	}
+	return nil
+}
+
+// dbusDecoder reads values in the D-Bus wire format
+type dbusDecoder struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+// number reads a fixed size number
+func (dec *dbusDecoder) number(size int) (uint64, error) {
+	if dec.pos%size != 0 {
+		dec.pos += size - dec.pos%size
+	}
+	if dec.pos+size > len(dec.data) {
+		return 0, fmt.Errorf("D-Bus message truncated")
+	}
+	data := dec.data[dec.pos : dec.pos+size]
+	dec.pos += size
+	switch size {
+	case 1:
+		return uint64(data[0]), nil
+	case 2:
+		return uint64(dec.order.Uint16(data)), nil
+	case 4:
+		return uint64(dec.order.Uint32(data)), nil
+	}
+	return dec.order.Uint64(data), nil
+}
+
+// str reads a string with a length field of 'size' bytes
+func (dec *dbusDecoder) str(size int) (string, error) {
+	length, err := dec.number(size)
+	if err != nil {
+		return "", err
+	}
+	if dec.pos+int(length)+1 > len(dec.data) {
+		return "", fmt.Errorf("D-Bus message truncated")
+	}
+	str := string(dec.data[dec.pos : dec.pos+int(length)])
+	dec.pos += int(length) + 1
+	return str, nil
+}
+
+// decode reads a value of the single complete type 'typ'
+func (dec *dbusDecoder) decode(typ string) (interface{}, error) {
+	switch typ[0] {
+	case 'y':
+		val, err := dec.number(1)
+		return byte(val), err
+	case 'b':
+		val, err := dec.number(4)
+		return val != 0, err
+	case 'n':
+		val, err := dec.number(2)
+		return int16(val), err
+	case 'q':
+		val, err := dec.number(2)
+		return uint16(val), err
+	case 'i':
+		val, err := dec.number(4)
+		return int32(val), err
+	case 'u', 'h':
+		val, err := dec.number(4)
+		return uint32(val), err
+	case 'x':
+		val, err := dec.number(8)
+		return int64(val), err
+	case 't':
+		return dec.number(8)
+	case 'd':
+		val, err := dec.number(8)
+		return math.Float64frombits(val), err
+	case 's', 'o':
+		return dec.str(4)
+	case 'g':
+		return dec.str(1)
+	case 'v':
+		sig, err := dec.str(1)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := dbusNextType(sig); err != nil {
+			return nil, err
+		}
+		val, err := dec.decode(sig)
+		return dbusVariant{Sig: sig, Value: val}, err
+	case 'a':
+		length, err := dec.number(4)
+		if err != nil {
+			return nil, err
+		}
+		if align := dbusAlignment(typ[1]); dec.pos%align != 0 {
+			dec.pos += align - dec.pos%align
+		}
+		end := dec.pos + int(length)
+		if end > len(dec.data) {
+			return nil, fmt.Errorf("D-Bus message truncated")
+		}
+		elems := []interface{}{}
+		for dec.pos < end {
+			elem, err := dec.decode(typ[1:])
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		return elems, nil
+	case '(', '{':
+		types, err := dbusSplitSig(typ[1 : len(typ)-1])
+		if err != nil {
+			return nil, err
+		}
+		if dec.pos%8 != 0 {
+			dec.pos += 8 - dec.pos%8
+		}
+		fields := []interface{}{}
+		for _, fieldType := range types {
+			field, err := dec.decode(fieldType)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+		}
+		return fields, nil
+	}
+	return nil, fmt.Errorf("unsupported type '%s'", typ)
+}
@@ -0,0 +1,333 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"net"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeBus is a local D-Bus service answering the calls of the
+// DBusServiceManager like systemd, logind and tuned do
+type fakeBus struct {
+	sync.Mutex
+	listener   net.Listener
+	sysState   string
+	virt       string
+	active     map[string]string
+	unitFiles  map[string]string
+	tasksMax   uint64
+	tunedProf  string
+	tunedOnBus bool
+	calls      []string
+	conns      []net.Conn
+}
+
+// startFakeBus starts the fake D-Bus service and returns its address
+func startFakeBus(t *testing.T) (*fakeBus, string) {
+	sock := path.Join(t.TempDir(), "bus")
+	listener, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb := &fakeBus{
+		listener:   listener,
+		sysState:   "running",
+		virt:       "kvm",
+		active:     map[string]string{"saptune.service": "inactive", "tuned.service": "active"},
+		unitFiles:  map[string]string{"saptune.service": "disabled", "tuned.service": "enabled", "sapconf.service": "static"},
+		tasksMax:   math.MaxUint64,
+		tunedProf:  "balanced",
+		tunedOnBus: true,
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			fb.Lock()
+			fb.conns = append(fb.conns, conn)
+			fb.Unlock()
+			go fb.serve(conn)
+		}
+	}()
+	return fb, "unix:path=" + sock
+}
+
+// dropConnections closes the connections of all clients like a restart
+// of the bus does
+func (fb *fakeBus) dropConnections() {
+	fb.Lock()
+	defer fb.Unlock()
+	for _, conn := range fb.conns {
+		conn.Close()
+	}
+	fb.conns = nil
+}
+
+// serve handles the authentication and the method calls of a connection
+func (fb *fakeBus) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "\x00AUTH EXTERNAL ") {
+		_, _ = conn.Write([]byte("REJECTED EXTERNAL\r\n"))
+		return
+	}
+	_, _ = conn.Write([]byte("OK 0123456789abcdef0123456789abcdef\r\n"))
+	if line, err = reader.ReadString('\n'); err != nil || line != "BEGIN\r\n" {
+		return
+	}
+	serial := uint32(0)
+	for {
+		msg, err := dbusReadMessage(reader)
+		if err != nil {
+			return
+		}
+		sig, body, errName := fb.handle(msg)
+		serial++
+		reply := dbusMessage{Type: dbusMethodReturn, Serial: serial, Fields: map[byte]dbusVariant{dbusFieldReplySerial: {"u", msg.Serial}}, Body: body}
+		if errName != "" {
+			reply.Type = dbusError
+			reply.Fields[dbusFieldErrorName] = dbusVariant{"s", errName}
+			sig = "s"
+			reply.Body = []interface{}{"fake bus error"}
+		}
+		if sig != "" {
+			reply.Fields[dbusFieldSignature] = dbusVariant{"g", sig}
+		}
+		// a signal before the reply has to be skipped by the client
+		signal := dbusMessage{Type: dbusSignal, Serial: serial + 1000, Fields: map[byte]dbusVariant{dbusFieldPath: {"o", systemdPath}, dbusFieldInterface: {"s", systemdManager}, dbusFieldMember: {"s", "Reloading"}, dbusFieldSignature: {"g", "b"}}, Body: []interface{}{true}}
+		for _, m := range []dbusMessage{signal, reply} {
+			data, err := dbusMarshal(m)
+			if err != nil {
+				return
+			}
+			if _, err := conn.Write(data); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// fakeUnitPath returns the object path of a unit
+func fakeUnitPath(unit string) string {
+	return "/org/freedesktop/systemd1/unit/" + strings.NewReplacer(".", "_2e", "-", "_2d").Replace(unit)
+}
+
+// handle answers a method call with the signature and body of the reply
+// or with an error name
+func (fb *fakeBus) handle(msg dbusMessage) (string, []interface{}, string) {
+	fb.Lock()
+	defer fb.Unlock()
+	dest := msg.field(dbusFieldDestination)
+	member := msg.field(dbusFieldMember)
+	fb.calls = append(fb.calls, member)
+	str := func(i int) string {
+		if i < len(msg.Body) {
+			s, _ := msg.Body[i].(string)
+			return s
+		}
+		return ""
+	}
+	if dest == tunedBus && !fb.tunedOnBus {
+		return "", nil, "org.freedesktop.DBus.Error.ServiceUnknown"
+	}
+	switch member {
+	case "Hello":
+		return "s", []interface{}{":1.42"}, ""
+	case "Get":
+		switch prop := str(1); {
+		case prop == "SystemState":
+			return "v", []interface{}{dbusVariant{"s", fb.sysState}}, ""
+		case prop == "Virtualization":
+			return "v", []interface{}{dbusVariant{"s", fb.virt}}, ""
+		case prop == "State":
+			// job already finished
+			return "", nil, "org.freedesktop.DBus.Error.UnknownObject"
+		case prop == "TasksMax" && str(0) == "org.freedesktop.systemd1.Slice":
+			return "v", []interface{}{dbusVariant{"t", fb.tasksMax}}, ""
+		case prop == "ActiveState":
+			for unit, state := range fb.active {
+				if fakeUnitPath(unit) == msg.field(dbusFieldPath) {
+					return "v", []interface{}{dbusVariant{"s", state}}, ""
+				}
+			}
+			return "v", []interface{}{dbusVariant{"s", "inactive"}}, ""
+		}
+	case "LoadUnit":
+		return "o", []interface{}{fakeUnitPath(str(0))}, ""
+	case "StartUnit", "RestartUnit", "ReloadOrTryRestartUnit":
+		if _, ok := fb.unitFiles[str(0)]; !ok {
+			return "", nil, "org.freedesktop.systemd1.NoSuchUnit"
+		}
+		fb.active[str(0)] = "active"
+		return "o", []interface{}{"/org/freedesktop/systemd1/job/4711"}, ""
+	case "StopUnit":
+		fb.active[str(0)] = "inactive"
+		return "o", []interface{}{"/org/freedesktop/systemd1/job/4712"}, ""
+	case "EnableUnitFiles", "DisableUnitFiles":
+		state := "enabled"
+		if member == "DisableUnitFiles" {
+			state = "disabled"
+		}
+		for _, unit := range msg.Body[0].([]interface{}) {
+			fb.unitFiles[unit.(string)] = state
+		}
+		if member == "EnableUnitFiles" {
+			return "ba(sss)", []interface{}{false, []interface{}{}}, ""
+		}
+		return "a(sss)", []interface{}{[]interface{}{}}, ""
+	case "Reload", "ResetFailed":
+		return "", nil, ""
+	case "GetUnitFileState":
+		if state, ok := fb.unitFiles[str(0)]; ok {
+			return "s", []interface{}{state}, ""
+		}
+		return "", nil, "org.freedesktop.DBus.Error.FileNotFound"
+	case "ListUnitFiles":
+		list := []interface{}{}
+		for unit, state := range fb.unitFiles {
+			list = append(list, []interface{}{"/usr/lib/systemd/system/" + unit, state})
+		}
+		return "a(ss)", []interface{}{list}, ""
+	case "SetUnitProperties":
+		for _, prop := range msg.Body[2].([]interface{}) {
+			fields := prop.([]interface{})
+			if fields[0] == "TasksMax" {
+				fb.tasksMax, _ = fields[1].(dbusVariant).Value.(uint64)
+			}
+		}
+		return "", nil, ""
+	case "ListUsers":
+		return "a(uso)", []interface{}{[]interface{}{[]interface{}{uint32(65534), "nobody", "/org/freedesktop/login1/user/_65534"}}}, ""
+	case "active_profile":
+		return "s", []interface{}{fb.tunedProf}, ""
+	case "switch_profile":
+		if str(0) == "unknown" {
+			return "(bs)", []interface{}{[]interface{}{false, "Requested profile 'unknown' doesn't exist."}}, ""
+		}
+		fb.tunedProf = str(0)
+		return "(bs)", []interface{}{[]interface{}{true, "OK"}}, ""
+	case "disable":
+		fb.tunedProf = ""
+		return "b", []interface{}{true}, ""
+	}
+	return "", nil, "org.freedesktop.DBus.Error.UnknownMethod"
+}
+
+func TestDBusMarshal(t *testing.T) {
+	msg := dbusMessage{
+		Type:   dbusMethodCall,
+		Serial: 7,
+		Fields: map[byte]dbusVariant{
+			dbusFieldPath:      {"o", "/org/freedesktop/systemd1"},
+			dbusFieldMember:    {"s", "Test"},
+			dbusFieldSignature: {"g", "ybnqiuxtdsa(sv)a{sy}"},
+		},
+		Body: []interface{}{byte(1), true, int16(-2), uint16(3), int32(-4), uint32(5), int64(-6), uint64(math.MaxUint64), 0.5, "saptune", []interface{}{[]interface{}{"TasksMax", dbusVariant{"t", uint64(42)}}, []interface{}{"Names", dbusVariant{"as", []interface{}{"a", "b"}}}}, []interface{}{[]interface{}{"key", byte(9)}}},
+	}
+	data, err := dbusMarshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0] != 'l' || data[1] != dbusMethodCall {
+		t.Errorf("unexpected message start '%v'", data[:4])
+	}
+	res, err := dbusReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Type != msg.Type || res.Serial != msg.Serial || res.field(dbusFieldMember) != "Test" {
+		t.Errorf("wrong header '%+v'", res)
+	}
+	if !reflect.DeepEqual(res.Body, msg.Body) {
+		t.Errorf("got '%#v', expected '%#v'", res.Body, msg.Body)
+	}
+
+	// wrong signatures
+	for _, sig := range []string{"a", "(s", "z", "s)"} {
+		msg.Fields[dbusFieldSignature] = dbusVariant{"g", sig}
+		msg.Body = []interface{}{"x"}
+		if _, err := dbusMarshal(msg); err == nil {
+			t.Errorf("signature '%s': expected an error", sig)
+		}
+	}
+	// body does not match the signature
+	msg.Fields[dbusFieldSignature] = dbusVariant{"g", "su"}
+	msg.Body = []interface{}{"x", "y"}
+	if _, err := dbusMarshal(msg); err == nil {
+		t.Error("expected an error")
+	}
+	// truncated message
+	if _, err := dbusReadMessage(bytes.NewReader(data[:len(data)-3])); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestDBusConn(t *testing.T) {
+	_, address := startFakeBus(t)
+	dc, err := dbusDial(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dc.Close()
+	if dc.name != ":1.42" {
+		t.Errorf("wrong unique name '%s'", dc.name)
+	}
+	val, err := dc.GetProperty(systemdBus, systemdPath, systemdManager, "SystemState")
+	if err != nil || val != "running" {
+		t.Errorf("got '%v', '%v'", val, err)
+	}
+	_, err = dc.Call(systemdBus, systemdPath, systemdManager, "Unknown", "")
+	if derr, ok := err.(dbusErr); !ok || derr.Name != "org.freedesktop.DBus.Error.UnknownMethod" {
+		t.Errorf("expected an UnknownMethod error, got '%v'", err)
+	}
+
+	if _, err := dbusDial("tcp:host=localhost,port=4711"); err == nil {
+		t.Error("expected an error for an unsupported address")
+	}
+	if _, err := dbusDial("unix:path=" + path.Join(t.TempDir(), "nobus")); err == nil {
+		t.Error("expected an error for a missing socket")
+	}
+}
+
+func TestDBusConnConcurrent(t *testing.T) {
+	fb, address := startFakeBus(t)
+	fb.active["sapconf.service"] = "failed"
+	dc, err := dbusDial(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dc.Close()
+	// each caller has to get the reply of its own call
+	wg := sync.WaitGroup{}
+	for cnt := 0; cnt < 20; cnt++ {
+		for unit, exp := range map[string]string{"saptune.service": "inactive", "tuned.service": "active", "sapconf.service": "failed"} {
+			wg.Add(1)
+			go func(unit, exp string) {
+				defer wg.Done()
+				val, err := dc.GetProperty(systemdBus, fakeUnitPath(unit), systemdUnit, "ActiveState")
+				if err != nil || val != exp {
+					t.Errorf("'%s': got '%v', '%v', expected '%s'", unit, val, err, exp)
+				}
+			}(unit, exp)
+		}
+	}
+	wg.Wait()
+
+	// a closed connection is reported as connection error
+	fb.dropConnections()
+	if _, err := dc.Call(systemdBus, systemdPath, systemdManager, "ResetFailed", ""); err == nil {
+		t.Error("expected an error")
+	} else if _, ok := err.(dbusConnErr); !ok {
+		t.Errorf("expected a connection error, got '%T' - '%v'", err, err)
+	}
+}
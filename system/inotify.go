@@ -0,0 +1,103 @@
+package system
+
+import (
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// inotify events, which indicate a content change of a watched file or
+// directory
+const watchMask = syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_ATTRIB
+
+// FileWatcher watches files and directories for changes using inotify
+type FileWatcher struct {
+	file    *os.File // non-blocking inotify fd, Close unblocks a pending Read
+	mu      sync.Mutex
+	watches map[int]string
+	Events  chan string // name of the changed file or directory
+}
+
+// NewFileWatcher creates an inotify based watcher for the given files and
+// directories. Not existing files are skipped silently, because inotify is
+// not able to watch them. /proc and /sys are not supported by inotify.
+func NewFileWatcher(files []string) (*FileWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	fw := &FileWatcher{
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		watches: make(map[int]string),
+		Events:  make(chan string, 16),
+	}
+	for _, file := range files {
+		fw.Add(file)
+	}
+	go fw.readEvents()
+	return fw, nil
+}
+
+// Add starts watching the given file or directory. A not existing file is
+// skipped silently, a file, which can not be watched, is logged.
+func (fw *FileWatcher) Add(file string) {
+	if _, err := os.Stat(file); err != nil {
+		DebugLog("FileWatcher.Add - skip not existing file '%s'", file)
+		return
+	}
+	wd, err := syscall.InotifyAddWatch(int(fw.file.Fd()), file, watchMask)
+	if err != nil {
+		WarningLog("can not watch file '%s' for changes - %v", file, err)
+		return
+	}
+	fw.mu.Lock()
+	fw.watches[wd] = file
+	fw.mu.Unlock()
+}
+
+// Watched returns the number of successfully watched files
+func (fw *FileWatcher) Watched() int {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	return len(fw.watches)
+}
+
+// watchedFile returns the name of the file of the watch descriptor
+func (fw *FileWatcher) watchedFile(wd int) (string, bool) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	name, ok := fw.watches[wd]
+	return name, ok
+}
+
+// Close stops watching. The reading goroutine ends and closes the Events
+// channel
+func (fw *FileWatcher) Close() {
+	_ = fw.file.Close()
+}
+
+// readEvents reads the inotify events and sends the name of the changed
+// file or directory to the Events channel
+func (fw *FileWatcher) readEvents() {
+	var buf [syscall.SizeofInotifyEvent * 64]byte
+	defer close(fw.Events)
+	for {
+		n, err := fw.file.Read(buf[:])
+		if err != nil || n <= 0 {
+			return
+		}
+		offset := 0
+		for offset <= n-syscall.SizeofInotifyEvent {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			if name, ok := fw.watchedFile(int(event.Wd)); ok {
+				select {
+				case fw.Events <- name:
+				default:
+					// channel full, a change is already pending
+				}
+			}
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package system
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestFileWatcher(t *testing.T) {
+	tstDir, err := ioutil.TempDir("", "saptune_watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tstDir)
+	tstFile := path.Join(tstDir, "watched")
+	if err := ioutil.WriteFile(tstFile, []byte("start"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fw, err := NewFileWatcher([]string{tstFile, "/file_does_not_exist"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fw.Close()
+	if fw.Watched() != 1 {
+		t.Errorf("expected 1 watched file, got '%d'\n", fw.Watched())
+	}
+	if err := ioutil.WriteFile(tstFile, []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case name := <-fw.Events:
+		if name != tstFile {
+			t.Errorf("got event for '%s', expected '%s'\n", name, tstFile)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("no change event received for '%s'\n", tstFile)
+	}
+}
+
+func TestFileWatcherClose(t *testing.T) {
+	tstDir := t.TempDir()
+	fw, err := NewFileWatcher([]string{tstDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the reader is blocked waiting for events, Close has to end it
+	fw.Close()
+	timeout := time.After(5 * time.Second)
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-fw.Events:
+			closed = !ok
+		case <-timeout:
+			t.Fatalf("reading goroutine not stopped by Close\n")
+		}
+	}
+	fw.Add(path.Join(tstDir, "does_not_exist"))
+	if fw.Watched() != 1 {
+		t.Errorf("expected 1 watched file, got '%d'\n", fw.Watched())
+	}
+}
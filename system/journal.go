@@ -0,0 +1,70 @@
+package system
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// journal priorities as defined by syslog(3)
+const (
+	JournalPrioErr     = 3
+	JournalPrioWarning = 4
+	JournalPrioNotice  = 5
+	JournalPrioInfo    = 6
+)
+
+// journalSocket is the socket of the systemd journal using the native
+// journal protocol
+var journalSocket = "/run/systemd/journal/socket"
+
+// JournalMsg sends a message with the given priority and additional
+// journal fields to the systemd journal using the native journal protocol.
+// Field names need to be upper case letters, digits and underscores.
+// If the journal is not available, the message is written to stderr
+func JournalMsg(prio int, msg string, fields map[string]string) error {
+	payload := journalPayload(prio, msg, fields)
+	conn, err := net.Dial("unixgram", journalSocket)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", msg)
+		return err
+	}
+	defer conn.Close()
+	if _, err = conn.Write(payload); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", msg)
+	}
+	return err
+}
+
+// journalPayload builds the datagram for the native journal protocol
+// values containing a newline need the binary safe serialization
+func journalPayload(prio int, msg string, fields map[string]string) []byte {
+	payload := bytes.Buffer{}
+	writeJournalField(&payload, "PRIORITY", fmt.Sprintf("%d", prio))
+	writeJournalField(&payload, "SYSLOG_IDENTIFIER", "saptune")
+	writeJournalField(&payload, "MESSAGE", msg)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		writeJournalField(&payload, strings.ToUpper(key), fields[key])
+	}
+	return payload.Bytes()
+}
+
+// writeJournalField appends a single field to the journal payload
+func writeJournalField(payload *bytes.Buffer, key, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(payload, "%s=%s\n", key, value)
+		return
+	}
+	payload.WriteString(key + "\n")
+	_ = binary.Write(payload, binary.LittleEndian, uint64(len(value)))
+	payload.WriteString(value + "\n")
+}
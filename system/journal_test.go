@@ -0,0 +1,34 @@
+package system
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestJournalPayload(t *testing.T) {
+	fields := map[string]string{"SAPTUNE_NOTE": "1680803", "saptune_param": "vm.swappiness"}
+	payload := journalPayload(JournalPrioWarning, "drift detected", fields)
+	want := "PRIORITY=4\nSYSLOG_IDENTIFIER=saptune\nMESSAGE=drift detected\nSAPTUNE_NOTE=1680803\nSAPTUNE_PARAM=vm.swappiness\n"
+	if string(payload) != want {
+		t.Errorf("got: '%s', expected: '%s'\n", string(payload), want)
+	}
+
+	// multiline values need the binary safe serialization
+	payload = journalPayload(JournalPrioInfo, "line1\nline2", nil)
+	size := make([]byte, 8)
+	binary.LittleEndian.PutUint64(size, uint64(len("line1\nline2")))
+	want = "PRIORITY=6\nSYSLOG_IDENTIFIER=saptune\nMESSAGE\n" + string(size) + "line1\nline2\n"
+	if !bytes.Equal(payload, []byte(want)) {
+		t.Errorf("got: '%q', expected: '%q'\n", string(payload), want)
+	}
+}
+
+func TestJournalMsg(t *testing.T) {
+	oldSocket := journalSocket
+	defer func() { journalSocket = oldSocket }()
+	journalSocket = "/tmp/saptune_no_journal_socket"
+	if err := JournalMsg(JournalPrioInfo, "test message", nil); err == nil {
+		t.Errorf("expected an error for a missing journal socket\n")
+	}
+}
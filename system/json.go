@@ -5,15 +5,28 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
 var schemaDir = "file:///usr/share/saptune/schemas/1.0/"
-var supportedRAC = map[string]bool{"daemon start": false, "daemon status": true, "daemon stop": false, "service apply": false, "service start": false, "service status": true, "service stop": false, "service restart": false, "service revert": false, "service reload": false, "service takeover": false, "service enable": false, "service disable": false, "service enablestart": false, "service disablestop": false, "note list": true, "note revertall": false, "note enabled": true, "note applied": true, "note apply": false, "note simulate": false, "note customise": false, "note create": false, "note edit": false, "note revert": false, "note show": false, "note delete": false, "note verify": true, "note rename": false, "solution list": true, "solution verify": true, "solution enabled": true, "solution applied": true, "solution apply": false, "solution simulate": false, "solution customise": false, "solution create": false, "solution edit": false, "solution revert": false, "solution show": false, "solution delete": false, "solution rename": false, "staging status": false, "staging enable": false, "staging disable": false, "staging is-enabled": false, "staging list": false, "staging diff": false, "staging analysis": false, "staging release": false, "revert all": false, "lock remove": false, "check": false, "status": true, "version": true, "help": false}
+var supportedRAC = map[string]bool{"daemon start": false, "daemon status": true, "daemon stop": false, "service apply": false, "service start": false, "service status": true, "service stop": false, "service restart": false, "service revert": false, "service reload": false, "service takeover": false, "service enable": false, "service disable": false, "service enablestart": false, "service disablestop": false, "service driftcheck": false, "parameter list": true, "parameter show": true, "parameter revert": false, "note list": true, "note revertall": false, "note enabled": true, "note applied": true, "note apply": true, "note simulate": true, "note customise": false, "note create": false, "note edit": false, "note revert": true, "note show": false, "note delete": false, "note verify": true, "note lint": true, "note rename": false, "solution list": true, "solution verify": true, "solution enabled": true, "solution applied": true, "solution apply": true, "solution simulate": true, "solution customise": false, "solution create": false, "solution edit": false, "solution revert": true, "solution show": false, "solution delete": false, "solution rename": false, "staging status": true, "staging enable": false, "staging disable": false, "staging is-enabled": false, "staging list": true, "staging diff": false, "staging analysis": false, "staging release": true, "revert all": true, "configure": false, "config export": false, "config import": false, "lock remove": false, "check": false, "status": true, "compare": true, "baseline save": true, "baseline check": true, "version": true, "help": false}
 
 // jentry is the json entry to display
 var jentry JEntry
 
+// jRunning is set while JRun runs a saptune command within the current
+// process, so the json entry is returned instead of written to stdout
+var jRunning bool
+
+// jRunMu serialises JRun, as the command line, the json entry and the exit
+// function are global
+var jRunMu sync.Mutex
+
+// jExit carries the exit code of a command run by JRun from ErrorExit back
+// to JRun
+type jExit int
+
 // JMsg is a single log message and it's severity/priority
 type JMsg struct {
 	// Priority of the log messages as defined AT
@@ -105,16 +118,17 @@ type notesOrder struct {
 
 // JNoteListEntry is one line of 'saptune note list'
 type JNoteListEntry struct {
-	NoteID       string `json:"Note ID"`
-	NoteDesc     string `json:"Note description"`
-	NoteRef      JObj   `json:"Note reference"`
-	NoteVers     string `json:"Note version"`
-	NoteRdate    string `json:"Note release date"`
-	ManEnabled   bool   `json:"Note enabled manually"`
-	SolEnabled   bool   `json:"Note enabled by Solution"`
-	ManReverted  bool   `json:"Note reverted manually"`
-	NoteOverride bool   `json:"Note override exists"`
-	CustomNote   bool   `json:"custom Note"`
+	NoteID       string   `json:"Note ID"`
+	NoteDesc     string   `json:"Note description"`
+	NoteRef      JObj     `json:"Note reference"`
+	NoteVers     string   `json:"Note version"`
+	NoteRdate    string   `json:"Note release date"`
+	ManEnabled   bool     `json:"Note enabled manually"`
+	SolEnabled   bool     `json:"Note enabled by Solution"`
+	ManReverted  bool     `json:"Note reverted manually"`
+	NoteOverride bool     `json:"Note override exists"`
+	CustomNote   bool     `json:"custom Note"`
+	Conflicts    []string `json:"Note conflicts with"`
 }
 
 // JNoteList is the whole 'saptune note list'
@@ -127,15 +141,35 @@ type JNoteList struct {
 // JPNotesLine one row of 'saptune note verify|simulate'
 // from PrintNoteFields
 type JPNotesLine struct {
-	NoteID    string       `json:"Note ID,omitempty"`
-	NoteVers  string       `json:"Note version,omitempty"`
-	Parameter string       `json:"parameter"`
-	Compliant *bool        `json:"compliant,omitempty"`
-	ExpValue  string       `json:"expected value,omitempty"`
-	OverValue string       `json:"override value,omitempty"`
-	ActValue  *string      `json:"actual value,omitempty"`
-	Comment   string       `json:"comment,omitempty"`
-	Footnotes []JFootNotes `json:"amendments,omitempty"`
+	NoteID     string       `json:"Note ID,omitempty"`
+	NoteVers   string       `json:"Note version,omitempty"`
+	Parameter  string       `json:"parameter"`
+	Compliant  *bool        `json:"compliant,omitempty"`
+	ExpValue   string       `json:"expected value,omitempty"`
+	OverValue  string       `json:"override value,omitempty"`
+	ActValue   *string      `json:"actual value,omitempty"`
+	Comment    string       `json:"comment,omitempty"`
+	Footnotes  []JFootNotes `json:"amendments,omitempty"`
+	Owner      string       `json:"owner,omitempty"`
+	DefinedBy  []JParamNote `json:"also defined by,omitempty"`
+	OverActive bool         `json:"override active,omitempty"`
+	OverMeta   *JOverMeta   `json:"override metadata,omitempty"`
+	Template   string       `json:"template,omitempty"`
+	Severity   string       `json:"severity,omitempty"`
+}
+
+// JOverMeta is the metadata of an overridden parameter from the override file
+type JOverMeta struct {
+	Reason     string `json:"reason,omitempty"`
+	Expires    string `json:"expires,omitempty"`
+	ApprovedBy string `json:"approved by,omitempty"`
+	Expired    bool   `json:"expired"`
+}
+
+// JParamNote is a Note defining a parameter together with its value
+type JParamNote struct {
+	NoteID string `json:"Note ID"`
+	Value  string `json:"value"`
 }
 
 // JFootNotes collects the footnotes per parameter
@@ -159,6 +193,137 @@ type JPNotes struct {
 	Attentions    []JPNotesRemind `json:"attentions,omitempty"`
 	NotesOrder    []string        `json:"Notes enabled,omitempty"`
 	SysCompliance *bool           `json:"system compliance,omitempty"`
+	Summary       *JVerifySummary `json:"summary,omitempty"`
+	Host          *JHostFacts     `json:"host,omitempty"`
+}
+
+// JStaging is the result of 'saptune staging status' and
+// 'saptune staging list'
+type JStaging struct {
+	Enabled bool            `json:"staging enabled"`
+	Staged  []JStagingEntry `json:"staged,omitempty"`
+}
+
+// JStagingEntry is a Note or Solution in the staging area. State is
+// 'new', 'updated' or 'deleted'
+type JStagingEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	State       string `json:"state,omitempty"`
+}
+
+// JHostFacts are the facts of the verified host, used by 'saptune compare'
+type JHostFacts struct {
+	Hostname  string `json:"hostname"`
+	VirtEnv   string `json:"virtualization"`
+	CSP       string `json:"cloud service provider"`
+	OsVersion string `json:"os version"`
+	Arch      string `json:"architecture"`
+}
+
+// JCompare is the result of 'saptune compare'
+type JCompare struct {
+	Hosts       []JCompareHost `json:"hosts"`
+	ParamsCount int            `json:"parameters compared"`
+	Differences []JCompareLine `json:"differences"`
+}
+
+// JCompareHost is a verify snapshot compared by 'saptune compare'
+type JCompareHost struct {
+	File       string      `json:"file"`
+	Created    string      `json:"publish time"`
+	Facts      *JHostFacts `json:"host,omitempty"`
+	NotesOrder []string    `json:"Notes enabled"`
+	Compliance *bool       `json:"system compliance,omitempty"`
+}
+
+// JCompareLine is a parameter differing between the compared hosts
+type JCompareLine struct {
+	NoteID    string          `json:"Note ID"`
+	Parameter string          `json:"parameter"`
+	Diffs     []string        `json:"differences"`
+	Values    []JCompareValue `json:"values"`
+}
+
+// JCompareValue are the values of a parameter on one of the compared hosts.
+// Available is false, if the parameter is not verified on the host
+type JCompareValue struct {
+	Available bool    `json:"available"`
+	ExpValue  string  `json:"expected value,omitempty"`
+	ActValue  *string `json:"actual value,omitempty"`
+	Compliant *bool   `json:"compliant,omitempty"`
+}
+
+// JBaseline is a recorded known-good state of the parameters managed by
+// the enabled Notes, written by 'saptune baseline save'
+type JBaseline struct {
+	Name       string           `json:"name"`
+	Created    string           `json:"publish time"`
+	Host       *JHostFacts      `json:"host,omitempty"`
+	NotesOrder []string         `json:"Notes enabled"`
+	Params     []JBaselineParam `json:"parameters"`
+}
+
+// JBaselineParam is the recorded value of a parameter
+type JBaselineParam struct {
+	Parameter string   `json:"parameter"`
+	Value     string   `json:"value"`
+	Notes     []string `json:"Notes"`
+	Compliant bool     `json:"compliant"`
+}
+
+// JBaselineCheck is the result of 'saptune baseline check'
+type JBaselineCheck struct {
+	Name        string            `json:"name"`
+	Created     string            `json:"baseline time"`
+	ParamsCount int               `json:"parameters checked"`
+	Changes     []JBaselineChange `json:"changes"`
+}
+
+// JBaselineChange is a parameter changed since the baseline was saved.
+// Change is 'changed', 'added' (not part of the baseline) or 'removed'
+// (no longer managed by the enabled Notes)
+type JBaselineChange struct {
+	Parameter     string   `json:"parameter"`
+	Notes         []string `json:"Notes"`
+	Change        string   `json:"change"`
+	BaseValue     *string  `json:"baseline value,omitempty"`
+	ActValue      *string  `json:"current value,omitempty"`
+	BaseCompliant *bool    `json:"baseline compliant,omitempty"`
+	Compliant     *bool    `json:"compliant,omitempty"`
+}
+
+// JVerifySummary is the compliance summary of 'saptune verify --summary'
+type JVerifySummary struct {
+	Score        int             `json:"score"`
+	Counts       JComplCounts    `json:"counts"`
+	Notes        []JSummaryEntry `json:"Notes"`
+	Sections     []JSummaryEntry `json:"sections"`
+	TopOffenders []JOffender     `json:"top offenders"`
+}
+
+// JComplCounts are the number of parameters per compliance category
+type JComplCounts struct {
+	Compliant     int `json:"compliant"`
+	NonCompliant  int `json:"non-compliant"`
+	NotApplicable int `json:"not applicable"`
+	Untouched     int `json:"untouched"`
+	Informational int `json:"informational"`
+}
+
+// JSummaryEntry is the compliance summary of a Note or a section
+type JSummaryEntry struct {
+	Name   string       `json:"name"`
+	Score  int          `json:"score"`
+	Counts JComplCounts `json:"counts"`
+}
+
+// JOffender is a non-compliant parameter of the compliance summary
+type JOffender struct {
+	Parameter string   `json:"parameter"`
+	Notes     []string `json:"Notes"`
+	ExpValue  string   `json:"expected value"`
+	ActValue  string   `json:"actual value"`
 }
 
 // JSol - Solution name and related Note list
@@ -183,6 +348,7 @@ type JStatus struct {
 	EnabledNotes    []string       `json:"Notes enabled"`
 	AppliedNotes    []string       `json:"Notes applied"`
 	Staging         JStatusStaging `json:"staging"`
+	Conflicts       []JConflict    `json:"conflicts"`
 	Msg             string         `json:"remember message"`
 }
 
@@ -193,6 +359,14 @@ type JStatusStaging struct {
 	StagedSols     []string `json:"Solutions staged"`
 }
 
+// JConflict is a component competing with saptune in 'saptune status'
+type JConflict struct {
+	Component string   `json:"component"`
+	Source    string   `json:"source"`
+	Params    []string `json:"parameters"`
+	Tunes     string   `json:"tunes,omitempty"`
+}
+
 // JStatusServs are the mentioned systemd services in 'saptune status'
 type JStatusServs struct {
 	SaptuneService JObj    `json:"saptune"`
@@ -201,6 +375,71 @@ type JStatusServs struct {
 	TunedProfile   *string `json:"tuned profile,omitempty"`
 }
 
+// JParamListEntry is one line of 'saptune parameter list'
+type JParamListEntry struct {
+	Parameter  string       `json:"parameter"`
+	StartValue string       `json:"start value"`
+	Owner      string       `json:"owner"`
+	Notes      []JParamNote `json:"Notes"`
+}
+
+// JParamList is the whole 'saptune parameter list'
+type JParamList struct {
+	ParamsList []JParamListEntry `json:"parameters changed"`
+}
+
+// JParamOverride is an override file defining a parameter
+type JParamOverride struct {
+	NoteID string `json:"Note ID"`
+	File   string `json:"file"`
+	Value  string `json:"value"`
+}
+
+// JParamShow is the whole 'saptune parameter show'
+type JParamShow struct {
+	Parameter  string           `json:"parameter"`
+	ActValue   *string          `json:"current value"`
+	StartValue string           `json:"start value"`
+	Owner      string           `json:"owner"`
+	Notes      []JParamNote     `json:"Notes"`
+	Overrides  []JParamOverride `json:"overrides"`
+}
+
+// JNoteLint is the whole 'saptune note lint'
+type JNoteLint struct {
+	File     string         `json:"file"`
+	Findings []JLintFinding `json:"findings"`
+	Errors   int            `json:"errors"`
+	Warnings int            `json:"warnings"`
+}
+
+// JLintFinding is a single problem found by 'saptune note lint'
+type JLintFinding struct {
+	Line     int    `json:"line"`
+	Section  string `json:"section,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// JCustomise is the whole non-interactive 'saptune note customise' and
+// 'saptune solution customise'
+type JCustomise struct {
+	Name    string             `json:"name"`
+	File    string             `json:"file"`
+	Changes []JCustomiseChange `json:"changes"`
+	Applied bool               `json:"applied"`
+	Reapply bool               `json:"reapply needed"`
+}
+
+// JCustomiseChange is a single change of 'saptune note customise' or
+// 'saptune solution customise'
+type JCustomiseChange struct {
+	Parameter string `json:"parameter"`
+	Action    string `json:"action"`
+	OldValue  string `json:"old value,omitempty"`
+	NewValue  string `json:"new value,omitempty"`
+}
+
 // JSolListEntry is one line of 'saptune solution list'
 type JSolListEntry struct {
 	SolName     string   `json:"Solution ID"`
@@ -238,7 +477,7 @@ func jInit() {
 // used in system/logging
 func jWriteMsg(prio, msg string) {
 	var jmsg JMsg
-	if GetFlagVal("format") != "json" {
+	if !StructuredOut() {
 		return
 	}
 	jmsg.Prio = prio
@@ -250,12 +489,19 @@ func jWriteMsg(prio, msg string) {
 // used in function system/ErrorExit
 func jOut(exit int) error {
 	var err error
-	if GetFlagVal("format") != "json" {
+	if !StructuredOut() {
 		return err
 	}
 	// reset stdout to original setting
 	os.Stdout = stdOutOrg
 	jentry.CmdRet = exit
+	if jRunning {
+		// JRun returns the json entry to its caller
+		return err
+	}
+	if IsReportFormat() {
+		return reportOut()
+	}
 	data, err := json.Marshal(jentry)
 	if err == nil {
 		fmt.Println(string(data))
@@ -263,6 +509,44 @@ func jOut(exit int) error {
 	return err
 }
 
+// JRun runs the saptune command 'run' within the current process like a
+// call of saptune with the arguments 'args' and the option '--format=json'
+// and returns the collected json entry including the exit code.
+// An error exit of the command only ends the command, not the process.
+// used by the saptune API
+func JRun(args []string, run func()) JEntry {
+	jRunMu.Lock()
+	defer jRunMu.Unlock()
+	oldArgs, oldOSExit, oldStdout := os.Args, OSExit, os.Stdout
+	devNull, _ := os.Open(os.DevNull)
+	defer func() {
+		ReleaseSaptuneLock()
+		os.Args, OSExit, os.Stdout = oldArgs, oldOSExit, oldStdout
+		RereadArgs()
+		jRunning = false
+		devNull.Close()
+	}()
+	os.Args = append([]string{oldArgs[0], "--format=json"}, args...)
+	RereadArgs()
+	OSExit = func(exit int) { panic(jExit(exit)) }
+	// the screen output of the command is not needed
+	os.Stdout = devNull
+	jRunning = true
+	jInit()
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(jExit); !ok {
+					panic(r)
+				}
+			}
+		}()
+		run()
+		ErrorExit("", 0)
+	}()
+	return jentry
+}
+
 // JInvalid is the answer of an invalid saptune call
 // used in function action/PrintHelpAndExit
 func JInvalid(exitStatus int) {
@@ -277,6 +561,9 @@ func JInvalid(exitStatus int) {
 // used in function action/SelectAction
 func JnotSupportedYet() {
 	rac := realmAndCmd()
+	if IsReportFormat() && !reportRAC[rac] {
+		ErrorExit("output format '%s' is not supported for 'saptune %s'", GetFlagVal("format"), rac)
+	}
 	if GetFlagVal("format") != "json" || racIsSupported(rac) {
 		return
 	}
@@ -287,7 +574,7 @@ func JnotSupportedYet() {
 // Jcollect collects the result data
 func Jcollect(data interface{}) {
 	rac := realmAndCmd()
-	if GetFlagVal("format") != "json" || !racIsSupported(rac) {
+	if !StructuredOut() || !racIsSupported(rac) || (IsReportFormat() && !reportRAC[rac]) {
 		return
 	}
 	switch res := data.(type) {
@@ -317,7 +604,10 @@ func Jcollect(data interface{}) {
 		var appSol appliedSol
 		appSol.AppliedSol = append(appSol.AppliedSol, res)
 		jentry.CmdResult = appSol
-	case JSolList, JNoteList, JStatus, JPNotes:
+	case []JAppliedSol:
+		// "saptune solution applied" with more than one solution
+		jentry.CmdResult = appliedSol{AppliedSol: res}
+	case JSolList, JNoteList, JStatus, JPNotes, JParamList, JParamShow, JNoteLint, JCustomise, JCompare, JBaseline, JBaselineCheck, JStaging:
 		//"solution list", "note list", "status", "daemon status", "service status", "note verify", "solution verify", "note simulate", "solution simulate":
 		jentry.CmdResult = res
 	default:
@@ -331,8 +621,12 @@ func schemaName(name string) string {
 }
 
 // realmAndCmd returns the realms name and the command name, if available
+// The arguments of commands without realm (e.g. 'compare') are skipped
 func realmAndCmd() string {
 	rac := CliArg(1)
+	if node, depth := FindCommand([]string{rac}); depth == 1 && len(node.Commands) == 0 {
+		return rac
+	}
 	if CliArg(2) != "" {
 		rac = rac + " " + CliArg(2)
 	}
@@ -341,6 +635,13 @@ func realmAndCmd() string {
 
 // racIsSupported checks, if the combination 'realm command' has json support
 func racIsSupported(rac string) bool {
+	switch rac {
+	case "note customise":
+		// only the non-interactive customise supports json
+		return IsFlagSet("set") || IsFlagSet("unset") || IsFlagSet("untouched")
+	case "solution customise":
+		return IsFlagSet("add-note") || IsFlagSet("remove-note")
+	}
 	if _, ok := supportedRAC[rac]; !ok {
 		// rac not a valid combination
 		// return true to let PrintHelpAndExit later do it's job
@@ -5,83 +5,206 @@ import (
 	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 	"syscall"
+	"time"
 )
 
 // saptune lock file
 var stLockFile = "/run/.saptune.lock"
 
-// isOwnLock return true, if lock file is from the current running process
-// pid inside the lock file is the pid of current running saptune instance
+// modes of the saptune lock. Commands, which only read the system and the
+// configuration, share the lock, all other commands need it exclusively
+const (
+	LockShared    = syscall.LOCK_SH
+	LockExclusive = syscall.LOCK_EX
+)
+
+// stLock is the open lock file as long as the current process holds the
+// saptune lock. The lock itself is a flock(2) on this file, which is
+// released by the kernel, if the process dies. So it can not get stale.
+var stLock *os.File
+var stLockMode = LockExclusive
+
+// lockPollInterval is the interval to retry the lock while waiting for it
+var lockPollInterval = 200 * time.Millisecond
+
+// isOwnLock returns true, if the current process holds the saptune lock
 func isOwnLock() bool {
-	if !saptuneIsLocked() {
-		// no lock file found, return false
-		return false
-	}
-	p, err := ioutil.ReadFile(stLockFile)
+	return stLock != nil
+}
+
+// HoldsExclusiveLock returns true, if the current process holds the saptune
+// lock exclusively, so it is the only saptune process changing the system
+// or the configuration
+func HoldsExclusiveLock() bool {
+	return isOwnLock() && stLockMode == LockExclusive
+}
+
+// SaptuneLock sets the saptune lock in the given mode (LockShared or
+// LockExclusive). If the lock is held by another saptune process, it waits
+// up to 'wait' for the lock before exiting with 11
+func SaptuneLock(mode int, wait time.Duration) {
+	locked, err := waitForLock(mode, wait)
 	if err != nil {
-		ErrorLog("problems during reading the lock file - '%v'", err)
-		ReleaseSaptuneLock()
-		OSExit(99)
-	}
-	// file exists, check if empty or if pid inside is from a dead process
-	// if yes, remove file and return false
-	pid, _ := strconv.Atoi(string(p))
-	return pid == os.Getpid()
+		ErrorExit("problems setting lock - %v", err, 12)
+	}
+	if locked {
+		return
+	}
+	if wait > 0 {
+		ErrorExit("saptune still in use after waiting %v, try later ...", wait, 11)
+	}
+	ErrorExit("saptune currently in use, try later or use '--wait=TIMEOUT' ...", 11)
 }
 
-// SaptuneLock creates the saptune lock file
-func SaptuneLock() {
-	// check for saptune lock file
-	if saptuneIsLocked() {
-		ErrorExit("saptune currently in use, try later ...", 11)
+// TrySaptuneLock sets the exclusive saptune lock, if saptune is not locked
+// by another process.
+// returns false instead of exiting, if the lock could not be set
+func TrySaptuneLock() bool {
+	if isOwnLock() {
+		return true
 	}
-	stLock, err := os.OpenFile(stLockFile, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0600)
+	locked, err := setLock(LockExclusive)
 	if err != nil {
-		ErrorExit("problems setting lock", 12)
-	} else {
-		fmt.Fprintf(stLock, "%d", os.Getpid())
+		WarningLog("problems setting lock - %v", err)
 	}
-	stLock.Close()
+	return locked
 }
 
-// saptuneIsLocked checks, if the lock file for saptune exists
-func saptuneIsLocked() bool {
-	f, err := os.Stat(stLockFile)
-	if os.IsNotExist(err) {
-		return false
+// waitForLock retries to set the lock until it succeeds or 'wait' is over
+func waitForLock(mode int, wait time.Duration) (bool, error) {
+	deadline := time.Now().Add(wait)
+	logged := false
+	for {
+		locked, err := setLock(mode)
+		if locked || err != nil || !time.Now().Before(deadline) {
+			return locked, err
+		}
+		if !logged {
+			NoticeLog("saptune currently in use, waiting up to %v for the lock ...", wait)
+			logged = true
+		}
+		time.Sleep(lockPollInterval)
 	}
-	// file is empty, remove file and return false
-	if f.Size() == 0 {
-		ReleaseSaptuneLock()
-		return false
+}
+
+// setLock tries to set the saptune lock without blocking.
+// The pid written by exclusive lock holders is only informational, it is
+// used to detect the lock files of older saptune versions, which do not
+// use flock.
+func setLock(mode int) (bool, error) {
+	if pid := legacyLockPid(); pid != 0 {
+		return false, nil
 	}
-	// file exists, read content
-	p, err := ioutil.ReadFile(stLockFile)
+	lockFile, err := os.OpenFile(stLockFile, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return false, err
+	}
+	if err := syscall.Flock(int(lockFile.Fd()), mode|syscall.LOCK_NB); err != nil {
+		lockFile.Close()
+		if err == syscall.EWOULDBLOCK {
+			return false, nil
+		}
+		return false, err
+	}
+	if mode == LockExclusive {
+		_ = lockFile.Truncate(0)
+		fmt.Fprintf(lockFile, "%d", os.Getpid())
+	}
+	stLock = lockFile
+	stLockMode = mode
+	return true, nil
+}
+
+// flockIsHeld checks, if a flock is set on the saptune lock file
+func flockIsHeld() bool {
+	lockFile, err := os.Open(stLockFile)
 	if err != nil {
-		ErrorLog("problems during reading the lock file - '%v'", err)
-		ReleaseSaptuneLock()
-		OSExit(99)
-	}
-	// file contains a pid. Check, if process is still alive
-	// if not (dead process) remove file and return false
-	// TODO - check, if p is really a pid
-	pid, _ := strconv.Atoi(string(p))
-	if err := syscall.Kill(pid, syscall.Signal(0)); err == nil {
-		// process exists, must not be the same process, which
-		// created the lock file. Will be checked in ErrorExit
+		return false
+	}
+	defer lockFile.Close()
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
 		return true
 	}
-	// process does not exists
-	ReleaseSaptuneLock()
+	_ = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
 	return false
 }
 
-// ReleaseSaptuneLock removes the saptune lock file
+// legacyLockPid returns the pid found in the lock file, if it belongs to
+// another running saptune process, otherwise 0.
+// Older saptune versions only create the lock file containing their pid.
+// A pid of a dead process or of a process, which is not saptune (the pid
+// was reused after a crash), is stale.
+func legacyLockPid() int {
+	content, err := ioutil.ReadFile(stLockFile)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil || pid <= 0 || pid == os.Getpid() || !isSaptuneProcess(pid) {
+		return 0
+	}
+	return pid
+}
+
+// isSaptuneProcess checks, if the process with the given pid is alive and
+// is a saptune process
+func isSaptuneProcess(pid int) bool {
+	if err := syscall.Kill(pid, syscall.Signal(0)); err != nil {
+		return false
+	}
+	comm, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	return err == nil && strings.TrimSpace(string(comm)) == "saptune"
+}
+
+// ReleaseSaptuneLock releases the saptune lock of the current process.
+// The lock file is not removed, as other saptune processes may already
+// wait for a lock on it
 func ReleaseSaptuneLock() {
-	if err := os.Remove(stLockFile); os.IsNotExist(err) {
-		// no lock file available, nothing to do
-	} else if err != nil {
-		ErrorLog("problems removing lock. Please remove lock file '%s' manually before the next start of saptune.\n", stLockFile)
+	if stLock == nil {
+		// no lock held, nothing to do
+		return
+	}
+	if stLockMode == LockExclusive {
+		_ = stLock.Truncate(0)
+	}
+	if err := syscall.Flock(int(stLock.Fd()), syscall.LOCK_UN); err != nil {
+		ErrorLog("problems releasing lock '%s' - %v", stLockFile, err)
+	}
+	stLock.Close()
+	stLock = nil
+}
+
+// RemoveSaptuneLock removes a pending lock file, e.g. of a former saptune
+// call of an older saptune version. A lock file locked by a running saptune
+// is not removed
+func RemoveSaptuneLock() error {
+	if flockIsHeld() {
+		return fmt.Errorf("lock file '%s' is in use by a running saptune, not removed", stLockFile)
+	}
+	if err := os.Remove(stLockFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("problems removing lock. Please remove lock file '%s' manually before the next start of saptune - %v", stLockFile, err)
+	}
+	return nil
+}
+
+// LockWait returns the time to wait for the saptune lock from the option
+// '--wait=TIMEOUT' or 0, if the option is not set
+func LockWait() time.Duration {
+	wait, _ := parseLockWait(GetFlagVal("wait"))
+	return wait
+}
+
+// parseLockWait parses the value of the option '--wait'. The timeout is
+// given in seconds or as duration like '90s' or '5m'
+func parseLockWait(val string) (time.Duration, bool) {
+	if val == "" {
+		return 0, true
+	}
+	if secs, err := strconv.Atoi(val); err == nil {
+		return time.Duration(secs) * time.Second, secs >= 0
 	}
+	wait, err := time.ParseDuration(val)
+	return wait, err == nil && wait >= 0
 }
@@ -4,54 +4,200 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
+	"path"
 	"strconv"
+	"syscall"
 	"testing"
+	"time"
 )
 
+// foreignLock sets a flock on the saptune lock file using its own file
+// descriptor, which acts like the lock of another saptune process
+func foreignLock(t *testing.T, mode int) *os.File {
+	lockFile, err := os.OpenFile(stLockFile, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		t.Fatalf("can not open lock file - %v", err)
+	}
+	if err := syscall.Flock(int(lockFile.Fd()), mode|syscall.LOCK_NB); err != nil {
+		t.Fatalf("can not set foreign lock - %v", err)
+	}
+	return lockFile
+}
+
 func TestLock(t *testing.T) {
-	if saptuneIsLocked() {
-		_, err := os.Stat(stLockFile)
-		if os.IsNotExist(err) {
-			t.Errorf("saptune lock does NOT exists, but is reported as existing\n")
-		} else {
-			t.Errorf("saptune lock exists, but shouldn't\n")
-		}
+	os.Remove(stLockFile)
+	if flockIsHeld() || legacyLockPid() != 0 {
+		t.Errorf("saptune lock exists, but shouldn't\n")
 	}
-	SaptuneLock()
-	if !saptuneIsLocked() {
-		_, err := os.Stat(stLockFile)
-		if os.IsNotExist(err) {
-			t.Errorf("saptune should be locked, but isn't\n")
-		} else {
-			t.Errorf("saptune lock exists, but is reported as non-existing\n")
-		}
+	SaptuneLock(LockExclusive, 0)
+	if !flockIsHeld() {
+		t.Errorf("saptune should be locked, but isn't\n")
 	}
-	if !isOwnLock() {
-		pid := -1
-		p, err := ioutil.ReadFile(stLockFile)
-		if err == nil {
-			pid, _ = strconv.Atoi(string(p))
-		}
+	if !isOwnLock() || !HoldsExclusiveLock() {
+		t.Errorf("lock not owned exclusively by the current process\n")
+	}
+	pid := -1
+	p, err := ioutil.ReadFile(stLockFile)
+	if err == nil {
+		pid, _ = strconv.Atoi(string(p))
+	}
+	if pid != os.Getpid() {
 		t.Errorf("wrong pid found in lock file: '%d' instead of '%d'\n", pid, os.Getpid())
 	}
 	ReleaseSaptuneLock()
-	if saptuneIsLocked() {
-		_, err := os.Stat(stLockFile)
-		if os.IsNotExist(err) {
-			t.Errorf("saptune lock does NOT exists, but is reported as existing\n")
-		} else {
-			t.Errorf("saptune lock exists, but shouldn't\n")
-			os.Remove(stLockFile)
+	if flockIsHeld() || isOwnLock() || HoldsExclusiveLock() {
+		t.Errorf("saptune lock exists, but shouldn't\n")
+	}
+	// the lock file remains for waiting processes, but without pid
+	if p, err := ioutil.ReadFile(stLockFile); err != nil || len(p) != 0 {
+		t.Errorf("wrong lock file content '%s' - '%v'\n", string(p), err)
+	}
+
+	// empty lock file or pid of a dead or of a non saptune process
+	for _, content := range []string{"", "4711", "1", "no pid"} {
+		_ = ioutil.WriteFile(stLockFile, []byte(content), 0600)
+		if flockIsHeld() || legacyLockPid() != 0 {
+			t.Errorf("lock file with content '%s' reported as locked\n", content)
 		}
 	}
+	os.Remove(stLockFile)
+	ReleaseSaptuneLock()
+}
+
+func TestTrySaptuneLock(t *testing.T) {
+	os.Remove(stLockFile)
+	if !TrySaptuneLock() {
+		t.Errorf("setting the lock failed, but should succeed\n")
+	}
+	if !isOwnLock() {
+		t.Errorf("lock file not owned by the current process\n")
+	}
+	// lock already owned by ourself
+	if !TrySaptuneLock() {
+		t.Errorf("lock is owned by the current process, but reported as foreign\n")
+	}
+	ReleaseSaptuneLock()
 
-	sl, _ := os.OpenFile(stLockFile, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0600)
-	fmt.Fprintf(sl, "")
-	saptuneIsLocked()
+	// lock of a foreign, still running process
+	foreign := foreignLock(t, LockShared)
+	if TrySaptuneLock() {
+		t.Errorf("lock of a foreign process was overwritten\n")
+	}
+	foreign.Close()
+	if !TrySaptuneLock() {
+		t.Errorf("released foreign lock still blocks\n")
+	}
+	ReleaseSaptuneLock()
 	os.Remove(stLockFile)
-	sl, _ = os.OpenFile(stLockFile, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0600)
-	fmt.Fprintf(sl, "%d", 4711)
-	saptuneIsLocked()
+}
+
+func TestSharedLock(t *testing.T) {
 	os.Remove(stLockFile)
+	foreign := foreignLock(t, LockShared)
+	// read-only commands run concurrently
+	if locked, err := setLock(LockShared); !locked || err != nil {
+		t.Errorf("shared lock blocked by another shared lock - '%v'\n", err)
+	}
+	if HoldsExclusiveLock() {
+		t.Errorf("shared lock reported as exclusive\n")
+	}
 	ReleaseSaptuneLock()
+	if locked, err := setLock(LockExclusive); locked || err != nil {
+		t.Errorf("exclusive lock not blocked by a shared lock - '%v'\n", err)
+		ReleaseSaptuneLock()
+	}
+	foreign.Close()
+
+	foreign = foreignLock(t, LockExclusive)
+	if locked, _ := setLock(LockShared); locked {
+		t.Errorf("shared lock not blocked by an exclusive lock\n")
+		ReleaseSaptuneLock()
+	}
+	foreign.Close()
+	os.Remove(stLockFile)
+}
+
+func TestWaitForLock(t *testing.T) {
+	oldInterval := lockPollInterval
+	defer func() { lockPollInterval = oldInterval }()
+	lockPollInterval = 10 * time.Millisecond
+	os.Remove(stLockFile)
+
+	foreign := foreignLock(t, LockExclusive)
+	if locked, _ := waitForLock(LockShared, 50*time.Millisecond); locked {
+		t.Errorf("lock set, but foreign lock is still held\n")
+		ReleaseSaptuneLock()
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		foreign.Close()
+	}()
+	if locked, err := waitForLock(LockShared, 5*time.Second); !locked || err != nil {
+		t.Errorf("lock not set after the foreign lock was released - '%v'\n", err)
+	}
+	ReleaseSaptuneLock()
+	os.Remove(stLockFile)
+}
+
+func TestLegacyLock(t *testing.T) {
+	// lock file of an older saptune version, which only contains the pid
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("command 'sleep' not available")
+	}
+	saptune := path.Join(t.TempDir(), "saptune")
+	content, _ := ioutil.ReadFile(sleep)
+	_ = ioutil.WriteFile(saptune, content, 0755)
+	cmd := exec.Command(saptune, "10")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("can not start fake saptune - %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	_ = ioutil.WriteFile(stLockFile, []byte(fmt.Sprintf("%d", cmd.Process.Pid)), 0600)
+	if legacyLockPid() != cmd.Process.Pid {
+		t.Errorf("lock of running saptune '%d' not detected\n", cmd.Process.Pid)
+	}
+	if TrySaptuneLock() {
+		t.Errorf("lock of a running saptune was overwritten\n")
+		ReleaseSaptuneLock()
+	}
+	// 'saptune lock remove' removes the lock file of an older saptune
+	if err := RemoveSaptuneLock(); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(stLockFile); !os.IsNotExist(err) {
+		t.Errorf("lock file not removed\n")
+	}
+}
+
+func TestRemoveSaptuneLock(t *testing.T) {
+	os.Remove(stLockFile)
+	if err := RemoveSaptuneLock(); err != nil {
+		t.Errorf("missing lock file reported as error - '%v'\n", err)
+	}
+	foreign := foreignLock(t, LockShared)
+	if err := RemoveSaptuneLock(); err == nil {
+		t.Errorf("lock file of a running saptune was removed\n")
+	}
+	foreign.Close()
+	if err := RemoveSaptuneLock(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseLockWait(t *testing.T) {
+	for val, exp := range map[string]time.Duration{"": 0, "0": 0, "30": 30 * time.Second, "90s": 90 * time.Second, "5m": 5 * time.Minute} {
+		if wait, ok := parseLockWait(val); !ok || wait != exp {
+			t.Errorf("'%s': got '%v' - '%v', expected '%v'\n", val, wait, ok, exp)
+		}
+	}
+	for _, val := range []string{"-5", "-1s", "soon"} {
+		if _, ok := parseLockWait(val); ok {
+			t.Errorf("'%s' accepted, but is not a valid timeout\n", val)
+		}
+	}
 }
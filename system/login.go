@@ -2,37 +2,23 @@ package system
 
 import (
 	"fmt"
-	"os/exec"
-	"strings"
 )
 
 // GetCurrentLogins returns the user ids of the currently logged in users
 func GetCurrentLogins() []string {
 	uID := []string{}
-	cmdName := "/usr/bin/loginctl"
-	cmdArgs := []string{"--no-pager", "--no-legend", "--no-ask-password", "list-users"}
-	if !CmdIsAvailable(cmdName) {
-		WarningLog("command '%s' not found", cmdName)
-		return uID
-	}
 	running, err := IsSystemRunning()
 	if err != nil {
 		ErrorLog("%v - Failed to call command systemctl", err)
 		return uID
 	}
 	if running {
-		cmdOut, err := exec.Command(cmdName, cmdArgs...).CombinedOutput()
+		users, err := GetServiceManager().LoggedInUsers()
 		if err != nil {
-			WarningLog("failed to invoke external command '%s %v': %v, output: %s", cmdName, cmdArgs, err, string(cmdOut))
+			WarningLog("failed to get the logged in users: %v", err)
 			return uID
 		}
-		for _, logins := range strings.Split(string(cmdOut), "\n") {
-			if logins == "" {
-				continue
-			}
-			user := strings.Split(strings.TrimSpace(logins), " ")
-			uID = append(uID, user[0])
-		}
+		uID = users
 	}
 	return uID
 }
@@ -42,40 +28,21 @@ func GetCurrentLogins() []string {
 func GetTasksMax(userID string) string {
 	//systemctl show -p TasksMax user-<uid>.slice
 	uSlice := "user-" + userID + ".slice"
-	cmdName := "/usr/bin/systemctl"
-	cmdArgs := []string{"show", "-p", "TasksMax", uSlice}
-	DebugLog("GetTasksMax - calling '%s' with '%s' args", cmdName, strings.Join(cmdArgs, " "))
-
-	if !CmdIsAvailable(cmdName) {
-		WarningLog("command '%s' not found", cmdName)
-		return ""
-	}
-	cmdOut, err := exec.Command(cmdName, cmdArgs...).CombinedOutput()
+	tasksMax, err := GetServiceManager().UnitProperty(uSlice, "TasksMax")
 	if err != nil {
-		WarningLog("failed to invoke external command '%s %v': %v, output: %s", cmdName, cmdArgs, err, string(cmdOut))
+		WarningLog("failed to get TasksMax of '%s': %v", uSlice, err)
 		return ""
 	}
-	tasksMax := strings.Split(strings.TrimSpace(string(cmdOut)), "=")
-	DebugLog("GetTasksMax - cmd returns '%+v'", tasksMax)
-	// The result of strings.Split of an 'empty' string is a slice with
-	// one element - the empty string.
-	if len(tasksMax) == 1 && tasksMax[0] == "" {
-		return tasksMax[0]
-	}
-	return tasksMax[1]
+	DebugLog("GetTasksMax - '%s' returns '%s'", uSlice, tasksMax)
+	return tasksMax
 }
 
 // SetTasksMax sets the limit of TasksMax for a given user id to 'limit'
 func SetTasksMax(userID, limit string) error {
 	//systemctl  --runtime set-property user-<uid>.slice TasksMax=infinity
 	uSlice := "user-" + userID + ".slice"
-	tmLimit := "TasksMax=" + limit
-	cmdName := "/usr/bin/systemctl"
-	cmdArgs := []string{"--runtime", "set-property", uSlice, tmLimit}
-
-	if !CmdIsAvailable(cmdName) {
-		return fmt.Errorf("command '%s' not found", cmdName)
+	if err := GetServiceManager().SetUnitProperty(uSlice, "TasksMax", limit); err != nil {
+		return fmt.Errorf("failed to set TasksMax of '%s' - %v", uSlice, err)
 	}
-	_, err := exec.Command(cmdName, cmdArgs...).CombinedOutput()
-	return err
+	return nil
 }
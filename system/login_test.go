@@ -6,6 +6,8 @@ import (
 )
 
 func TestGetCurrentLogins(t *testing.T) {
+	defer SetServiceManager(GetServiceManager())
+	SetServiceManager(ExecServiceManager{})
 	cmd := "/usr/bin/loginctl"
 	ocmd := "/usr/bin/loginctl_OrG"
 	val := ""
@@ -31,6 +33,8 @@ func TestGetCurrentLogins(t *testing.T) {
 }
 
 func TestGetTasksMax(t *testing.T) {
+	defer SetServiceManager(GetServiceManager())
+	SetServiceManager(ExecServiceManager{})
 	cmd := "/usr/bin/systemctl"
 	ocmd := "/usr/bin/systemctl_OrG"
 	userID := "65534"
@@ -77,6 +81,8 @@ func TestSetTasksMax(t *testing.T) {
 
 // test with missing loginctl command
 func TestMissingLoginctlCmd(t *testing.T) {
+	defer SetServiceManager(GetServiceManager())
+	SetServiceManager(ExecServiceManager{})
 	val := ""
 	cmdName := "/usr/bin/loginctl"
 	savName := "/usr/bin/loginctl_SAVE"
@@ -96,6 +102,8 @@ func TestMissingLoginctlCmd(t *testing.T) {
 
 // test with missing systemctl command
 func TestMissingSystemctlCmd(t *testing.T) {
+	defer SetServiceManager(GetServiceManager())
+	SetServiceManager(ExecServiceManager{})
 	userID := "65534"
 	val := "18446744073709"
 	cmdName := "/usr/bin/systemctl"
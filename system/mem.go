@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -59,6 +60,16 @@ func GetTotalMemSizePages() uint64 {
 	return (ParseMeminfo()[MemMainTotalKey] + ParseMeminfo()[MemSwapTotalKey]) / uint64(os.Getpagesize())
 }
 
+// GetNumaNodes return the number of NUMA nodes of the system. A system
+// without NUMA information has one node.
+func GetNumaNodes() int {
+	nodes, _ := filepath.Glob("/sys/devices/system/node/node[0-9]*")
+	if len(nodes) == 0 {
+		return 1
+	}
+	return len(nodes)
+}
+
 // GetSemaphoreLimits return kernel semaphore limits. Panic on error.
 func GetSemaphoreLimits() (msl, mns, opm, mni uint64) {
 	field, err := GetSysctlString("kernel.sem")
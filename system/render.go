@@ -0,0 +1,525 @@
+package system
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reportFormats are the supported output formats besides 'json' together
+// with the functions rendering the collected json entry
+var reportFormats = map[string]func(io.Writer, JEntry) error{"csv": renderCSV, "yaml": renderYAML, "markdown": renderMarkdown, "html": renderHTML}
+
+// reportRAC are the 'realm command' combinations supporting the report
+// formats
+var reportRAC = map[string]bool{"note list": true, "note verify": true, "solution verify": true, "verify": true, "status": true, "service status": true, "daemon status": true}
+
+// regFNIndex matches the index at the beginning of a footnote text
+var regFNIndex = regexp.MustCompile(`^\s*\[\d+\]\s*`)
+
+// IsReportFormat returns true, if one of the report formats (csv, yaml,
+// markdown, html) is selected by '--format'
+func IsReportFormat() bool {
+	_, ok := reportFormats[GetFlagVal("format")]
+	return ok
+}
+
+// StructuredOut returns true, if the output of saptune is written in a
+// machine readable format (json or one of the report formats) instead of
+// the screen output
+func StructuredOut() bool {
+	return GetFlagVal("format") == "json" || IsReportFormat()
+}
+
+// isValidFormat checks, if the value of '--format' is supported
+func isValidFormat(format string) bool {
+	_, ok := reportFormats[format]
+	return ok || format == "json"
+}
+
+// OutputFormats returns the supported values of '--format'
+func OutputFormats() []string {
+	formats := []string{}
+	for format := range reportFormats {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return append([]string{"json"}, formats...)
+}
+
+// reportOut writes the collected result in the selected report format
+// to stdout
+// used in function system/jOut
+func reportOut() error {
+	if _, ok := jentry.CmdResult.(emptyResult); ok {
+		// nothing collected, e.g. because of an error
+		return nil
+	}
+	return reportFormats[GetFlagVal("format")](stdOutOrg, jentry)
+}
+
+// reportTable is a result converted into rows and columns. 'refs' contains
+// the footnote ids referenced by each row
+type reportTable struct {
+	header []string
+	rows   [][]string
+	refs   [][]string
+}
+
+// reportFootnote is a footnote of a report section
+type reportFootnote struct {
+	id  string
+	txt string
+}
+
+// reportSection is a part of a markdown or html report, e.g. the result of
+// a single Note
+type reportSection struct {
+	title      string
+	table      reportTable
+	footnotes  []reportFootnote
+	attentions []string
+}
+
+// jsonField is a struct field together with the name from its json tag
+type jsonField struct {
+	name  string
+	value reflect.Value
+}
+
+// jsonFields returns the fields of a struct named like in the json output.
+// If 'all' is false, empty fields tagged with 'omitempty' are skipped
+func jsonFields(val reflect.Value, all bool) []jsonField {
+	fields := []jsonField{}
+	for i := 0; i < val.NumField(); i++ {
+		tag := strings.Split(val.Type().Field(i).Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = val.Type().Field(i).Name
+		}
+		if !all && len(tag) > 1 && tag[1] == "omitempty" && isEmptyValue(val.Field(i)) {
+			continue
+		}
+		fields = append(fields, jsonField{name: name, value: val.Field(i)})
+	}
+	return fields
+}
+
+// isEmptyValue reports, if a value is empty in the sense of 'omitempty'
+func isEmptyValue(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return val.Len() == 0
+	case reflect.Bool:
+		return !val.Bool()
+	case reflect.Int, reflect.Int64:
+		return val.Int() == 0
+	case reflect.Ptr, reflect.Interface:
+		return val.IsNil()
+	}
+	return false
+}
+
+// cellValue converts a value into the content of a single table cell
+func cellValue(val reflect.Value) string {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return ""
+		}
+		return cellValue(val.Elem())
+	case reflect.String:
+		return val.String()
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool())
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10)
+	case reflect.Slice:
+		items := []string{}
+		sep := " "
+		for i := 0; i < val.Len(); i++ {
+			if val.Index(i).Kind() == reflect.Struct {
+				sep = "; "
+			}
+			items = append(items, cellValue(val.Index(i)))
+		}
+		return strings.Join(items, sep)
+	case reflect.Struct:
+		if fn, ok := val.Interface().(JFootNotes); ok {
+			return strings.TrimSpace(fn.FNoteTxt)
+		}
+		items := []string{}
+		for _, fld := range jsonFields(val, false) {
+			items = append(items, fmt.Sprintf("%s=%s", fld.name, cellValue(fld.value)))
+		}
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%v", val.Interface())
+}
+
+// sliceTable converts a slice of structs into a table with one row per
+// entry and one column per struct field
+func sliceTable(entries reflect.Value) reportTable {
+	tab := reportTable{}
+	for _, fld := range jsonFields(reflect.New(entries.Type().Elem()).Elem(), true) {
+		tab.header = append(tab.header, fld.name)
+	}
+	for i := 0; i < entries.Len(); i++ {
+		row := []string{}
+		for _, fld := range jsonFields(entries.Index(i), true) {
+			row = append(row, cellValue(fld.value))
+		}
+		tab.rows = append(tab.rows, row)
+		tab.refs = append(tab.refs, nil)
+	}
+	return tab
+}
+
+// fieldTable converts a struct into a table with one row per field.
+// Nested structs are flattened, the names are joined by '.'
+func fieldTable(tab *reportTable, prefix string, val reflect.Value) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		tab.rows = append(tab.rows, []string{prefix, cellValue(val)})
+		tab.refs = append(tab.refs, nil)
+		return
+	}
+	for _, fld := range jsonFields(val, false) {
+		name := fld.name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		fieldTable(tab, name, fld.value)
+	}
+}
+
+// resultTable converts a result into a table. Lists (Notes, Solutions,
+// verifications) get one row per entry, all other results one row per field
+func resultTable(result interface{}) reportTable {
+	switch res := result.(type) {
+	case JPNotes:
+		if len(res.Verifications) == 0 && len(res.Simulations) != 0 {
+			return sliceTable(reflect.ValueOf(res.Simulations))
+		}
+		return sliceTable(reflect.ValueOf(res.Verifications))
+	case JNoteList:
+		return sliceTable(reflect.ValueOf(res.NotesList))
+	case JSolList:
+		return sliceTable(reflect.ValueOf(res.SolsList))
+	}
+	tab := reportTable{header: []string{"field", "value"}}
+	fieldTable(&tab, "", reflect.ValueOf(result))
+	return tab
+}
+
+// noteSections converts the result of 'note verify' or 'solution verify'
+// into one report section per Note. The footnotes of the parameters are
+// collected per Note and referenced from the parameter rows
+func noteSections(res JPNotes) []reportSection {
+	lines := res.Verifications
+	if len(lines) == 0 {
+		lines = res.Simulations
+	}
+	sections := []reportSection{}
+	index := make(map[string]int)
+	for _, line := range lines {
+		idx, ok := index[line.NoteID]
+		if !ok {
+			title := fmt.Sprintf("Note %s", line.NoteID)
+			if line.NoteVers != "" {
+				title = fmt.Sprintf("%s, Version %s", title, line.NoteVers)
+			}
+			sections = append(sections, reportSection{title: title, table: reportTable{header: []string{"Parameter", "Expected", "Override", "Actual", "Compliant"}}})
+			idx = len(sections) - 1
+			index[line.NoteID] = idx
+		}
+		sect := &sections[idx]
+		compliant := "-"
+		if line.Compliant != nil {
+			compliant = map[bool]string{true: "yes", false: "no"}[*line.Compliant]
+		}
+		actual := ""
+		if line.ActValue != nil {
+			actual = *line.ActValue
+		}
+		refs := []string{}
+		for _, fn := range line.Footnotes {
+			if fn.FNoteNumber == 0 {
+				continue
+			}
+			id := fmt.Sprintf("%s-%d", line.NoteID, fn.FNoteNumber)
+			refs = append(refs, id)
+			known := false
+			for _, sfn := range sect.footnotes {
+				if sfn.id == id {
+					known = true
+				}
+			}
+			if !known {
+				sect.footnotes = append(sect.footnotes, reportFootnote{id: id, txt: regFNIndex.ReplaceAllString(strings.TrimSpace(fn.FNoteTxt), "")})
+			}
+		}
+		sect.table.rows = append(sect.table.rows, []string{line.Parameter, line.ExpValue, line.OverValue, actual, compliant})
+		sect.table.refs = append(sect.table.refs, refs)
+	}
+	for _, remind := range res.Attentions {
+		if idx, ok := index[remind.NoteID]; ok {
+			sections[idx].attentions = append(sections[idx].attentions, remind.NoteReminder)
+		}
+	}
+	return sections
+}
+
+// summarySections converts the compliance summary of 'verify --summary'
+// into report sections
+func summarySections(summary *JVerifySummary) []reportSection {
+	sections := []reportSection{}
+	for _, part := range []struct {
+		title   string
+		name    string
+		entries []JSummaryEntry
+	}{{"Compliance summary per Note", "Note", summary.Notes}, {"Compliance summary per section", "Section", summary.Sections}} {
+		tab := reportTable{header: []string{part.name, "compliant", "non-compliant", "not applicable", "untouched", "informational", "score"}}
+		for _, entry := range part.entries {
+			cnt := entry.Counts
+			tab.rows = append(tab.rows, []string{entry.Name, strconv.Itoa(cnt.Compliant), strconv.Itoa(cnt.NonCompliant), strconv.Itoa(cnt.NotApplicable), strconv.Itoa(cnt.Untouched), strconv.Itoa(cnt.Informational), fmt.Sprintf("%d%%", entry.Score)})
+			tab.refs = append(tab.refs, nil)
+		}
+		sections = append(sections, reportSection{title: part.title, table: tab})
+	}
+	tab := reportTable{header: []string{"Parameter", "Notes", "Expected", "Actual"}}
+	for _, offender := range summary.TopOffenders {
+		tab.rows = append(tab.rows, []string{offender.Parameter, strings.Join(offender.Notes, ", "), offender.ExpValue, offender.ActValue})
+		tab.refs = append(tab.refs, nil)
+	}
+	return append(sections, reportSection{title: "Top offending parameters", table: tab})
+}
+
+// reportSections returns the sections of a markdown or html report
+func reportSections(entry JEntry) []reportSection {
+	if res, ok := entry.CmdResult.(JPNotes); ok {
+		if res.Summary != nil {
+			return append(summarySections(res.Summary), noteSections(res)...)
+		}
+		return noteSections(res)
+	}
+	return []reportSection{{title: entry.Cmd, table: resultTable(entry.CmdResult)}}
+}
+
+// reportSummary returns the summary lines of a markdown or html report
+func reportSummary(entry JEntry) [][2]string {
+	summary := [][2]string{{"command", entry.CmdLine}, {"publish time", entry.Created}, {"exit code", strconv.Itoa(entry.CmdRet)}}
+	if res, ok := entry.CmdResult.(JPNotes); ok && res.SysCompliance != nil {
+		summary = append(summary, [2]string{"system compliance", map[bool]string{true: "yes", false: "no"}[*res.SysCompliance]})
+	}
+	if res, ok := entry.CmdResult.(JPNotes); ok && res.Summary != nil {
+		summary = append(summary, [2]string{"compliance score", fmt.Sprintf("%d%%", res.Summary.Score)})
+	}
+	return summary
+}
+
+// renderCSV writes the result as comma separated values
+func renderCSV(writer io.Writer, entry JEntry) error {
+	tab := resultTable(entry.CmdResult)
+	cw := csv.NewWriter(writer)
+	if err := cw.Write(tab.header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(tab.rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// renderYAML writes the whole entry (like the json output) as yaml
+func renderYAML(writer io.Writer, entry JEntry) error {
+	_, lines := yamlNode(reflect.ValueOf(entry))
+	_, err := fmt.Fprintln(writer, strings.Join(lines, "\n"))
+	return err
+}
+
+// yamlNode converts a value into yaml. Scalars are returned as string,
+// mappings and sequences as lines without indentation
+func yamlNode(val reflect.Value) (string, []string) {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return "null", nil
+		}
+		return yamlNode(val.Elem())
+	case reflect.String:
+		return yamlQuote(val.String()), nil
+	case reflect.Struct:
+		fields := jsonFields(val, false)
+		if len(fields) == 0 {
+			return "{}", nil
+		}
+		lines := []string{}
+		for _, fld := range fields {
+			lines = append(lines, yamlEntry(yamlQuote(fld.name)+":", fld.value)...)
+		}
+		return "", lines
+	case reflect.Map:
+		if val.Len() == 0 {
+			return "{}", nil
+		}
+		keys := []string{}
+		values := make(map[string]reflect.Value)
+		for _, key := range val.MapKeys() {
+			keys = append(keys, cellValue(key))
+			values[cellValue(key)] = val.MapIndex(key)
+		}
+		sort.Strings(keys)
+		lines := []string{}
+		for _, key := range keys {
+			lines = append(lines, yamlEntry(yamlQuote(key)+":", values[key])...)
+		}
+		return "", lines
+	case reflect.Slice:
+		if val.Len() == 0 {
+			return "[]", nil
+		}
+		lines := []string{}
+		for i := 0; i < val.Len(); i++ {
+			lines = append(lines, yamlEntry("-", val.Index(i))...)
+		}
+		return "", lines
+	}
+	return cellValue(val), nil
+}
+
+// yamlEntry returns the lines of a mapping entry ('key:') or a sequence
+// entry ('-')
+func yamlEntry(indicator string, val reflect.Value) []string {
+	scalar, nested := yamlNode(val)
+	if nested == nil {
+		return []string{indicator + " " + scalar}
+	}
+	lines := []string{}
+	if indicator == "-" {
+		// first line of a nested node follows the sequence indicator
+		lines = append(lines, "- "+nested[0])
+		for _, line := range nested[1:] {
+			lines = append(lines, "  "+line)
+		}
+		return lines
+	}
+	lines = append(lines, indicator)
+	for _, line := range nested {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
+
+// yamlQuote quotes a string, if it can not be used as plain yaml scalar
+func yamlQuote(str string) string {
+	if str == "" || strings.ContainsAny(str, ":#\n\t\"'\\") || strings.ContainsAny(str[:1], "-?,[]{}&*!|>%@` ") || strings.HasSuffix(str, " ") {
+		return strconv.Quote(str)
+	}
+	switch strings.ToLower(str) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return strconv.Quote(str)
+	}
+	if _, err := strconv.ParseFloat(str, 64); err == nil {
+		return strconv.Quote(str)
+	}
+	return str
+}
+
+// mdEscape escapes the content of a markdown table cell
+func mdEscape(str string) string {
+	return strings.Replace(strings.Replace(str, "|", "\\|", -1), "\n", "<br>", -1)
+}
+
+// renderMarkdown writes the result as markdown compliance report
+func renderMarkdown(writer io.Writer, entry JEntry) error {
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, "# saptune %s\n\n", entry.Cmd)
+	for _, line := range reportSummary(entry) {
+		fmt.Fprintf(&buf, "- **%s**: %s\n", line[0], mdEscape(line[1]))
+	}
+	for _, sect := range reportSections(entry) {
+		fmt.Fprintf(&buf, "\n## %s\n\n", sect.title)
+		fmt.Fprintf(&buf, "| %s |\n", strings.Join(sect.table.header, " | "))
+		fmt.Fprintf(&buf, "|%s\n", strings.Repeat(" --- |", len(sect.table.header)))
+		for i, row := range sect.table.rows {
+			cells := []string{}
+			for _, cell := range row {
+				cells = append(cells, mdEscape(cell))
+			}
+			for _, ref := range sect.table.refs[i] {
+				cells[len(cells)-1] = cells[len(cells)-1] + "[^" + ref + "]"
+			}
+			fmt.Fprintf(&buf, "| %s |\n", strings.Join(cells, " | "))
+		}
+		if len(sect.footnotes) > 0 {
+			fmt.Fprintln(&buf)
+		}
+		for _, fn := range sect.footnotes {
+			fmt.Fprintf(&buf, "[^%s]: %s\n", fn.id, strings.Replace(fn.txt, "\n", " ", -1))
+		}
+		for _, attention := range sect.attentions {
+			fmt.Fprintf(&buf, "\n> **Attention:**\n> %s\n", strings.Replace(strings.TrimSpace(attention), "\n", "\n> ", -1))
+		}
+	}
+	_, err := writer.Write(buf.Bytes())
+	return err
+}
+
+// renderHTML writes the result as self-contained html compliance report
+func renderHTML(writer io.Writer, entry JEntry) error {
+	buf := bytes.Buffer{}
+	title := html.EscapeString("saptune " + entry.Cmd)
+	fmt.Fprintf(&buf, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", title)
+	fmt.Fprintln(&buf, "<style>body{font-family:sans-serif}table{border-collapse:collapse}th,td{border:1px solid #999;padding:2px 6px;text-align:left}.attention{color:#c00}</style>")
+	fmt.Fprintf(&buf, "</head>\n<body>\n<h1>%s</h1>\n<ul>\n", title)
+	for _, line := range reportSummary(entry) {
+		fmt.Fprintf(&buf, "<li><b>%s</b>: %s</li>\n", html.EscapeString(line[0]), html.EscapeString(line[1]))
+	}
+	fmt.Fprintln(&buf, "</ul>")
+	for _, sect := range reportSections(entry) {
+		fmt.Fprintf(&buf, "<h2>%s</h2>\n<table>\n<tr>", html.EscapeString(sect.title))
+		for _, head := range sect.table.header {
+			fmt.Fprintf(&buf, "<th>%s</th>", html.EscapeString(head))
+		}
+		fmt.Fprintln(&buf, "</tr>")
+		for i, row := range sect.table.rows {
+			fmt.Fprint(&buf, "<tr>")
+			for j, cell := range row {
+				fmt.Fprintf(&buf, "<td>%s", strings.Replace(html.EscapeString(cell), "\n", "<br>", -1))
+				if j == len(row)-1 {
+					for _, ref := range sect.table.refs[i] {
+						fmt.Fprintf(&buf, "<sup><a href=\"#fn-%s\">[%s]</a></sup>", ref, ref[strings.LastIndex(ref, "-")+1:])
+					}
+				}
+				fmt.Fprint(&buf, "</td>")
+			}
+			fmt.Fprintln(&buf, "</tr>")
+		}
+		fmt.Fprintln(&buf, "</table>")
+		for _, fn := range sect.footnotes {
+			fmt.Fprintf(&buf, "<p id=\"fn-%s\"><sup>[%s]</sup> %s</p>\n", fn.id, fn.id[strings.LastIndex(fn.id, "-")+1:], strings.Replace(html.EscapeString(fn.txt), "\n", "<br>", -1))
+		}
+		for _, attention := range sect.attentions {
+			fmt.Fprintf(&buf, "<p class=\"attention\"><b>Attention:</b><br>%s</p>\n", strings.Replace(html.EscapeString(strings.TrimSpace(attention)), "\n", "<br>", -1))
+		}
+	}
+	fmt.Fprintln(&buf, "</body>\n</html>")
+	_, err := writer.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,202 @@
+package system
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+var tstCompliant = true
+var tstNotCompliant = false
+var tstActual = "10"
+
+var tstReportEntry = JEntry{
+	Created: "2026-01-01 10:00:00.000",
+	CmdLine: "saptune --format=markdown note verify",
+	Cmd:     "note verify",
+	CmdResult: JPNotes{
+		Verifications: []JPNotesLine{
+			{NoteID: "941735", NoteVers: "11", Parameter: "vm.swappiness", Compliant: &tstNotCompliant, ExpValue: "20", ActValue: &tstActual, Comment: "[17]", Footnotes: []JFootNotes{{FNoteNumber: 17, FNoteTxt: "[17] override has expired"}}, Severity: "critical"},
+			{NoteID: "941735", NoteVers: "11", Parameter: "kernel.shmmni", Compliant: &tstCompliant, ExpValue: "10", ActValue: &tstActual},
+			{NoteID: "1680803", NoteVers: "7", Parameter: "IO_SCHEDULER_sda", Compliant: nil, ExpValue: "none", Comment: "[1]", Footnotes: []JFootNotes{{FNoteNumber: 1, FNoteTxt: " [1] setting is not supported by the system"}}},
+		},
+		Attentions:    []JPNotesRemind{{NoteID: "1680803", NoteReminder: "check\nthe | scheduler"}},
+		SysCompliance: &tstNotCompliant,
+	},
+	CmdMsg: []JMsg{},
+}
+
+func TestIsReportFormat(t *testing.T) {
+	for format, expected := range map[string]bool{"json": false, "csv": true, "yaml": true, "markdown": true, "html": true} {
+		os.Args = []string{"saptune", "--format=" + format, "note", "list"}
+		RereadArgs()
+		if IsReportFormat() != expected {
+			t.Errorf("format '%s': expected '%v'", format, expected)
+		}
+		if !StructuredOut() {
+			t.Errorf("format '%s': expected structured output", format)
+		}
+		if !ChkCliSyntax() {
+			t.Errorf("format '%s': expected good syntax", format)
+		}
+	}
+	os.Args = []string{"saptune", "--format=xml", "note", "list"}
+	RereadArgs()
+	if StructuredOut() || ChkCliSyntax() {
+		t.Error("expected unsupported format 'xml'")
+	}
+	os.Args = []string{"saptune"}
+	RereadArgs()
+}
+
+func TestReportFormatNotSupported(t *testing.T) {
+	oldOSExit := OSExit
+	defer func() { OSExit = oldOSExit }()
+	OSExit = tstosExit
+	oldErrorExitOut := ErrorExitOut
+	defer func() { ErrorExitOut = oldErrorExitOut }()
+	ErrorExitOut = tstErrorExitOut
+	buffer := bytes.Buffer{}
+	tstwriter = &buffer
+	defer func() {
+		os.Args = []string{"saptune"}
+		RereadArgs()
+	}()
+
+	// commands without json support must not run with a report format
+	for _, args := range [][]string{{"note", "edit", "1680803"}, {"note", "customise", "1680803"}, {"service", "stop"}} {
+		os.Args = append([]string{"saptune", "--format=csv"}, args...)
+		RereadArgs()
+		jInit()
+		tstRetErrorExit = -1
+		JnotSupportedYet()
+		if tstRetErrorExit != 1 {
+			t.Errorf("'%s': error exit should be '1' and NOT '%v'", strings.Join(args, " "), tstRetErrorExit)
+		}
+	}
+	os.Args = []string{"saptune", "--format=csv", "note", "verify"}
+	RereadArgs()
+	jInit()
+	tstRetErrorExit = -1
+	JnotSupportedYet()
+	if tstRetErrorExit != -1 {
+		t.Errorf("'note verify' supports csv, but got error exit '%v'", tstRetErrorExit)
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := renderCSV(&buf, tstReportEntry); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got '%s'", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "Note ID,Note version,parameter,compliant,expected value") {
+		t.Errorf("wrong header '%s'", lines[0])
+	}
+	if lines[1] != "941735,11,vm.swappiness,false,20,,10,[17],[17] override has expired,,,false,,,critical" {
+		t.Errorf("wrong line '%s'", lines[1])
+	}
+
+	buf.Reset()
+	status := JEntry{Cmd: "status", CmdResult: JStatus{Services: JStatusServs{SaptuneService: []string{"enabled", "active"}}, TuningState: "compliant", EnabledNotes: []string{"941735", "1680803"}}}
+	if err := renderCSV(&buf, status); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"field,value", "services.saptune,enabled active", "tuning state,compliant", "Notes enabled,941735 1680803"} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("missing line '%s' in '%s'", line, buf.String())
+		}
+	}
+}
+
+func TestRenderYAML(t *testing.T) {
+	buf := bytes.Buffer{}
+	list := JEntry{Cmd: "note list", CmdResult: JNoteList{NotesList: []JNoteListEntry{{NoteID: "941735", NoteDesc: "SAP memory: #1", NoteVers: "11", Conflicts: []string{}}}, NotesOrder: []string{"941735"}}, CmdMsg: []JMsg{}}
+	if err := renderYAML(&buf, list); err != nil {
+		t.Fatal(err)
+	}
+	expected := `$schema: ""
+publish time: ""
+argv: ""
+pid: 0
+command: note list
+exit code: 0
+result:
+  Notes available:
+    - Note ID: "941735"
+      Note description: "SAP memory: #1"
+      Note reference: null
+      Note version: "11"
+      Note release date: ""
+      Note enabled manually: false
+      Note enabled by Solution: false
+      Note reverted manually: false
+      Note override exists: false
+      custom Note: false
+      Note conflicts with: []
+  Notes enabled:
+    - "941735"
+  remember message: ""
+messages: []
+`
+	if buf.String() != expected {
+		t.Errorf("got '%s', expected '%s'", buf.String(), expected)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := renderMarkdown(&buf, tstReportEntry); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"# saptune note verify", "- **system compliance**: no", "## Note 941735, Version 11", "| vm.swappiness | 20 |  | 10 | no[^941735-17] |", "[^941735-17]: override has expired", "## Note 1680803, Version 7", "| IO_SCHEDULER_sda | none |  |  | -[^1680803-1] |", "[^1680803-1]: setting is not supported by the system", "> check\n> the | scheduler"} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("missing '%s' in '%s'", line, buf.String())
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := renderHTML(&buf, tstReportEntry); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"<h2>Note 941735, Version 11</h2>", "<td>no<sup><a href=\"#fn-941735-17\">[17]</a></sup></td>", "<p id=\"fn-941735-17\"><sup>[17]</sup> override has expired</p>", "check<br>the | scheduler", "</html>"} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("missing '%s' in '%s'", line, buf.String())
+		}
+	}
+}
+
+func TestRenderSummary(t *testing.T) {
+	entry := tstReportEntry
+	res := entry.CmdResult.(JPNotes)
+	res.Summary = &JVerifySummary{
+		Score:        50,
+		Counts:       JComplCounts{Compliant: 1, NonCompliant: 1, NotApplicable: 1},
+		Notes:        []JSummaryEntry{{Name: "1680803", Score: 100, Counts: JComplCounts{NotApplicable: 1}}, {Name: "941735", Score: 50, Counts: JComplCounts{Compliant: 1, NonCompliant: 1}}},
+		Sections:     []JSummaryEntry{{Name: "block", Score: 100, Counts: JComplCounts{NotApplicable: 1}}, {Name: "sysctl", Score: 50, Counts: JComplCounts{Compliant: 1, NonCompliant: 1}}},
+		TopOffenders: []JOffender{{Parameter: "vm.swappiness", Notes: []string{"941735"}, ExpValue: "20", ActValue: "10"}},
+	}
+	entry.CmdResult = res
+	buf := bytes.Buffer{}
+	if err := renderMarkdown(&buf, entry); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"- **compliance score**: 50%", "## Compliance summary per Note", "| 941735 | 1 | 1 | 0 | 0 | 0 | 50% |", "## Compliance summary per section", "| block | 0 | 0 | 1 | 0 | 0 | 100% |", "## Top offending parameters", "| vm.swappiness | 941735 | 20 | 10 |", "## Note 941735, Version 11"} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("missing '%s' in '%s'", line, buf.String())
+		}
+	}
+	buf.Reset()
+	if err := renderYAML(&buf, entry); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "summary:\n") || !strings.Contains(buf.String(), "top offenders:\n") {
+		t.Errorf("missing summary in '%s'", buf.String())
+	}
+}
@@ -2,25 +2,19 @@ package system
 
 import (
 	"fmt"
-	"os/exec"
-	"strings"
+	"net"
+	"os"
 )
 
 // GetAvailServices returns a map of the available services of the system
 func GetAvailServices() map[string]string {
 	allServices := make(map[string]string)
-	cmdArgs := []string{"--no-pager", "list-unit-files"}
-	cmdOut, err := exec.Command(systemctlCmd, cmdArgs...).CombinedOutput()
+	units, err := GetServiceManager().UnitFiles()
 	if err != nil {
-		WarningLog("There was an error running external command %s %s: %v, output: %s", systemctlCmd, cmdArgs, err, cmdOut)
+		WarningLog("There was an error getting the available services: %v", err)
 		return allServices
 	}
-	for _, line := range strings.Split(string(cmdOut), "\n") {
-		fields := strings.Fields(line)
-		if len(fields) == 0 {
-			continue
-		}
-		serv := strings.TrimSpace(fields[0])
+	for _, serv := range units {
 		allServices[serv] = serv
 	}
 	return allServices
@@ -45,3 +39,28 @@ func GetServiceName(service string) string {
 	}
 	return serviceName
 }
+
+// IsNotifyService returns true, if saptune was started by a systemd service
+// of type 'notify'
+func IsNotifyService() bool {
+	return os.Getenv("NOTIFY_SOCKET") != ""
+}
+
+// SdNotify sends a state change like 'READY=1' to systemd, if saptune was
+// started by a service of type 'notify'. Without a notification socket
+// nothing is done
+func SdNotify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("failed to connect to the systemd notification socket '%s' - %v", socket, err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("failed to notify systemd about '%s' - %v", state, err)
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package system
 
 import (
+	"net"
 	"os"
+	"path"
 	"testing"
+	"time"
 )
 
 func TestGetServiceName(t *testing.T) {
@@ -40,3 +43,36 @@ func TestGetAvailServices(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestSdNotify(t *testing.T) {
+	socket := path.Join(t.TempDir(), "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", "")
+	if IsNotifyService() {
+		t.Error("not started by a notify service, but reported")
+	}
+	if err := SdNotify("READY=1"); err != nil {
+		t.Errorf("unexpected error without notification socket - '%v'", err)
+	}
+	t.Setenv("NOTIFY_SOCKET", socket)
+	if !IsNotifyService() {
+		t.Error("started by a notify service, but not reported")
+	}
+	if err := SdNotify("READY=1"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if n, err := conn.Read(buf); err != nil || string(buf[:n]) != "READY=1" {
+		t.Errorf("got '%s' - '%v', expected 'READY=1'", string(buf[:n]), err)
+	}
+	t.Setenv("NOTIFY_SOCKET", path.Join(path.Dir(socket), "missing"))
+	if err := SdNotify("READY=1"); err == nil {
+		t.Error("expected an error for a missing notification socket")
+	}
+}
@@ -0,0 +1,680 @@
+package system
+
+import (
+	"fmt"
+	"math"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServiceManager is the access to the service manager (systemd), the login
+// manager (logind) and tuned.
+// The D-Bus implementation is used, if the system bus is available,
+// otherwise the commands systemctl, loginctl, systemd-detect-virt and
+// tuned-adm are called.
+type ServiceManager interface {
+	// UnitAction runs 'action' (start, stop, restart,
+	// reload-or-try-restart, enable or disable) for a unit
+	UnitAction(action, unit string) error
+	// ResetFailed resets the 'failed' state of all units
+	ResetFailed() error
+	// ActiveState returns the active state of a unit (e.g. 'active')
+	ActiveState(unit string) (string, error)
+	// UnitFileState returns the unit file state of a unit (e.g. 'enabled')
+	UnitFileState(unit string) (string, error)
+	// UnitFiles returns the names of all available unit files
+	UnitFiles() ([]string, error)
+	// SystemState returns the state of the system (e.g. 'running'). An
+	// error is returned too, if the state is not 'running'
+	SystemState() (string, error)
+	// Virtualization detects a virtualized environment like
+	// 'systemd-detect-virt'. 'opt' can be '' (any), '-v' (vm),
+	// '-c' (container) or '-r' (chroot)
+	Virtualization(opt string) (bool, string, error)
+	// LoggedInUsers returns the user ids of the currently logged in users
+	LoggedInUsers() ([]string, error)
+	// UnitProperty returns the value of a property of a unit
+	UnitProperty(unit, property string) (string, error)
+	// SetUnitProperty sets a numeric property of a unit for runtime
+	SetUnitProperty(unit, property, value string) error
+	// TunedProfile returns the active tuned profile
+	TunedProfile() (string, error)
+	// TunedSwitchProfile activates a tuned profile
+	TunedSwitchProfile(profile string) error
+	// TunedOff switches off the active tuned profile
+	TunedOff() error
+}
+
+// serviceManager is the ServiceManager in use, set by GetServiceManager
+var serviceManager ServiceManager
+
+// enabledUnitStates are the unit file states 'systemctl is-enabled'
+// reports as enabled
+var enabledUnitStates = map[string]bool{"enabled": true, "enabled-runtime": true, "static": true, "indirect": true, "generated": true, "transient": true, "alias": true}
+
+// containerTypes are the container types reported by systemd
+var containerTypes = map[string]bool{"openvz": true, "lxc": true, "lxc-libvirt": true, "systemd-nspawn": true, "docker": true, "podman": true, "rkt": true, "wsl": true, "proot": true, "pouch": true}
+
+// GetServiceManager returns the ServiceManager to use. On first call the
+// D-Bus system bus is tried, the command based implementation is the
+// fallback. It is used too, if the connection to the bus got lost
+func GetServiceManager() ServiceManager {
+	if sm, ok := serviceManager.(*DBusServiceManager); ok && sm.isLost() {
+		DebugLog("GetServiceManager - connection to the D-Bus system bus lost, using the commands")
+		serviceManager = ExecServiceManager{}
+	}
+	if serviceManager == nil {
+		sm, err := NewDBusServiceManager(dbusSystemBus)
+		if err != nil {
+			DebugLog("GetServiceManager - D-Bus system bus not available, using the commands - %v", err)
+			serviceManager = ExecServiceManager{}
+		} else {
+			serviceManager = sm
+		}
+	}
+	return serviceManager
+}
+
+// SetServiceManager sets the ServiceManager to use, e.g. for tests
+func SetServiceManager(sm ServiceManager) {
+	serviceManager = sm
+}
+
+// ExecServiceManager is the ServiceManager calling the commands systemctl,
+// loginctl, systemd-detect-virt and tuned-adm
+type ExecServiceManager struct{}
+
+// UnitAction calls 'systemctl <action> <unit>'
+func (ExecServiceManager) UnitAction(action, unit string) error {
+	out, err := exec.Command(systemctlCmd, action, unit).CombinedOutput()
+	DebugLog("UnitAction - %s %s '%s' : '%+v %s'", systemctlCmd, action, unit, err, string(out))
+	if err != nil {
+		return fmt.Errorf("%v - %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ResetFailed calls 'systemctl reset-failed'
+func (ExecServiceManager) ResetFailed() error {
+	out, err := exec.Command(systemctlCmd, "reset-failed").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v - %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ActiveState calls 'systemctl is-active <unit>'
+func (ExecServiceManager) ActiveState(unit string) (string, error) {
+	out, err := exec.Command(systemctlCmd, "is-active", unit).CombinedOutput()
+	DebugLog("ActiveState - %s is-active '%s' : '%+v %s'", systemctlCmd, unit, err, string(out))
+	if len(out) == 0 && err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// UnitFileState calls 'systemctl is-enabled <unit>'
+func (ExecServiceManager) UnitFileState(unit string) (string, error) {
+	out, err := exec.Command(systemctlCmd, "is-enabled", unit).CombinedOutput()
+	DebugLog("UnitFileState - %s is-enabled '%s' : '%+v %s'", systemctlCmd, unit, err, string(out))
+	if len(out) == 0 && err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// UnitFiles calls 'systemctl list-unit-files'
+func (ExecServiceManager) UnitFiles() ([]string, error) {
+	units := []string{}
+	cmdArgs := []string{"--no-pager", "list-unit-files"}
+	out, err := exec.Command(systemctlCmd, cmdArgs...).CombinedOutput()
+	if err != nil {
+		return units, fmt.Errorf("%v - %s", err, strings.TrimSpace(string(out)))
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		units = append(units, strings.TrimSpace(fields[0]))
+	}
+	return units, nil
+}
+
+// SystemState calls 'systemctl is-system-running'
+func (ExecServiceManager) SystemState() (string, error) {
+	out, err := exec.Command(systemctlCmd, "is-system-running").CombinedOutput()
+	DebugLog("SystemState - %s is-system-running : '%+v %s'", systemctlCmd, err, string(out))
+	return strings.TrimSpace(string(out)), err
+}
+
+// Virtualization calls 'systemd-detect-virt'
+// option can be '-r' (chroot), -c (container), -v (vm)
+// '-r' only returns 0 or 1 without any output
+func (ExecServiceManager) Virtualization(opt string) (bool, string, error) {
+	var out []byte
+	var err error
+
+	virt := false
+	if opt == "" {
+		out, err = exec.Command(systemddvCmd).CombinedOutput()
+	} else {
+		out, err = exec.Command(systemddvCmd, opt).CombinedOutput()
+	}
+	DebugLog("Virtualization - %s %s : '%+v %s'", systemddvCmd, opt, err, string(out))
+	if err == nil {
+		// virtualized environment detected
+		virt = true
+	}
+	if len(out) == 0 && err != nil && opt != "-r" {
+		return virt, "", fmt.Errorf("%v - %s", err, string(out))
+	}
+	return virt, strings.TrimSpace(string(out)), err
+}
+
+// LoggedInUsers calls 'loginctl list-users'
+func (ExecServiceManager) LoggedInUsers() ([]string, error) {
+	uID := []string{}
+	cmdArgs := []string{"--no-pager", "--no-legend", "--no-ask-password", "list-users"}
+	if !CmdIsAvailable(loginctlCmd) {
+		return uID, fmt.Errorf("command '%s' not found", loginctlCmd)
+	}
+	out, err := exec.Command(loginctlCmd, cmdArgs...).CombinedOutput()
+	if err != nil {
+		return uID, fmt.Errorf("failed to invoke external command '%s %v': %v, output: %s", loginctlCmd, cmdArgs, err, string(out))
+	}
+	for _, logins := range strings.Split(string(out), "\n") {
+		if logins == "" {
+			continue
+		}
+		user := strings.Split(strings.TrimSpace(logins), " ")
+		uID = append(uID, user[0])
+	}
+	return uID, nil
+}
+
+// UnitProperty calls 'systemctl show -p <property> <unit>'
+func (ExecServiceManager) UnitProperty(unit, property string) (string, error) {
+	cmdArgs := []string{"show", "-p", property, unit}
+	if !CmdIsAvailable(systemctlCmd) {
+		return "", fmt.Errorf("command '%s' not found", systemctlCmd)
+	}
+	out, err := exec.Command(systemctlCmd, cmdArgs...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to invoke external command '%s %v': %v, output: %s", systemctlCmd, cmdArgs, err, string(out))
+	}
+	// The result of strings.Split of an 'empty' string is a slice with
+	// one element - the empty string.
+	value := strings.SplitN(strings.TrimSpace(string(out)), "=", 2)
+	if len(value) == 1 {
+		return "", nil
+	}
+	return value[1], nil
+}
+
+// SetUnitProperty calls 'systemctl --runtime set-property <unit>
+// <property>=<value>'
+func (ExecServiceManager) SetUnitProperty(unit, property, value string) error {
+	if !CmdIsAvailable(systemctlCmd) {
+		return fmt.Errorf("command '%s' not found", systemctlCmd)
+	}
+	_, err := exec.Command(systemctlCmd, "--runtime", "set-property", unit, property+"="+value).CombinedOutput()
+	return err
+}
+
+// TunedProfile calls 'tuned-adm active'
+func (ExecServiceManager) TunedProfile() (string, error) {
+	out, err := exec.Command(tunedAdmCmd, "active").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%v %s", err, string(out))
+	}
+	matches := regTunedProfile.FindStringSubmatch(string(out))
+	if len(matches) == 0 {
+		return "", nil
+	}
+	return matches[1], nil
+}
+
+// TunedSwitchProfile calls 'tuned-adm profile <profile>'
+func (ExecServiceManager) TunedSwitchProfile(profile string) error {
+	if out, err := exec.Command(tunedAdmCmd, "profile", profile).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v %s", err, string(out))
+	}
+	return nil
+}
+
+// TunedOff calls 'tuned-adm off'
+func (ExecServiceManager) TunedOff() error {
+	if out, err := exec.Command(tunedAdmCmd, "off").CombinedOutput(); err != nil {
+		return fmt.Errorf("%v %s", err, string(out))
+	}
+	return nil
+}
+
+// systemd, logind and tuned D-Bus names
+const (
+	systemdBus     = "org.freedesktop.systemd1"
+	systemdPath    = "/org/freedesktop/systemd1"
+	systemdManager = "org.freedesktop.systemd1.Manager"
+	systemdUnit    = "org.freedesktop.systemd1.Unit"
+	systemdJob     = "org.freedesktop.systemd1.Job"
+	logindBus      = "org.freedesktop.login1"
+	logindPath     = "/org/freedesktop/login1"
+	logindManager  = "org.freedesktop.login1.Manager"
+	tunedBus       = "com.redhat.tuned"
+	tunedPath      = "/Tuned"
+	tunedControl   = "com.redhat.tuned.control"
+)
+
+// dbusJobWait is the maximal time to wait for a job of systemd
+var dbusJobWait = 300 * time.Second
+
+// errDBusLost is returned by the D-Bus calls, if the connection to the
+// message bus is lost and can not be re-established
+var errDBusLost = fmt.Errorf("connection to the D-Bus message bus lost")
+
+// DBusServiceManager is the ServiceManager using the D-Bus API of systemd,
+// logind and tuned. If logind or tuned are not available on the bus or the
+// connection to the bus is lost, the commands are used
+type DBusServiceManager struct {
+	address  string
+	mu       sync.Mutex
+	conn     *dbusConn
+	lost     bool
+	fallback ExecServiceManager
+}
+
+// NewDBusServiceManager connects to the D-Bus message bus at 'address'
+func NewDBusServiceManager(address string) (*DBusServiceManager, error) {
+	dc, err := dbusDial(address)
+	if err != nil {
+		return nil, err
+	}
+	return &DBusServiceManager{address: address, conn: dc}, nil
+}
+
+// isLost checks, if the connection to the bus is lost
+func (sm *DBusServiceManager) isLost() bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.lost
+}
+
+// connection returns the connection to the bus. If 'broken' is still the
+// current connection, it is replaced by a new one. If this fails, the
+// connection is lost for good
+func (sm *DBusServiceManager) connection(broken *dbusConn) (*dbusConn, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.lost {
+		return nil, errDBusLost
+	}
+	if broken != nil && broken == sm.conn {
+		broken.Close()
+		dc, err := dbusDial(sm.address)
+		if err != nil {
+			WarningLog("connection to the D-Bus message bus lost, using the commands - %v", err)
+			sm.lost = true
+			return nil, errDBusLost
+		}
+		sm.conn = dc
+	}
+	return sm.conn, nil
+}
+
+// call calls a D-Bus method. After a connection error the connection is
+// re-established once and the call is repeated. If this fails too,
+// errDBusLost is returned
+func (sm *DBusServiceManager) call(dest, path, iface, member, sig string, args ...interface{}) ([]interface{}, error) {
+	dc, err := sm.connection(nil)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := dc.Call(dest, path, iface, member, sig, args...)
+	if _, ok := err.(dbusConnErr); !ok {
+		return reply, err
+	}
+	DebugLog("D-Bus call '%s' failed, reconnecting - %v", member, err)
+	if dc, err = sm.connection(dc); err != nil {
+		return nil, err
+	}
+	reply, err = dc.Call(dest, path, iface, member, sig, args...)
+	if _, ok := err.(dbusConnErr); ok {
+		WarningLog("connection to the D-Bus message bus lost, using the commands - %v", err)
+		sm.mu.Lock()
+		sm.lost = true
+		sm.mu.Unlock()
+		return nil, errDBusLost
+	}
+	return reply, err
+}
+
+// getProperty returns the value of the property 'prop' of the interface
+// 'iface' of the object 'path' at 'dest'
+func (sm *DBusServiceManager) getProperty(dest, path, iface, prop string) (interface{}, error) {
+	reply, err := sm.call(dest, path, "org.freedesktop.DBus.Properties", "Get", "ss", iface, prop)
+	if err != nil {
+		return nil, err
+	}
+	return dbusPropertyValue(reply, prop)
+}
+
+// useFallback checks, if a D-Bus call failed because the service is not
+// available on the bus or the bus itself is not available anymore
+func useFallback(err error) bool {
+	return err == errDBusLost || isServiceUnknown(err)
+}
+
+// isServiceUnknown checks, if a D-Bus call failed because the service is
+// not available on the bus
+func isServiceUnknown(err error) bool {
+	derr, ok := err.(dbusErr)
+	return ok && (derr.Name == "org.freedesktop.DBus.Error.ServiceUnknown" || derr.Name == "org.freedesktop.DBus.Error.NameHasNoOwner")
+}
+
+// manager calls a method of the systemd manager
+func (sm *DBusServiceManager) manager(member, sig string, args ...interface{}) ([]interface{}, error) {
+	return sm.call(systemdBus, systemdPath, systemdManager, member, sig, args...)
+}
+
+// UnitAction starts a job for the unit and waits for its end or changes
+// the unit files. Like systemctl the manager configuration is reloaded
+// after enable and disable
+func (sm *DBusServiceManager) UnitAction(action, unit string) error {
+	var err error
+	var reply []interface{}
+	switch action {
+	case "start", "stop", "restart", "reload-or-try-restart":
+		member := map[string]string{"start": "StartUnit", "stop": "StopUnit", "restart": "RestartUnit", "reload-or-try-restart": "ReloadOrTryRestartUnit"}[action]
+		reply, err = sm.manager(member, "ss", unit, "replace")
+		if err == errDBusLost {
+			return sm.fallback.UnitAction(action, unit)
+		}
+		if err == nil {
+			err = sm.waitJob(reply)
+		}
+		if err == nil && action != "stop" {
+			if state, _ := sm.ActiveState(unit); state == "failed" {
+				err = fmt.Errorf("job for '%s' failed", unit)
+			}
+		}
+	case "enable":
+		_, err = sm.manager("EnableUnitFiles", "asbb", []interface{}{unit}, false, false)
+		if err == errDBusLost {
+			return sm.fallback.UnitAction(action, unit)
+		}
+		if err == nil {
+			_, err = sm.manager("Reload", "")
+		}
+	case "disable":
+		_, err = sm.manager("DisableUnitFiles", "asb", []interface{}{unit}, false)
+		if err == errDBusLost {
+			return sm.fallback.UnitAction(action, unit)
+		}
+		if err == nil {
+			_, err = sm.manager("Reload", "")
+		}
+	default:
+		err = fmt.Errorf("unsupported action '%s'", action)
+	}
+	DebugLog("UnitAction - D-Bus %s '%s' : '%+v'", action, unit, err)
+	return err
+}
+
+// waitJob waits until the job returned by a unit action has finished
+func (sm *DBusServiceManager) waitJob(reply []interface{}) error {
+	if len(reply) != 1 {
+		return fmt.Errorf("unexpected reply '%v'", reply)
+	}
+	job, _ := reply[0].(string)
+	end := time.Now().Add(dbusJobWait)
+	for time.Now().Before(end) {
+		if _, err := sm.getProperty(systemdBus, job, systemdJob, "State"); err != nil {
+			if _, ok := err.(dbusErr); ok {
+				// job object removed, job finished
+				return nil
+			}
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("timeout waiting for job '%s'", job)
+}
+
+// ResetFailed resets the 'failed' state of all units
+func (sm *DBusServiceManager) ResetFailed() error {
+	_, err := sm.manager("ResetFailed", "")
+	if err == errDBusLost {
+		return sm.fallback.ResetFailed()
+	}
+	return err
+}
+
+// unitProperty returns a property of a unit, the unit is loaded, if needed
+func (sm *DBusServiceManager) unitProperty(unit, iface, property string) (interface{}, error) {
+	reply, err := sm.manager("LoadUnit", "s", unit)
+	if err != nil {
+		return nil, err
+	}
+	if len(reply) != 1 {
+		return nil, fmt.Errorf("unexpected reply '%v'", reply)
+	}
+	unitPath, _ := reply[0].(string)
+	return sm.getProperty(systemdBus, unitPath, iface, property)
+}
+
+// ActiveState returns the property 'ActiveState' of a unit
+func (sm *DBusServiceManager) ActiveState(unit string) (string, error) {
+	state, err := sm.unitProperty(unit, systemdUnit, "ActiveState")
+	if err == errDBusLost {
+		return sm.fallback.ActiveState(unit)
+	}
+	if _, ok := err.(dbusErr); ok {
+		// like 'systemctl is-active' for not loadable units
+		return "inactive", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	str, _ := state.(string)
+	return str, nil
+}
+
+// UnitFileState returns the unit file state of a unit
+func (sm *DBusServiceManager) UnitFileState(unit string) (string, error) {
+	reply, err := sm.manager("GetUnitFileState", "s", unit)
+	if err == errDBusLost {
+		return sm.fallback.UnitFileState(unit)
+	}
+	if _, ok := err.(dbusErr); ok {
+		return "not-found", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(reply) != 1 {
+		return "", fmt.Errorf("unexpected reply '%v'", reply)
+	}
+	state, _ := reply[0].(string)
+	return state, nil
+}
+
+// UnitFiles returns the names of all available unit files
+func (sm *DBusServiceManager) UnitFiles() ([]string, error) {
+	units := []string{}
+	reply, err := sm.manager("ListUnitFiles", "")
+	if err == errDBusLost {
+		return sm.fallback.UnitFiles()
+	}
+	if err != nil {
+		return units, err
+	}
+	if len(reply) != 1 {
+		return units, fmt.Errorf("unexpected reply '%v'", reply)
+	}
+	list, _ := reply[0].([]interface{})
+	for _, entry := range list {
+		if fields, ok := entry.([]interface{}); ok && len(fields) == 2 {
+			file, _ := fields[0].(string)
+			units = append(units, path.Base(file))
+		}
+	}
+	return units, nil
+}
+
+// SystemState returns the property 'SystemState' of systemd
+func (sm *DBusServiceManager) SystemState() (string, error) {
+	state, err := sm.getProperty(systemdBus, systemdPath, systemdManager, "SystemState")
+	if err == errDBusLost {
+		return sm.fallback.SystemState()
+	}
+	if err != nil {
+		return "", err
+	}
+	str, _ := state.(string)
+	if str != "running" {
+		return str, fmt.Errorf("system state is '%s'", str)
+	}
+	return str, nil
+}
+
+// Virtualization returns the property 'Virtualization' of systemd. A
+// chroot is detected by the command systemd-detect-virt
+func (sm *DBusServiceManager) Virtualization(opt string) (bool, string, error) {
+	if opt == "-r" {
+		return sm.fallback.Virtualization(opt)
+	}
+	prop, err := sm.getProperty(systemdBus, systemdPath, systemdManager, "Virtualization")
+	if err == errDBusLost {
+		return sm.fallback.Virtualization(opt)
+	}
+	if err != nil {
+		return false, "", err
+	}
+	vtype, _ := prop.(string)
+	if vtype == "" || (opt == "-v" && containerTypes[vtype]) || (opt == "-c" && !containerTypes[vtype]) {
+		return false, "none", fmt.Errorf("no virtualization detected")
+	}
+	return true, vtype, nil
+}
+
+// LoggedInUsers returns the user ids of the currently logged in users
+func (sm *DBusServiceManager) LoggedInUsers() ([]string, error) {
+	uID := []string{}
+	reply, err := sm.call(logindBus, logindPath, logindManager, "ListUsers", "")
+	if useFallback(err) {
+		return sm.fallback.LoggedInUsers()
+	}
+	if err != nil {
+		return uID, err
+	}
+	if len(reply) != 1 {
+		return uID, fmt.Errorf("unexpected reply '%v'", reply)
+	}
+	list, _ := reply[0].([]interface{})
+	for _, entry := range list {
+		if fields, ok := entry.([]interface{}); ok && len(fields) == 3 {
+			if uid, ok := fields[0].(uint32); ok {
+				uID = append(uID, strconv.FormatUint(uint64(uid), 10))
+			}
+		}
+	}
+	return uID, nil
+}
+
+// unitInterface returns the D-Bus interface of the type specific
+// properties of a unit, e.g. 'org.freedesktop.systemd1.Slice'
+func unitInterface(unit string) string {
+	ext := strings.TrimPrefix(path.Ext(unit), ".")
+	if ext == "" {
+		return systemdUnit
+	}
+	return "org.freedesktop.systemd1." + strings.ToUpper(ext[:1]) + ext[1:]
+}
+
+// UnitProperty returns the value of a property of a unit. The maximal
+// value of an unsigned number is reported as 'infinity' like systemctl
+// does
+func (sm *DBusServiceManager) UnitProperty(unit, property string) (string, error) {
+	val, err := sm.unitProperty(unit, unitInterface(unit), property)
+	if err == errDBusLost {
+		return sm.fallback.UnitProperty(unit, property)
+	}
+	if err != nil {
+		return "", err
+	}
+	if num, ok := val.(uint64); ok && num == math.MaxUint64 {
+		return "infinity", nil
+	}
+	return fmt.Sprintf("%v", val), nil
+}
+
+// SetUnitProperty sets a numeric property of a unit for runtime.
+// 'infinity' is the maximal value of an unsigned number
+func (sm *DBusServiceManager) SetUnitProperty(unit, property, value string) error {
+	num := uint64(math.MaxUint64)
+	if value != "infinity" {
+		var err error
+		if num, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return fmt.Errorf("value '%s' of property '%s' is not a number", value, property)
+		}
+	}
+	props := []interface{}{[]interface{}{property, dbusVariant{"t", num}}}
+	_, err := sm.manager("SetUnitProperties", "sba(sv)", unit, true, props)
+	if err == errDBusLost {
+		return sm.fallback.SetUnitProperty(unit, property, value)
+	}
+	return err
+}
+
+// TunedProfile returns the active tuned profile
+func (sm *DBusServiceManager) TunedProfile() (string, error) {
+	reply, err := sm.call(tunedBus, tunedPath, tunedControl, "active_profile", "")
+	if useFallback(err) {
+		return sm.fallback.TunedProfile()
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(reply) != 1 {
+		return "", fmt.Errorf("unexpected reply '%v'", reply)
+	}
+	profile, _ := reply[0].(string)
+	return profile, nil
+}
+
+// TunedSwitchProfile activates a tuned profile
+func (sm *DBusServiceManager) TunedSwitchProfile(profile string) error {
+	reply, err := sm.call(tunedBus, tunedPath, tunedControl, "switch_profile", "s", profile)
+	if useFallback(err) {
+		return sm.fallback.TunedSwitchProfile(profile)
+	}
+	if err != nil {
+		return err
+	}
+	if len(reply) == 1 {
+		if res, ok := reply[0].([]interface{}); ok && len(res) == 2 && res[0] == false {
+			return fmt.Errorf("%v", res[1])
+		}
+	}
+	return nil
+}
+
+// TunedOff switches off the active tuned profile
+func (sm *DBusServiceManager) TunedOff() error {
+	reply, err := sm.call(tunedBus, tunedPath, tunedControl, "disable", "")
+	if useFallback(err) {
+		return sm.fallback.TunedOff()
+	}
+	if err != nil {
+		return err
+	}
+	if len(reply) == 1 && reply[0] == false {
+		return fmt.Errorf("tuned failed to switch off the active profile")
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package system
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// useFakeBus switches to a DBusServiceManager connected to a fake D-Bus
+// service for the duration of the test
+func useFakeBus(t *testing.T) *fakeBus {
+	fb, address := startFakeBus(t)
+	sm, err := NewDBusServiceManager(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldSM := serviceManager
+	SetServiceManager(sm)
+	t.Cleanup(func() {
+		sm.conn.Close()
+		SetServiceManager(oldSM)
+	})
+	return fb
+}
+
+func TestGetServiceManager(t *testing.T) {
+	oldSM := serviceManager
+	oldBus := dbusSystemBus
+	defer func() {
+		SetServiceManager(oldSM)
+		dbusSystemBus = oldBus
+	}()
+	t.Setenv("DBUS_SYSTEM_BUS_ADDRESS", "")
+
+	// no system bus available - commands are used
+	dbusSystemBus = "unix:path=/nonexistent/system_bus_socket"
+	SetServiceManager(nil)
+	if _, ok := GetServiceManager().(ExecServiceManager); !ok {
+		t.Errorf("expected the command based service manager, got '%T'", GetServiceManager())
+	}
+
+	_, address := startFakeBus(t)
+	dbusSystemBus = address
+	SetServiceManager(nil)
+	sm, ok := GetServiceManager().(*DBusServiceManager)
+	if !ok {
+		t.Fatalf("expected the D-Bus service manager, got '%T'", GetServiceManager())
+	}
+	sm.conn.Close()
+}
+
+func TestDBusSystemState(t *testing.T) {
+	fb := useFakeBus(t)
+	if state, err := GetSystemState(); err != nil || state != "running" {
+		t.Errorf("got '%s', '%v'", state, err)
+	}
+	if running, err := IsSystemRunning(); !running || err != nil {
+		t.Errorf("got '%v', '%v'", running, err)
+	}
+	fb.sysState = "degraded"
+	if running, err := IsSystemRunning(); !running || err != nil {
+		t.Errorf("got '%v', '%v'", running, err)
+	}
+	fb.sysState = "starting"
+	if !SystemctlIsStarting() {
+		t.Error("expected system state 'starting'")
+	}
+	fb.sysState = "stopping"
+	if running, err := IsSystemRunning(); running || err == nil {
+		t.Errorf("got '%v', '%v'", running, err)
+	}
+	// units are not started, if the system is not running
+	if err := SystemctlStart("saptune.service"); err == nil {
+		t.Error("expected an error, system is not running")
+	}
+	if fb.active["saptune.service"] != "inactive" {
+		t.Error("unit started, but system is not running")
+	}
+}
+
+func TestDBusUnits(t *testing.T) {
+	fb := useFakeBus(t)
+	if err := SystemctlEnableStart("saptune.service"); err != nil {
+		t.Fatal(err)
+	}
+	if fb.unitFiles["saptune.service"] != "enabled" || fb.active["saptune.service"] != "active" {
+		t.Errorf("saptune.service not enabled and started - '%s', '%s'", fb.unitFiles["saptune.service"], fb.active["saptune.service"])
+	}
+	if !strings.Contains(strings.Join(fb.calls, " "), "EnableUnitFiles Reload") {
+		t.Errorf("missing reload after enable - '%v'", fb.calls)
+	}
+	if enabled, err := SystemctlIsEnabled("saptune.service"); !enabled || err != nil {
+		t.Errorf("got '%v', '%v'", enabled, err)
+	}
+	if running, err := SystemctlIsRunning("saptune.service"); !running || err != nil {
+		t.Errorf("got '%v', '%v'", running, err)
+	}
+	if state, err := SystemctlIsActive("saptune.service"); state != "active" || err != nil {
+		t.Errorf("got '%s', '%v'", state, err)
+	}
+	if err := SystemctlRestart("saptune.service"); err != nil {
+		t.Error(err)
+	}
+	if err := SystemctlReloadTryRestart("saptune.service"); err != nil {
+		t.Error(err)
+	}
+	if err := SystemctlDisableStop("saptune.service"); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, _ := SystemctlIsEnabled("saptune.service"); enabled {
+		t.Error("saptune.service still enabled")
+	}
+	if state, err := SystemctlIsActive("saptune.service"); state != "inactive" || err == nil {
+		t.Errorf("got '%s', '%v'", state, err)
+	}
+	if err := SystemctlResetFailed(); err != nil {
+		t.Error(err)
+	}
+
+	// 'static' counts as enabled
+	if enabled, _ := SystemctlIsEnabled("sapconf.service"); !enabled {
+		t.Error("static unit sapconf.service not reported as enabled")
+	}
+	if !IsSapconfActive("sapconf.service") {
+		t.Error("sapconf.service not reported as active")
+	}
+	// unknown unit
+	if enabled, err := SystemctlIsEnabled("unknown.service"); enabled || err != nil {
+		t.Errorf("got '%v', '%v'", enabled, err)
+	}
+	if running, err := SystemctlIsRunning("unknown.service"); running || err != nil {
+		t.Errorf("got '%v', '%v'", running, err)
+	}
+	if err := SystemctlStart("unknown.service"); err == nil {
+		t.Error("expected an error for an unknown unit")
+	}
+
+	if !IsServiceAvailable("tuned") || !IsServiceAvailable("saptune.service") || IsServiceAvailable("unknown") {
+		t.Error("wrong result of IsServiceAvailable")
+	}
+	avail := GetAvailServices()
+	if len(avail) != 3 || avail["sapconf.service"] == "" {
+		t.Errorf("wrong available services '%v'", avail)
+	}
+}
+
+func TestDBusVirtualization(t *testing.T) {
+	fb := useFakeBus(t)
+	if virt, vtype, err := SystemdDetectVirt("-v"); !virt || vtype != "kvm" || err != nil {
+		t.Errorf("got '%v', '%s', '%v'", virt, vtype, err)
+	}
+	if virt, vtype, err := SystemdDetectVirt("-c"); virt || vtype != "none" || err == nil {
+		t.Errorf("got '%v', '%s', '%v'", virt, vtype, err)
+	}
+	fb.virt = "docker"
+	if virt, vtype, err := SystemdDetectVirt("-c"); !virt || vtype != "docker" || err != nil {
+		t.Errorf("got '%v', '%s', '%v'", virt, vtype, err)
+	}
+	if virt, vtype, _ := SystemdDetectVirt(""); !virt || vtype != "docker" {
+		t.Errorf("got '%v', '%s'", virt, vtype)
+	}
+	fb.virt = ""
+	if virt, vtype, err := SystemdDetectVirt(""); virt || vtype != "none" || err == nil {
+		t.Errorf("got '%v', '%s', '%v'", virt, vtype, err)
+	}
+}
+
+func TestDBusLogins(t *testing.T) {
+	fb := useFakeBus(t)
+	logins := GetCurrentLogins()
+	if len(logins) != 1 || logins[0] != "65534" {
+		t.Errorf("wrong logged in users '%v'", logins)
+	}
+	if val := GetTasksMax("65534"); val != "infinity" {
+		t.Errorf("expected 'infinity', got '%s'", val)
+	}
+	if err := SetTasksMax("65534", "18446744073709"); err != nil {
+		t.Fatal(err)
+	}
+	if val := GetTasksMax("65534"); val != "18446744073709" {
+		t.Errorf("expected '18446744073709', got '%s'", val)
+	}
+	if err := SetTasksMax("65534", "infinity"); err != nil || fb.tasksMax != math.MaxUint64 {
+		t.Errorf("got '%d', '%v'", fb.tasksMax, err)
+	}
+	if err := SetTasksMax("65534", "many"); err == nil {
+		t.Error("expected an error for a non numeric value")
+	}
+}
+
+func TestDBusTuned(t *testing.T) {
+	fb := useFakeBus(t)
+	if profile := GetTunedAdmProfile(); profile != "balanced" {
+		t.Errorf("expected 'balanced', got '%s'", profile)
+	}
+	if err := TunedAdmProfile("saptune"); err != nil || fb.tunedProf != "saptune" {
+		t.Errorf("got '%s', '%v'", fb.tunedProf, err)
+	}
+	if err := TunedAdmProfile("unknown"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+	if err := TunedAdmOff(); err != nil || fb.tunedProf != "" {
+		t.Errorf("got '%s', '%v'", fb.tunedProf, err)
+	}
+
+	// tuned not on the bus - tuned-adm is used
+	fb.tunedOnBus = false
+	oldTunedAdmCmd := tunedAdmCmd
+	defer func() { tunedAdmCmd = oldTunedAdmCmd }()
+	tunedAdmCmd = "/usr/bin/false"
+	if err := TunedAdmProfile("saptune"); err == nil {
+		t.Error("expected an error from the command fallback")
+	}
+	tunedAdmCmd = "/usr/bin/true"
+	if err := TunedAdmOff(); err != nil {
+		t.Error(err)
+	}
+	if profile := GetTunedAdmProfile(); profile != "" {
+		t.Errorf("expected an empty profile, got '%s'", profile)
+	}
+}
+
+func TestDBusReconnect(t *testing.T) {
+	fb := useFakeBus(t)
+	sm := serviceManager.(*DBusServiceManager)
+	// bus restarted, the connection is re-established
+	fb.dropConnections()
+	if state, err := sm.ActiveState("tuned.service"); err != nil || state != "active" {
+		t.Errorf("got '%s', '%v'", state, err)
+	}
+	if sm.isLost() {
+		t.Error("connection reported as lost, but reconnect should succeed")
+	}
+
+	// bus gone, the commands are used
+	fb.listener.Close()
+	fb.dropConnections()
+	if _, err := sm.SystemState(); err == errDBusLost {
+		t.Error("expected the command based fallback, got a D-Bus error")
+	}
+	if !sm.isLost() {
+		t.Error("connection not reported as lost")
+	}
+	if _, ok := GetServiceManager().(ExecServiceManager); !ok {
+		t.Errorf("expected the command based service manager, got '%T'", GetServiceManager())
+	}
+}
@@ -165,6 +165,25 @@ func parseSysctlConfFile(file string) (map[string]sysctlEntry, error) {
 	return entries, nil
 }
 
+// GetSysctlKeys returns the names of all readable and writable sysctl
+// parameters found in /proc/sys, sorted by name. Read-only entries like
+// kernel.random.uuid or fs.file-nr can not be tuned
+func GetSysctlKeys() []string {
+	keys := []string{}
+	procSys := "/proc/sys"
+	_ = filepath.Walk(procSys, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			// skip unreadable directories
+			return nil
+		}
+		if info.Mode().IsRegular() && info.Mode().Perm()&0444 != 0 && info.Mode().Perm()&0200 != 0 {
+			keys = append(keys, strings.Replace(strings.TrimPrefix(file, procSys+"/"), "/", ".", -1))
+		}
+		return nil
+	})
+	return keys
+}
+
 // GetSysctlString read a sysctl key and return the string value.
 func GetSysctlString(parameter string) (string, error) {
 	val, err := ioutil.ReadFile(path.Join("/proc/sys", strings.Replace(parameter, ".", "/", -1)))
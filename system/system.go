@@ -49,7 +49,7 @@ func IsUserRoot() bool {
 // GetSolutionSelector returns the architecture string
 // needed to select the supported set os solutions
 func GetSolutionSelector() string {
-	solutionSelector := runtime.GOARCH
+	solutionSelector := systemArch
 	if IsPagecacheAvailable() {
 		solutionSelector = solutionSelector + "_PC"
 	}
@@ -127,6 +127,16 @@ func CheckForPattern(file, pattern string) bool {
 	return strings.Contains(string(content), pattern)
 }
 
+// IsInList checks, if the entry is part of the list
+func IsInList(entry string, list []string) bool {
+	for _, item := range list {
+		if item == entry {
+			return true
+		}
+	}
+	return false
+}
+
 // CalledFrom returns the name and the line number of the calling source file
 func CalledFrom() string {
 	ret := ""
@@ -190,19 +200,25 @@ func OutIsTerm(writer *os.File) bool {
 }
 
 // InitOut initializes the various output methodes
-// currently only json and screen are supported
+// supported are screen, json and the report formats csv, yaml, markdown
+// and html
 func InitOut(logSwitch map[string]string) {
-	if GetFlagVal("format") == "json" {
-		// if writing json format, switch off
-		// the stdout and stderr output of the log messages
-		logSwitch["verbose"] = "off"
+	if !StructuredOut() {
+		return
+	}
+	// switch off the stdout output of the log messages
+	logSwitch["verbose"] = "off"
+	if !IsReportFormat() {
+		// json output, switch off the stderr output of the log
+		// messages too. Error messages of the report formats are
+		// still written to stderr
 		logSwitch["error"] = "off"
-		// switch off stdout
-		if os.Getenv("SAPTUNE_JDEBUG") != "on" {
-			os.Stdout, _ = os.Open(os.DevNull)
-		}
-		jInit()
 	}
+	// switch off stdout
+	if os.Getenv("SAPTUNE_JDEBUG") != "on" {
+		os.Stdout, _ = os.Open(os.DevNull)
+	}
+	jInit()
 }
 
 // SwitchOffOut disables stdout and stderr
@@ -296,15 +312,19 @@ func GetHWIdentity(info string) (string, error) {
 
 	switch info {
 	case "vendor":
-		if runtime.GOARCH == "ppc64le" {
+		if isIBMArch() {
 			fix = "IBM"
 		} else {
 			fileName = fmt.Sprintf("%s/board_vendor", DmiID)
 		}
 	case "model":
-		if runtime.GOARCH == "ppc64le" {
+		switch systemArch {
+		case ArchPPC64LE:
 			fileName = "/sys/firmware/devicetree/base/model"
-		} else {
+		case ArchS390X:
+			// IBM Z has no DMI information, use the machine type
+			fix = getSysinfoType(sysinfoFile)
+		default:
 			fileName = fmt.Sprintf("%s/product_name", DmiID)
 		}
 	}
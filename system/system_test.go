@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"reflect"
 	"strings"
 	"syscall"
 	"testing"
@@ -54,7 +55,7 @@ func TestGetSolutionSelector(t *testing.T) {
 	solSelector := GetSolutionSelector()
 	t.Logf("architecture is '%s'\n", solSelector)
 	//if solSelector != "amd64" && solSelector != "amd64_PC" && solSelector != "ppc64le" && solSelector != "ppc64le_PC" && solSelector != "TRAVIS_TODO" {
-	if solSelector != "amd64" && solSelector != "amd64_PC" && solSelector != "ppc64le" && solSelector != "ppc64le_PC" {
+	if solSelector != "amd64" && solSelector != "amd64_PC" && solSelector != "ppc64le" && solSelector != "ppc64le_PC" && solSelector != "arm64" && solSelector != "arm64_PC" && solSelector != "s390x" && solSelector != "s390x_PC" {
 		t.Errorf("Test failed, solSelector '%s'", solSelector)
 	}
 }
@@ -158,6 +159,13 @@ func TestCheckForPattern(t *testing.T) {
 	}
 }
 
+func TestIsInList(t *testing.T) {
+	list := []string{"1680803", "SAP_BOBJ"}
+	if !IsInList("SAP_BOBJ", list) || IsInList("SAP", list) || IsInList("1680803", nil) {
+		t.Errorf("wrong result for list '%v'", list)
+	}
+}
+
 func TestCalledFrom(t *testing.T) {
 	val := CalledFrom()
 	if !strings.Contains(val, "testing.go") {
@@ -207,9 +215,9 @@ func TestErrorExit(t *testing.T) {
 	//lint:ignore ST1018 Unicode control characters are expected here
 	checkOut(t, txt, "[31m[1mERROR: Colored Hallo direct[22m[0m\n")
 
-	SaptuneLock()
+	SaptuneLock(LockExclusive, 0)
 	// to reach ErrorExit("saptune currently in use, try later ...", 11)
-	SaptuneLock()
+	SaptuneLock(LockExclusive, 0)
 	ErrorExit("", 0)
 	if tstRetErrorExit != 0 {
 		t.Errorf("error exit should be '0' and NOT '%v'\n", tstRetErrorExit)
@@ -239,6 +247,53 @@ func TestErrorExit(t *testing.T) {
 	}
 }
 
+func TestJRun(t *testing.T) {
+	oldOSExit := OSExit
+	defer func() { OSExit = oldOSExit }()
+	OSExit = tstosExit
+	oldErrorExitOut := ErrorExitOut
+	defer func() { ErrorExitOut = oldErrorExitOut }()
+	ErrorExitOut = tstErrorExitOut
+	buffer := bytes.Buffer{}
+	tstwriter = &buffer
+	oldArgs := os.Args
+	oldStdout := os.Stdout
+
+	entry := JRun([]string{"version"}, func() {
+		if GetFlagVal("format") != "json" || CliArg(1) != "version" {
+			t.Errorf("wrong command line '%v'", os.Args)
+		}
+		Jcollect("3")
+	})
+	if entry.Cmd != "version" || entry.CmdRet != 0 || !reflect.DeepEqual(entry.CmdResult, versResults{ConfVers: "3"}) {
+		t.Errorf("wrong json entry '%+v'", entry)
+	}
+
+	reached := false
+	entry = JRun([]string{"note", "verify", "1680803"}, func() {
+		SaptuneLock(LockExclusive, 0)
+		ErrorExit("The parameters listed above have deviated from the specified note.", 4)
+		reached = true
+	})
+	if reached {
+		t.Error("command continued after ErrorExit")
+	}
+	if entry.Cmd != "note verify" || entry.CmdRet != 4 {
+		t.Errorf("wrong json entry '%+v'", entry)
+	}
+	if isOwnLock() {
+		t.Error("lock not released after the command")
+	}
+	// the process settings are restored
+	if !reflect.DeepEqual(os.Args, oldArgs) || os.Stdout != oldStdout || GetFlagVal("format") == "json" {
+		t.Errorf("process settings not restored - '%v'", os.Args)
+	}
+	ErrorExit("", 6)
+	if tstRetErrorExit != 6 {
+		t.Errorf("error exit should be '6' and NOT '%v'\n", tstRetErrorExit)
+	}
+}
+
 func TestOutIsTerm(t *testing.T) {
 	pipeName := "/tmp/saptune_pipe_tst"
 	syscall.Mkfifo(pipeName, 0666)
@@ -397,6 +452,8 @@ func TestStripComments(t *testing.T) {
 }
 
 func TestGetVirtStatus(t *testing.T) {
+	defer SetServiceManager(GetServiceManager())
+	SetServiceManager(ExecServiceManager{})
 	oldSystemddvCmd := systemddvCmd
 	// test: virtualization found
 	systemddvCmd = path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/testdata/systemdDVOK")
@@ -22,6 +22,11 @@ type Operator string
 // RegexKeyOperatorValue breaks up a line into key, operator, value.
 var RegexKeyOperatorValue = regexp.MustCompile(`([\w.+_-]+)\s*([<=>]+)\s*["']*(.*?)["']*$`)
 
+// RegexSeverity breaks up a line of the section [severity] into parameter,
+// operator and severity. The parameter names are the names shown in the
+// verify table, so ':' is allowed, e.g. 'grub:numa_balancing'
+var RegexSeverity = regexp.MustCompile(`^([\w.:+_-]+)\s*(=)\s*(\w+)$`)
+
 // regKey gives the parameter part of the line from the note definition file
 var regKey = regexp.MustCompile(`(.*)\s*[<=>]+\s*["']*.*?["']*$`)
 
@@ -46,9 +51,13 @@ type INIEntry struct {
 }
 
 // INIFile contains all key-value pairs of an INI file.
+// The section [severity] classifies the parameters of the other sections,
+// so its entries are not part of AllValues and KeyValue, but only of
+// Severities (parameter -> severity)
 type INIFile struct {
-	AllValues []INIEntry
-	KeyValue  map[string]map[string]INIEntry
+	AllValues  []INIEntry
+	KeyValue   map[string]map[string]INIEntry
+	Severities map[string]string
 }
 
 // GetINIFileDescriptiveName return the descriptive name of the Note
@@ -74,7 +83,12 @@ func splitLineIntoKOV(curSection, line string) []string {
 	var kov []string
 	if curSection == "rpm" {
 		kov = splitRPM(line)
-	} else if curSection == "ArchX86" || curSection == "ArchPPC64LE" {
+	} else if curSection == "severity" {
+		kov = RegexSeverity.FindStringSubmatch(line)
+		if kov == nil {
+			system.WarningLog("[severity] section contains a line with wrong syntax - '%v', skipping entry. Please check", line)
+		}
+	} else if curSection == "ArchX86" || curSection == "ArchPPC64LE" || curSection == "ArchARM64" || curSection == "ArchS390X" {
 		kov = []string{"", "", "", line}
 	} else {
 		// check for unsupported '/' in the parameter name
@@ -174,8 +188,7 @@ func ParseINI(input string) *INIFile {
 		if line[0] == '[' {
 			// Save previous section, if valid
 			if currentSection != "" && !skipSection {
-				ret.KeyValue[currentSection] = currentEntriesMap
-				ret.AllValues = append(ret.AllValues, currentEntriesArray...)
+				saveSectionEntries(ret, currentSection, currentEntriesArray, currentEntriesMap)
 			}
 
 			// Start a new section
@@ -268,8 +281,7 @@ func ParseINI(input string) *INIFile {
 	if reminder != "" {
 		// Save previous section
 		if currentSection != "" {
-			ret.KeyValue[currentSection] = currentEntriesMap
-			ret.AllValues = append(ret.AllValues, currentEntriesArray...)
+			saveSectionEntries(ret, currentSection, currentEntriesArray, currentEntriesMap)
 		}
 		// write the reminder section data
 		currentEntriesArray, currentEntriesMap, currentSection = writeReminderSectionData(reminder)
@@ -277,12 +289,40 @@ func ParseINI(input string) *INIFile {
 
 	// Save last section
 	if currentSection != "" {
-		ret.KeyValue[currentSection] = currentEntriesMap
-		ret.AllValues = append(ret.AllValues, currentEntriesArray...)
+		saveSectionEntries(ret, currentSection, currentEntriesArray, currentEntriesMap)
 	}
 	return ret
 }
 
+// saveSectionEntries saves the entries of a section. The entries of the
+// section [severity] are no parameters, they only go to Severities
+func saveSectionEntries(ini *INIFile, section string, entries []INIEntry, entriesMap map[string]INIEntry) {
+	if section == "severity" {
+		if ini.Severities == nil {
+			ini.Severities = make(map[string]string)
+		}
+		for _, entry := range entries {
+			ini.Severities[entry.Key] = entry.Value
+		}
+		return
+	}
+	mergeSectionEntries(ini, section, entriesMap)
+	ini.AllValues = append(ini.AllValues, entries...)
+}
+
+// mergeSectionEntries saves the entries of a section. If the section is
+// used more than once in the file (e.g. [block] and [block:blkpat=sda]),
+// the entries of the later section take precedence
+func mergeSectionEntries(ini *INIFile, section string, entries map[string]INIEntry) {
+	if _, ok := ini.KeyValue[section]; !ok {
+		ini.KeyValue[section] = entries
+		return
+	}
+	for key, entry := range entries {
+		ini.KeyValue[section][key] = entry
+	}
+}
+
 // blkInfoNeeded - collect of block device info only needed, if a block
 // section exists or if a blk* tag is used in any section
 func blkInfoNeeded(sectFields []string) bool {
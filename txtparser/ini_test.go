@@ -224,6 +224,38 @@ func TestParseINI(t *testing.T) {
 	_ = system.CopyFile("/etc/os-release_OrG", "/etc/os-release")
 }
 
+func TestMergeSectionEntries(t *testing.T) {
+	ini := ParseINI("[sysctl]\nvm.swappiness = 60\nkernel.shmmni = 4096\n\n[vm]\nKSM=0\n\n[sysctl]\nvm.swappiness = 10\n")
+	if len(ini.KeyValue["sysctl"]) != 2 || ini.KeyValue["sysctl"]["vm.swappiness"].Value != "10" || ini.KeyValue["sysctl"]["kernel.shmmni"].Value != "4096" {
+		t.Errorf("got '%+v'", ini.KeyValue["sysctl"])
+	}
+	if len(ini.AllValues) != 4 {
+		t.Errorf("got '%+v'", ini.AllValues)
+	}
+}
+
+func TestSeveritySection(t *testing.T) {
+	// the section [severity] before and after the classified parameters
+	for _, content := range []string{
+		"[severity]\nvm.swappiness = critical\nKSM = informational\n\n[sysctl]\nvm.swappiness = 10\n\n[vm]\nKSM = 0\n",
+		"[sysctl]\nvm.swappiness = 10\n\n[vm]\nKSM = 0\n\n[severity]\nvm.swappiness = critical\nKSM = informational\n",
+	} {
+		ini := ParseINI(content)
+		if len(ini.AllValues) != 2 || ini.AllValues[0].Section != "sysctl" || ini.AllValues[1].Section != "vm" {
+			t.Errorf("wrong values '%+v'", ini.AllValues)
+		}
+		if _, ok := ini.KeyValue["severity"]; ok || ini.KeyValue["sysctl"]["vm.swappiness"].Value != "10" {
+			t.Errorf("wrong sections '%+v'", ini.KeyValue)
+		}
+		if !reflect.DeepEqual(ini.Severities, map[string]string{"vm.swappiness": "critical", "KSM": "informational"}) {
+			t.Errorf("wrong severities '%+v'", ini.Severities)
+		}
+	}
+	if ini := ParseINI("[sysctl]\nvm.swappiness = 10\n"); ini.Severities != nil {
+		t.Errorf("unexpected severities '%+v'", ini.Severities)
+	}
+}
+
 func TestGetINIFileDescriptiveName(t *testing.T) {
 	str := GetINIFileDescriptiveName(fileName)
 	if str != descName {
@@ -256,6 +288,29 @@ func TestGetINIFileVersionSectionRefs(t *testing.T) {
 	}
 }
 
+func TestGetINIFileVersionSectionList(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "deps_test.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer os.Remove(fmt.Sprintf("%s/version_%s.run", saptuneSectionDir, path.Base(tmpFile.Name())))
+	content := "[version]\nVERSION=1\nDATE=01.01.2023\nDESCRIPTION=dependency test\nREFERENCES=\nREQUIRES=1805750, 2382421\nCONFLICTS=\"2684254\"\n\n[sysctl]\nvm.swappiness=10\n"
+	if _, err := tmpFile.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	tmpFile.Close()
+	if list := GetINIFileVersionSectionList(tmpFile.Name(), "requires"); !reflect.DeepEqual(list, []string{"1805750", "2382421"}) {
+		t.Errorf("got: '%+v', expected: '[1805750 2382421]'\n", list)
+	}
+	if list := GetINIFileVersionSectionList(tmpFile.Name(), "conflicts"); !reflect.DeepEqual(list, []string{"2684254"}) {
+		t.Errorf("got: '%+v', expected: '[2684254]'\n", list)
+	}
+	if list := GetINIFileVersionSectionList(tmpFile.Name(), "supersedes"); len(list) != 0 {
+		t.Errorf("got: '%+v', expected an empty list\n", list)
+	}
+}
+
 func TestGetINIFileVersionSectionEntry(t *testing.T) {
 	str := GetINIFileVersionSectionEntry(fileName, "reference")
 	if str != noteRefs {
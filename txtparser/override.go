@@ -0,0 +1,135 @@
+package txtparser
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// OverrideDateFormat is the date format of the '@expires' metadata
+const OverrideDateFormat = "2006-01-02"
+
+// regOverrideMeta matches the metadata comments of an override file
+// like '# @reason: HANA sizing ticket 4711'
+var regOverrideMeta = regexp.MustCompile(`^#\s*@(reason|expires|approved-by)\s*:\s*(.*?)\s*$`)
+
+// OverrideMeta contains the metadata of a parameter in an override file,
+// which documents the deviation from the Note
+type OverrideMeta struct {
+	Reason     string
+	Expires    string
+	ApprovedBy string
+	Section    string // section of the parameter in the override file
+}
+
+// counter to control the warning message for wrong expiry dates
+var wrongExpiryCnt = map[string]int{}
+
+// GetOverrideMeta reads the parameter metadata from the override file of
+// the Note
+func GetOverrideMeta(ID string) map[string]OverrideMeta {
+	return GetOverrideMetaFile(path.Join(OverrideTuningSheets, ID))
+}
+
+// GetOverrideMetaFile reads the parameter metadata from the given
+// override file. A missing file results in an empty map
+func GetOverrideMetaFile(fileName string) map[string]OverrideMeta {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return make(map[string]OverrideMeta)
+	}
+	return ParseOverrideMeta(string(content))
+}
+
+// ParseOverrideMeta parses the metadata comments ('# @reason:',
+// '# @expires:' and '# @approved-by:') of an override file.
+// The metadata belongs to the next parameter following the comments.
+func ParseOverrideMeta(input string) map[string]OverrideMeta {
+	metas := make(map[string]OverrideMeta)
+	meta := OverrideMeta{}
+	section := ""
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case len(line) == 0:
+			continue
+		case line[0] == '[':
+			// metadata does not cross section boundaries
+			section = strings.Split(strings.Trim(line, "[]"), ":")[0]
+			meta = OverrideMeta{}
+			continue
+		case line[0] == '#':
+			matches := regOverrideMeta.FindStringSubmatch(line)
+			if len(matches) != 3 {
+				continue
+			}
+			switch matches[1] {
+			case "reason":
+				meta.Reason = matches[2]
+			case "expires":
+				meta.Expires = matches[2]
+			case "approved-by":
+				meta.ApprovedBy = matches[2]
+			}
+			continue
+		}
+		matches := RegexKeyOperatorValue.FindStringSubmatch(line)
+		if len(matches) == 4 && meta != (OverrideMeta{}) {
+			meta.Section = section
+			metas[matches[1]] = meta
+		}
+		meta = OverrideMeta{}
+	}
+	return metas
+}
+
+// LookupOverrideMeta returns the metadata of the parameter. Parameters of
+// the block section are expanded by the block device name (IO_SCHEDULER_sda),
+// so the metadata of the original parameter is used for them
+func LookupOverrideMeta(metas map[string]OverrideMeta, key string) (OverrideMeta, bool) {
+	if meta, ok := metas[key]; ok {
+		return meta, true
+	}
+	if idx := strings.LastIndex(key, "_"); idx > 0 {
+		if meta, ok := metas[key[:idx]]; ok && meta.Section == "block" {
+			return meta, true
+		}
+	}
+	return OverrideMeta{}, false
+}
+
+// IsExpired returns true, if the expiry date of the override is in the past.
+// The override is valid until the end of the expiry day.
+func (meta OverrideMeta) IsExpired(now time.Time) bool {
+	if meta.Expires == "" {
+		return false
+	}
+	expires, err := time.ParseInLocation(OverrideDateFormat, meta.Expires, now.Location())
+	if err != nil {
+		if wrongExpiryCnt[meta.Expires] < 1 {
+			system.WarningLog("wrong expiry date '%s' in override file, expected format 'YYYY-MM-DD'", meta.Expires)
+			wrongExpiryCnt[meta.Expires] = wrongExpiryCnt[meta.Expires] + 1
+		}
+		return false
+	}
+	return !now.Before(expires.AddDate(0, 0, 1))
+}
+
+// String returns the metadata as readable text
+func (meta OverrideMeta) String() string {
+	txt := []string{}
+	if meta.Reason != "" {
+		txt = append(txt, fmt.Sprintf("reason '%s'", meta.Reason))
+	}
+	if meta.ApprovedBy != "" {
+		txt = append(txt, fmt.Sprintf("approved by %s", meta.ApprovedBy))
+	}
+	if meta.Expires != "" {
+		txt = append(txt, fmt.Sprintf("expires %s", meta.Expires))
+	}
+	return strings.Join(txt, ", ")
+}
@@ -0,0 +1,83 @@
+package txtparser
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+var overrideMetaContent = `[version]
+# SAP-NOTE=4711 CATEGORY=LINUX VERSION=1 DATE=01.01.2026 NAME="override"
+
+[sysctl]
+# @reason: HANA sizing ticket 4711
+# @expires: 2027-03-31
+# @approved-by: jdoe
+vm.swappiness = 20
+# a normal comment
+kernel.shmmni = 32768
+# @reason: dangling metadata
+
+[block]
+# @reason: NVMe devices
+IO_SCHEDULER = none
+`
+
+func TestParseOverrideMeta(t *testing.T) {
+	metas := ParseOverrideMeta(overrideMetaContent)
+	if len(metas) != 2 {
+		t.Fatalf("expected 2 entries, got '%+v'", metas)
+	}
+	exp := OverrideMeta{Reason: "HANA sizing ticket 4711", Expires: "2027-03-31", ApprovedBy: "jdoe", Section: "sysctl"}
+	if metas["vm.swappiness"] != exp {
+		t.Errorf("got '%+v', expected '%+v'", metas["vm.swappiness"], exp)
+	}
+	if _, ok := metas["kernel.shmmni"]; ok {
+		t.Errorf("unexpected metadata for 'kernel.shmmni'")
+	}
+	if metas["IO_SCHEDULER"].Reason != "NVMe devices" {
+		t.Errorf("got '%+v'", metas["IO_SCHEDULER"])
+	}
+
+	// block device parameter
+	if meta, ok := LookupOverrideMeta(metas, "IO_SCHEDULER_sda"); !ok || meta.Reason != "NVMe devices" {
+		t.Errorf("got '%+v', '%v'", meta, ok)
+	}
+	if _, ok := LookupOverrideMeta(metas, "vm.swappiness_sda"); ok {
+		t.Error("unexpected metadata for 'vm.swappiness_sda'")
+	}
+
+	// metadata from file
+	tmpFile := path.Join(os.TempDir(), "override_meta_test")
+	if err := ioutil.WriteFile(tmpFile, []byte(overrideMetaContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile)
+	if metas = GetOverrideMetaFile(tmpFile); len(metas) != 2 {
+		t.Errorf("expected 2 entries, got '%+v'", metas)
+	}
+	if metas = GetOverrideMetaFile("/not_avail"); len(metas) != 0 {
+		t.Errorf("expected no entries, got '%+v'", metas)
+	}
+}
+
+func TestOverrideMetaIsExpired(t *testing.T) {
+	meta := OverrideMeta{Reason: "test", Expires: "2027-03-31", ApprovedBy: "jdoe"}
+	if meta.IsExpired(time.Date(2027, 3, 31, 23, 59, 0, 0, time.Local)) {
+		t.Error("override should be valid until the end of the expiry day")
+	}
+	if !meta.IsExpired(time.Date(2027, 4, 1, 0, 0, 0, 0, time.Local)) {
+		t.Error("override should be expired")
+	}
+	if (OverrideMeta{Reason: "test"}).IsExpired(time.Now()) {
+		t.Error("override without expiry date should not expire")
+	}
+	if (OverrideMeta{Expires: "31.03.2027"}).IsExpired(time.Date(2030, 1, 1, 0, 0, 0, 0, time.Local)) {
+		t.Error("wrong expiry date should not expire")
+	}
+	if meta.String() != "reason 'test', approved by jdoe, expires 2027-03-31" {
+		t.Errorf("got '%s'", meta.String())
+	}
+}
@@ -98,7 +98,6 @@ func GetOverrides(filetype, ID string) (bool, *INIFile) {
 
 // readVersionSection read content of [version] section from config file
 func readVersionSection(fileName string) ([]string, bool, error) {
-	skipSection := false
 	staging := false
 	chkVersEntries := map[string]bool{"missing": false, "found": false, "isNew": false, "isOld": false, "skip": false, "mandVers": false, "mandDate": false, "mandDesc": false, "mandRefs": false}
 	vsection := []string{}
@@ -116,7 +115,61 @@ func readVersionSection(fileName string) ([]string, bool, error) {
 	if err != nil {
 		return vsection, chkVersEntries["isNew"], err
 	}
-	for _, line := range strings.Split(string(content), "\n") {
+	vsection = scanVersionSection(string(content), chkVersEntries)
+
+	err = chkVersEntriesResult(fileName, chkVersEntries)
+	// if processing a note from the staging area do NOT store the version
+	// info in the 'run' file to not override the section info from the
+	// working area
+	if !chkVersEntries["missing"] && !staging {
+		err = storeVersionRunInfo(versRun, vsection, chkVersEntries["isNew"])
+	}
+	return vsection, chkVersEntries["isNew"], err
+}
+
+// ChkVersionSection checks the version section of a Note definition file
+// for a missing section or missing mandatory fields.
+// The section info is NOT stored for re-use, so it can be used for files
+// outside of the working area
+func ChkVersionSection(fileName string) error {
+	chkVersEntries := map[string]bool{"missing": false, "found": false, "isNew": false, "isOld": false, "skip": false, "mandVers": false, "mandDate": false, "mandDesc": false, "mandRefs": false}
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	scanVersionSection(string(content), chkVersEntries)
+	_ = chkVersEntriesResult(fileName, chkVersEntries)
+	if chkVersEntries["missing"] {
+		return fmt.Errorf("missing or wrong version section in Note definition file '%s'", fileName)
+	}
+	return nil
+}
+
+// LintVersionSection checks the version section of the content of a Note
+// definition file without logging. It returns, if the section is available,
+// if an old style version header is used and the missing mandatory fields
+// of the new style version section
+func LintVersionSection(content string) (bool, bool, []string) {
+	chkVersEntries := map[string]bool{"missing": false, "found": false, "isNew": false, "isOld": false, "skip": false, "mandVers": false, "mandDate": false, "mandDesc": false, "mandRefs": false}
+	scanVersionSection(content, chkVersEntries)
+	missing := []string{}
+	if chkVersEntries["isOld"] {
+		return chkVersEntries["found"], true, missing
+	}
+	for _, ent := range []struct{ name, key string }{{"VERSION", "mandVers"}, {"DATE", "mandDate"}, {"DESCRIPTION", "mandDesc"}, {"REFERENCES", "mandRefs"}} {
+		if !chkVersEntries[ent.key] {
+			missing = append(missing, ent.name)
+		}
+	}
+	return chkVersEntries["found"], false, missing
+}
+
+// scanVersionSection returns the lines of the version section and sets the
+// results of the syntax check in 'chkVersEntries'
+func scanVersionSection(content string, chkVersEntries map[string]bool) []string {
+	skipSection := false
+	vsection := []string{}
+	for _, line := range strings.Split(content, "\n") {
 		line = strings.TrimSpace(line)
 		if len(line) == 0 {
 			// skip empty lines
@@ -151,15 +204,7 @@ func readVersionSection(fileName string) ([]string, bool, error) {
 		}
 		vsection = append(vsection, line)
 	}
-
-	err = chkVersEntriesResult(fileName, chkVersEntries)
-	// if processing a note from the staging area do NOT store the version
-	// info in the 'run' file to not override the section info from the
-	// working area
-	if !chkVersEntries["missing"] && !staging {
-		err = storeVersionRunInfo(versRun, vsection, chkVersEntries["isNew"])
-	}
-	return vsection, chkVersEntries["isNew"], err
+	return vsection
 }
 
 // getVersionRunInfo reads content of stored version section info from
@@ -291,6 +336,16 @@ func GetINIFileVersionSectionRefs(fileName string) []string {
 	return rval
 }
 
+// GetINIFileVersionSectionList returns the list of Note IDs from the
+// dependency fields (REQUIRES, CONFLICTS, SUPERSEDES) of the version section
+// of the Note configuration file. The IDs are separated by comma or blank
+func GetINIFileVersionSectionList(fileName, entryName string) []string {
+	entry := GetINIFileVersionSectionEntry(fileName, entryName)
+	return strings.FieldsFunc(entry, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
 // selectVersionExpression returns the regular expression needed to
 // identify a specific version section entry
 func selectVersionExpression(newStyle bool, entry, file string) string {
@@ -325,6 +380,12 @@ func newStyleVersionSectionEntry(entryName string) string {
 		re = `^\s*DATE\s*=\s*"?(\d{2}[-./]{1}\d{2}[-./]{1}\d{4}|\d{4}[-./]{1}\d{2}[-./]{1}\d{2})"?.*$`
 	case "name", "description":
 		re = `^\s*DESCRIPTION\s*=\s*"?(.*)"?$`
+	case "requires":
+		re = `^\s*REQUIRES\s*=\s*"?(.*?)"?$`
+	case "conflicts":
+		re = `^\s*CONFLICTS\s*=\s*"?(.*?)"?$`
+	case "supersedes":
+		re = `^\s*SUPERSEDES\s*=\s*"?(.*?)"?$`
 	}
 	return re
 }
@@ -2,6 +2,7 @@ package txtparser
 
 import (
 	"github.com/SUSE/saptune/system"
+	"io/ioutil"
 	"os"
 	"path"
 	"reflect"
@@ -63,3 +64,22 @@ func TestStoreSectionInfo(t *testing.T) {
 
 	defer os.RemoveAll(saptuneSectionDir)
 }
+
+func TestChkVersionSection(t *testing.T) {
+	tstFile := "/tmp/saptune_tst_chkversion.conf"
+	defer os.Remove(tstFile)
+	for content, valid := range map[string]bool{
+		"[version]\n# SAP-NOTE=tstNote CATEGORY=extra VERSION=0 DATE=04.06.2019 NAME=\"test note\"\n[sysctl]\nvm.swappiness = 10\n": true,
+		"[version]\nVERSION=1\nDATE=04.06.2019\nDESCRIPTION=test note\nREFERENCES=\n[sysctl]\n":                                     true,
+		"[version]\nVERSION=1\nDATE=04.06.2019\n[sysctl]\n":                                                                         false,
+		"[sysctl]\nvm.swappiness = 10\n": false,
+	} {
+		_ = ioutil.WriteFile(tstFile, []byte(content), 0644)
+		if err := ChkVersionSection(tstFile); (err == nil) != valid {
+			t.Errorf("content '%s': got error '%v', expected valid '%v'\n", content, err, valid)
+		}
+	}
+	if err := ChkVersionSection("/file_does_not_exist"); err == nil {
+		t.Errorf("expected an error for a missing file\n")
+	}
+}
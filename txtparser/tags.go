@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/SUSE/saptune/system"
 	"regexp"
-	"runtime"
 	"strings"
 )
 
@@ -103,11 +102,8 @@ func chkOsTags(tagField string, secFields []string) bool {
 // chkArchTags checks if the arch section tag is valid or not
 func chkArchTags(tagField string, secFields []string) bool {
 	ret := true
-	chkArch := runtime.GOARCH
-	if chkArch == "amd64" {
-		// map architecture to 'uname -i' output
-		chkArch = "x86_64"
-	}
+	// map architecture to 'uname -i' output
+	chkArch := system.ArchUname(system.GetArch())
 	if tagField != chkArch {
 		// arch does not match
 		system.InfoLog("system architecture '%s' in section definition '%v' does not match the architecture of the running system '%s'. Skipping whole section with all lines till next valid section definition", tagField, secFields, chkArch)
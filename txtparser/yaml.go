@@ -0,0 +1,297 @@
+package txtparser
+
+// Implement a parser for the small YAML subset used by the saptune host
+// profile: nested mappings, lists of scalars (block and flow style),
+// quoted and unquoted scalars and literal block scalars ('|' and '|-').
+// All scalar values are returned as strings.
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a single line of the YAML input
+type yamlLine struct {
+	num    int    // line number, starting with 1
+	indent int    // number of leading blanks
+	text   string // line content without the leading blanks
+}
+
+// ParseYAMLFile reads a YAML file and parses the content into a map.
+// Values are strings, []string or map[string]interface{}
+func ParseYAMLFile(fileName string) (map[string]interface{}, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return ParseYAML(string(content))
+}
+
+// ParseYAML parses YAML text into a map.
+// Values are strings, []string or map[string]interface{}
+func ParseYAML(input string) (map[string]interface{}, error) {
+	lines := []yamlLine{}
+	for num, line := range strings.Split(strings.Replace(input, "\r\n", "\n", -1), "\n") {
+		if strings.HasPrefix(line, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", num+1)
+		}
+		text := strings.TrimLeft(line, " ")
+		lines = append(lines, yamlLine{num: num + 1, indent: len(line) - len(text), text: strings.TrimRight(text, " \t")})
+	}
+	pos := nextYAMLLine(lines, 0)
+	if pos < len(lines) && lines[pos].text == "---" {
+		pos = nextYAMLLine(lines, pos+1)
+	}
+	if pos >= len(lines) {
+		return make(map[string]interface{}), nil
+	}
+	result, pos, err := parseYAMLMapping(lines, pos, lines[pos].indent)
+	if err != nil {
+		return nil, err
+	}
+	if pos = nextYAMLLine(lines, pos); pos < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[pos].num)
+	}
+	return result, nil
+}
+
+// nextYAMLLine returns the position of the next line, which is neither
+// empty nor a comment
+func nextYAMLLine(lines []yamlLine, pos int) int {
+	for pos < len(lines) && (lines[pos].text == "" || strings.HasPrefix(lines[pos].text, "#")) {
+		pos++
+	}
+	return pos
+}
+
+// parseYAMLMapping parses the mapping starting at line 'pos' with the given
+// indentation
+func parseYAMLMapping(lines []yamlLine, pos, indent int) (map[string]interface{}, int, error) {
+	mapping := make(map[string]interface{})
+	for pos = nextYAMLLine(lines, pos); pos < len(lines); pos = nextYAMLLine(lines, pos) {
+		line := lines[pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, pos, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+		key, rest, err := splitYAMLKey(line)
+		if err != nil {
+			return nil, pos, err
+		}
+		if _, ok := mapping[key]; ok {
+			return nil, pos, fmt.Errorf("line %d: duplicate key '%s'", line.num, key)
+		}
+		pos++
+		switch {
+		case rest == "|" || rest == "|-":
+			mapping[key], pos = parseYAMLBlock(lines, pos, indent, rest == "|")
+		case rest != "":
+			if mapping[key], err = parseYAMLValue(rest, line.num); err != nil {
+				return nil, pos, err
+			}
+		default:
+			next := nextYAMLLine(lines, pos)
+			switch {
+			case next < len(lines) && lines[next].indent >= indent && isYAMLListItem(lines[next].text):
+				mapping[key], pos, err = parseYAMLList(lines, next, lines[next].indent)
+			case next < len(lines) && lines[next].indent > indent:
+				mapping[key], pos, err = parseYAMLMapping(lines, next, lines[next].indent)
+			default:
+				mapping[key] = ""
+			}
+			if err != nil {
+				return nil, pos, err
+			}
+		}
+	}
+	return mapping, pos, nil
+}
+
+// parseYAMLList parses a list of scalars starting at line 'pos' with the
+// given indentation
+func parseYAMLList(lines []yamlLine, pos, indent int) ([]string, int, error) {
+	list := []string{}
+	for pos = nextYAMLLine(lines, pos); pos < len(lines); pos = nextYAMLLine(lines, pos) {
+		line := lines[pos]
+		if line.indent != indent || !isYAMLListItem(line.text) {
+			break
+		}
+		item := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		value, err := parseYAMLScalar(item, line.num)
+		if err != nil {
+			return nil, pos, err
+		}
+		list = append(list, value)
+		pos++
+	}
+	return list, pos, nil
+}
+
+// parseYAMLBlock collects the lines of a literal block scalar, which are
+// indented deeper than the key
+func parseYAMLBlock(lines []yamlLine, pos, indent int, keepNewline bool) (string, int) {
+	block := []string{}
+	blockIndent := -1
+	for ; pos < len(lines); pos++ {
+		line := lines[pos]
+		if line.text == "" {
+			block = append(block, "")
+			continue
+		}
+		if line.indent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = line.indent
+		}
+		if line.indent < blockIndent {
+			break
+		}
+		block = append(block, strings.Repeat(" ", line.indent-blockIndent)+line.text)
+	}
+	// empty lines at the end belong to the following content
+	for len(block) > 0 && block[len(block)-1] == "" {
+		block = block[:len(block)-1]
+		pos--
+	}
+	text := strings.Join(block, "\n")
+	if keepNewline && text != "" {
+		text = text + "\n"
+	}
+	return text, pos
+}
+
+// splitYAMLKey splits a mapping line into key and the remaining value
+func splitYAMLKey(line yamlLine) (string, string, error) {
+	text := line.text
+	if isYAMLListItem(text) {
+		return "", "", fmt.Errorf("line %d: unexpected list item", line.num)
+	}
+	sep := strings.Index(text, ": ")
+	if sep < 0 {
+		if !strings.HasSuffix(text, ":") {
+			return "", "", fmt.Errorf("line %d: missing ':' after key", line.num)
+		}
+		sep = len(text) - 1
+	}
+	key, err := parseYAMLScalar(strings.TrimSpace(text[:sep]), line.num)
+	if err != nil {
+		return "", "", err
+	}
+	if key == "" {
+		return "", "", fmt.Errorf("line %d: empty key", line.num)
+	}
+	return key, strings.TrimSpace(text[sep+1:]), nil
+}
+
+// parseYAMLValue parses the value following a key, which is either a flow
+// list, an empty flow mapping or a scalar
+func parseYAMLValue(value string, num int) (interface{}, error) {
+	value = stripYAMLComment(value)
+	if value == "{}" {
+		return make(map[string]interface{}), nil
+	}
+	if !strings.HasPrefix(value, "[") {
+		return parseYAMLScalar(value, num)
+	}
+	if !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("line %d: missing ']' at end of list", num)
+	}
+	list := []string{}
+	content := strings.TrimSpace(value[1 : len(value)-1])
+	if content == "" {
+		return list, nil
+	}
+	for _, item := range strings.Split(content, ",") {
+		entry, err := parseYAMLScalar(strings.TrimSpace(item), num)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, entry)
+	}
+	return list, nil
+}
+
+// parseYAMLScalar returns the value of a quoted or unquoted scalar
+func parseYAMLScalar(value string, num int) (string, error) {
+	value = stripYAMLComment(value)
+	switch {
+	case strings.HasPrefix(value, `"`):
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("line %d: wrong quoting of value %s", num, value)
+		}
+		return unquoted, nil
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", fmt.Errorf("line %d: wrong quoting of value %s", num, value)
+		}
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1), nil
+	case value == "~" || value == "null":
+		return "", nil
+	}
+	return value, nil
+}
+
+// stripYAMLComment removes a trailing comment from an unquoted value or
+// after the closing quote
+func stripYAMLComment(value string) string {
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+		quote := value[0:1]
+		for i := 1; i < len(value); i++ {
+			if quote == `"` && value[i] == '\\' {
+				i++
+				continue
+			}
+			if value[i:i+1] == quote {
+				if quote == "'" && i+1 < len(value) && value[i+1] == '\'' {
+					i++
+					continue
+				}
+				return value[:i+1]
+			}
+		}
+		return value
+	}
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return value
+}
+
+// isYAMLListItem checks, if the line is a list item
+func isYAMLListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// YAMLScalar returns the value quoted, if needed to be read back unchanged
+func YAMLScalar(value string) string {
+	switch strings.ToLower(value) {
+	case "", "~", "null", "true", "false", "yes", "no", "on", "off":
+		return strconv.Quote(value)
+	}
+	if strings.TrimSpace(value) != value || strings.ContainsAny(value[0:1], "-?:,[]{}#&*!|>'\"%@`") || strings.Contains(value, ": ") || strings.Contains(value, " #") || strings.HasSuffix(value, ":") || strings.ContainsAny(value, "\n\t") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// YAMLBlock returns a literal block scalar of the text, indented by 'indent'
+// blanks
+func YAMLBlock(text string, indent int) string {
+	header := "|"
+	if !strings.HasSuffix(text, "\n") {
+		header = "|-"
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = strings.Repeat(" ", indent) + line
+		}
+	}
+	return header + "\n" + strings.Join(lines, "\n") + "\n"
+}
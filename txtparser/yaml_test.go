@@ -0,0 +1,83 @@
+package txtparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+var yamlProfile = `---
+# saptune host profile
+solutions:
+  - HANA
+notes: [2382421, "1656250"]   # additional notes
+order:
+- 941735
+- '1771258'
+overrides:
+  "2382421": |
+    [sysctl]
+    # keep comment
+    net.ipv4.tcp_slow_start_after_idle = 0
+
+    net.ipv4.tcp_tw_recycle = 0
+  "1656250": |-
+    [mem]
+    ShmFileSystemSizeMB = 0
+staging: false
+color_scheme: "full-red-noncmpl" # verify colors
+empty:
+`
+
+func TestParseYAML(t *testing.T) {
+	got, err := ParseYAML(yamlProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"solutions": []string{"HANA"},
+		"notes":     []string{"2382421", "1656250"},
+		"order":     []string{"941735", "1771258"},
+		"overrides": map[string]interface{}{
+			"2382421": "[sysctl]\n# keep comment\nnet.ipv4.tcp_slow_start_after_idle = 0\n\nnet.ipv4.tcp_tw_recycle = 0\n",
+			"1656250": "[mem]\nShmFileSystemSizeMB = 0",
+		},
+		"staging":      "false",
+		"color_scheme": "full-red-noncmpl",
+		"empty":        "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: '%+v', expected: '%+v'\n", got, want)
+	}
+
+	got, err = ParseYAML("")
+	if err != nil || len(got) != 0 {
+		t.Errorf("got: '%+v', '%v', expected an empty map\n", got, err)
+	}
+
+	for _, wrong := range []string{"solutions:\n  - HANA\n   - NETWEAVER\n", "solutions HANA\n", "a: 1\na: 2\n", "\ta: 1\n", "a: [1, 2\n", "a: \"open\n", "- HANA\n"} {
+		if _, err := ParseYAML(wrong); err == nil {
+			t.Errorf("expected an error for '%s'\n", wrong)
+		}
+	}
+}
+
+func TestYAMLScalar(t *testing.T) {
+	for value, want := range map[string]string{"HANA": "HANA", "941735": "941735", "": `""`, "true": `"true"`, "- a": `"- a"`, "a: b": `"a: b"`, "a #b": `"a #b"`, " a": `" a"`} {
+		if got := YAMLScalar(value); got != want {
+			t.Errorf("got: '%s', expected: '%s'\n", got, want)
+		}
+		// read back unchanged
+		if got, err := ParseYAML("key: " + YAMLScalar(value)); err != nil || got["key"] != value {
+			t.Errorf("got: '%v', '%v', expected: '%s'\n", got["key"], err, value)
+		}
+	}
+}
+
+func TestYAMLBlock(t *testing.T) {
+	for _, text := range []string{"[sysctl]\nvm.swappiness = 10\n", "[sysctl]\n\n  indented = 1", ""} {
+		got, err := ParseYAML("key: " + YAMLBlock(text, 2) + "next: 1\n")
+		if err != nil || got["key"] != text || got["next"] != "1" {
+			t.Errorf("got: '%+v', '%v', expected: '%s'\n", got, err, text)
+		}
+	}
+}